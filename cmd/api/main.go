@@ -1,24 +1,46 @@
 package main
 
 import (
+	"context"
+	"expvar"
 	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/team-xquare/deployment-platform/internal/app/addon"
 	"github.com/team-xquare/deployment-platform/internal/app/application"
+	"github.com/team-xquare/deployment-platform/internal/app/audit"
 	"github.com/team-xquare/deployment-platform/internal/app/auth"
+	"github.com/team-xquare/deployment-platform/internal/app/deployment"
 	"github.com/team-xquare/deployment-platform/internal/app/github"
+	"github.com/team-xquare/deployment-platform/internal/app/gitops"
+	"github.com/team-xquare/deployment-platform/internal/app/member"
+	"github.com/team-xquare/deployment-platform/internal/app/outbox"
 	"github.com/team-xquare/deployment-platform/internal/app/project"
+	"github.com/team-xquare/deployment-platform/internal/app/tier"
 	"github.com/team-xquare/deployment-platform/internal/app/user"
 	"github.com/team-xquare/deployment-platform/internal/pkg/config"
 	"github.com/team-xquare/deployment-platform/internal/pkg/db/mysql"
 	"github.com/team-xquare/deployment-platform/internal/pkg/db/redis"
 	"github.com/team-xquare/deployment-platform/internal/pkg/middleware"
+	"github.com/team-xquare/deployment-platform/internal/pkg/utils/crypto"
 
 	"github.com/gin-gonic/gin"
 )
 
 func main() {
 	config.Load()
+	if err := config.Validate(); err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+
+	keyring, err := crypto.NewKeyring(config.SecretsEncryptionKeys())
+	if err != nil {
+		log.Fatalf("Invalid SECRETS_ENCRYPTION_KEYS: %v", err)
+	}
 
 	redisClient, err := redis.NewConnection()
 	if err != nil {
@@ -38,25 +60,59 @@ func main() {
 	githubRepo := mysql.NewGitHubRepository(mysqlDB)
 	applicationRepo := mysql.NewApplicationRepository(mysqlDB)
 	addonRepo := mysql.NewAddonRepository(mysqlDB)
+	memberRepo := mysql.NewMemberRepository(mysqlDB)
+	deploymentRepo := mysql.NewDeploymentRepository(mysqlDB)
+	outboxRepo := mysql.NewOutboxRepository(mysqlDB)
+	gitopsRepo := mysql.NewGitOpsRepository(mysqlDB)
+	tierRepo := mysql.NewTierRepository(mysqlDB)
+	auditRepo := mysql.NewAuditRepository(mysqlDB)
+	transactor := mysql.NewTransactor(mysqlDB)
 
 	authService := auth.NewService(authRepo, userRepo)
 	userService := user.NewService(userRepo)
-	projectService := project.NewService(projectRepo, githubRepo)
+	memberService := member.NewService(memberRepo, userRepo, transactor)
+	projectService := project.NewService(projectRepo, githubRepo, memberService)
 	githubService := github.NewService(githubRepo)
-	applicationService := application.NewService(applicationRepo, githubService)
-	addonService := addon.NewService(addonRepo, githubService)
+	outboxService := outbox.NewService(outboxRepo)
+	gitopsService := gitops.NewService(gitopsRepo, memberService, githubService)
+	deploymentService := deployment.NewService(deploymentRepo, memberService)
+	tierService := tier.NewService(tierRepo, projectRepo, userRepo, memberService)
+	auditService := audit.NewService(auditRepo, memberService)
+	addonService := addon.NewService(addonRepo, projectRepo, transactor, keyring, githubService, gitopsService, memberService, deploymentService, tierService, outboxService)
+	applicationService := application.NewService(applicationRepo, projectRepo, transactor, keyring, githubService, gitopsService, memberService, deploymentService, addonService, tierService, outboxService)
+
+	githubService.OnPush(applicationService)
+	githubService.OnRepositoryChange(applicationService)
+	githubService.OnWorkflowRun(deploymentService)
+	githubService.OnCheckRun(deploymentService)
+
+	outboxWorker := outbox.NewWorker(outboxRepo, githubService, keyring, outbox.OptionsFromConfig())
+	outboxWorker.RegisterHandler(outbox.AggregateDeployment, deploymentService)
+	outboxWorker.Start()
 
 	authHandler := auth.NewHandler(authService)
 	userHandler := user.NewHandler(userService)
 	projectHandler := project.NewHandler(projectService)
+	memberHandler := member.NewHandler(memberService)
 	githubHandler := github.NewHandler(githubService)
 	applicationHandler := application.NewHandler(applicationService)
+	deploymentHandler := deployment.NewHandler(deploymentService)
 	addonHandler := addon.NewHandler(addonService)
+	gitopsHandler := gitops.NewHandler(gitopsService)
+	tierHandler := tier.NewHandler(tierService)
+	auditHandler := audit.NewHandler(auditService)
 
 	router := gin.New()
+	// Client IPs in the audit log come from X-Forwarded-For only when it was
+	// set by a configured proxy
+	if err := router.SetTrustedProxies(config.TrustedProxies()); err != nil {
+		log.Fatalf("Invalid TRUSTED_PROXIES: %v", err)
+	}
 	router.Use(gin.Recovery())
 	router.Use(gin.Logger())
 	router.Use(middleware.CORS())
+	router.Use(middleware.RequestID())
+	router.Use(auditService.Middleware())
 	router.Use(middleware.ErrorHandler())
 
 	api := router.Group("/api/v1")
@@ -64,13 +120,43 @@ func main() {
 		authHandler.RegisterRoutes(api)
 		userHandler.RegisterRoutes(api)
 		projectHandler.RegisterRoutes(api)
+		memberHandler.RegisterRoutes(api)
 		githubHandler.RegisterRoutes(api)
 		applicationHandler.RegisterRoutes(api)
+		deploymentHandler.RegisterRoutes(api)
 		addonHandler.RegisterRoutes(api)
+		gitopsHandler.RegisterRoutes(api)
+		tierHandler.RegisterRoutes(api)
+		auditHandler.RegisterRoutes(api)
+
+		// Process metrics published with expvar, such as audit_events_failed
+		api.GET("/admin/vars", middleware.Auth(), middleware.Admin(), gin.WrapH(expvar.Handler()))
+	}
+
+	server := &http.Server{
+		Addr:    ":" + config.AppConfig.AppPort,
+		Handler: router,
 	}
 
-	log.Printf("Starting server on port %s", config.AppConfig.AppPort)
-	if err := router.Run(":" + config.AppConfig.AppPort); err != nil {
-		log.Fatalf("Failed to start server: %v", err)
+	go func() {
+		log.Printf("Starting server on port %s", config.AppConfig.AppPort)
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatalf("Failed to start server: %v", err)
+		}
+	}()
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+
+	log.Println("Shutting down server...")
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if err := server.Shutdown(ctx); err != nil {
+		log.Printf("Server forced to shutdown: %v", err)
+	}
+	if err := outboxWorker.Shutdown(ctx); err != nil {
+		log.Printf("Outbox worker forced to shutdown: %v", err)
 	}
 }
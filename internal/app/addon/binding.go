@@ -0,0 +1,361 @@
+package addon
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/team-xquare/deployment-platform/internal/pkg/utils/crypto"
+	"github.com/team-xquare/deployment-platform/internal/pkg/utils/errors"
+)
+
+var envPrefixPattern = regexp.MustCompile(`^[A-Z][A-Z0-9_]*$`)
+
+// newCredentials generates the addon user of a binding. Passwords are hex so
+// they can be used in connection URLs without escaping.
+func newCredentials(applicationID uint) (username, password string, err error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	return fmt.Sprintf("app_%d", applicationID), hex.EncodeToString(b), nil
+}
+
+// addonHost is the in-cluster DNS name of an addon. Each project is deployed
+// to the namespace named after its slug.
+func addonHost(projectSlug, name string) string {
+	return name + "." + projectSlug + ".svc.cluster.local"
+}
+
+// databaseName is the database created for bound applications. Addon names may
+// contain hyphens, which not every engine accepts in unquoted identifiers.
+func databaseName(addonName string) string {
+	return strings.ReplaceAll(addonName, "-", "_")
+}
+
+// connectionEnv returns the variables an application uses to reach the addon,
+// split into plain values and secrets.
+func connectionEnv(engine *Engine, addon *Addon, binding *Binding, host string) (env, secrets map[string]string) {
+	port := strconv.Itoa(engine.Port)
+	address := host + ":" + port
+	user := url.UserPassword(binding.Username, binding.Password)
+	database := databaseName(addon.Name)
+
+	env = map[string]string{}
+	secrets = map[string]string{}
+
+	switch engine.Type {
+	case "mysql", "postgresql":
+		env["DB_HOST"] = host
+		env["DB_PORT"] = port
+		env["DB_NAME"] = database
+		env["DB_USERNAME"] = binding.Username
+		secrets["DB_PASSWORD"] = binding.Password
+		secrets["DATABASE_URL"] = (&url.URL{Scheme: engine.Type, User: user, Host: address, Path: "/" + database}).String()
+	case "redis":
+		env["REDIS_HOST"] = host
+		env["REDIS_PORT"] = port
+		env["REDIS_USERNAME"] = binding.Username
+		secrets["REDIS_PASSWORD"] = binding.Password
+		secrets["REDIS_URL"] = (&url.URL{Scheme: "redis", User: user, Host: address}).String()
+	case "mongodb":
+		env["MONGODB_HOST"] = host
+		env["MONGODB_PORT"] = port
+		env["MONGODB_DATABASE"] = database
+		env["MONGODB_USERNAME"] = binding.Username
+		secrets["MONGODB_PASSWORD"] = binding.Password
+		secrets["MONGODB_URI"] = (&url.URL{Scheme: "mongodb", User: user, Host: address, Path: "/" + database}).String()
+	case "rabbitmq":
+		env["RABBITMQ_HOST"] = host
+		env["RABBITMQ_PORT"] = port
+		env["RABBITMQ_USERNAME"] = binding.Username
+		secrets["RABBITMQ_PASSWORD"] = binding.Password
+		secrets["RABBITMQ_URL"] = (&url.URL{Scheme: "amqp", User: user, Host: address, Path: "/"}).String()
+	}
+
+	if binding.EnvPrefix != "" {
+		env = withPrefix(binding.EnvPrefix, env)
+		secrets = withPrefix(binding.EnvPrefix, secrets)
+	}
+
+	return env, secrets
+}
+
+func withPrefix(prefix string, values map[string]string) map[string]string {
+	prefixed := make(map[string]string, len(values))
+	for name, value := range values {
+		prefixed[prefix+"_"+name] = value
+	}
+	return prefixed
+}
+
+// Bind attaches an addon of the project to the application and redeploys the
+// addon with the binding's new user. The caller authorizes the user for the
+// application and must run Bind inside the transaction that redeploys it.
+func (s *Service) Bind(ctx context.Context, userID, projectID, applicationID uint, req CreateBindingRequest) (*BindingResponse, error) {
+	addon, err := s.repo.FindByID(ctx, req.AddonID)
+	if err != nil {
+		return nil, err
+	}
+	if addon.ProjectID != projectID {
+		return nil, errors.BadRequest("Addon belongs to a different project")
+	}
+	if findEngine(addon.Type) == nil {
+		return nil, errors.BadRequest("Addons of type " + addon.Type + " cannot be bound")
+	}
+	if req.EnvPrefix != "" && !envPrefixPattern.MatchString(req.EnvPrefix) {
+		return nil, errors.BadRequest("Environment variable prefix must be upper case letters, digits or underscores")
+	}
+
+	existing, err := s.repo.FindBinding(ctx, addon.ID, applicationID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, errors.BadRequest("Addon is already bound to the application")
+	}
+
+	username, password, err := newCredentials(applicationID)
+	if err != nil {
+		return nil, errors.Internal("Failed to generate addon credentials")
+	}
+	password, err = s.keyring.Encrypt(password)
+	if err != nil {
+		return nil, errors.Internal("Failed to encrypt addon credentials")
+	}
+	binding := &Binding{
+		AddonID:       addon.ID,
+		ApplicationID: applicationID,
+		EnvPrefix:     req.EnvPrefix,
+		Username:      username,
+		Password:      password,
+	}
+
+	// Two bindings must not set the same variable
+	_, env, secrets, err := s.bindingEnv(ctx, binding)
+	if err != nil {
+		return nil, err
+	}
+	bindings, err := s.repo.FindBindingsByApplicationID(ctx, applicationID)
+	if err != nil {
+		return nil, err
+	}
+	for _, other := range bindings {
+		otherAddon, otherEnv, otherSecrets, err := s.bindingEnv(ctx, other)
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range append(envNames(env), envNames(secrets)...) {
+			if _, ok := otherEnv[name]; ok {
+				return nil, errors.BadRequest(name + " is already set by addon " + otherAddon.Name + "; bind with a different env_prefix")
+			}
+			if _, ok := otherSecrets[name]; ok {
+				return nil, errors.BadRequest(name + " is already set by addon " + otherAddon.Name + "; bind with a different env_prefix")
+			}
+		}
+	}
+
+	if err := s.repo.SaveBinding(ctx, binding); err != nil {
+		return nil, err
+	}
+	if err := s.triggerAddonDeployment(ctx, addon, "apply", userID); err != nil {
+		return nil, err
+	}
+
+	return toBindingResponse(addon, binding, env, secrets), nil
+}
+
+// GetBindings lists the addons bound to the application. The caller authorizes
+// the user for the application.
+func (s *Service) GetBindings(ctx context.Context, applicationID uint) ([]*BindingResponse, error) {
+	bindings, err := s.repo.FindBindingsByApplicationID(ctx, applicationID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*BindingResponse, len(bindings))
+	for i, binding := range bindings {
+		addon, env, secrets, err := s.bindingEnv(ctx, binding)
+		if err != nil {
+			return nil, err
+		}
+		responses[i] = toBindingResponse(addon, binding, env, secrets)
+	}
+
+	return responses, nil
+}
+
+// ConnectionEnv returns the connection variables of every addon bound to the
+// application, split into plain values and secrets.
+func (s *Service) ConnectionEnv(ctx context.Context, applicationID uint) (env, secrets map[string]string, err error) {
+	bindings, err := s.repo.FindBindingsByApplicationID(ctx, applicationID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	env = map[string]string{}
+	secrets = map[string]string{}
+	for _, binding := range bindings {
+		_, bindingEnv, bindingSecrets, err := s.bindingEnv(ctx, binding)
+		if err != nil {
+			return nil, nil, err
+		}
+		for name, value := range bindingEnv {
+			env[name] = value
+		}
+		for name, value := range bindingSecrets {
+			secrets[name] = value
+		}
+	}
+
+	return env, secrets, nil
+}
+
+// Unbind removes a binding of the application and redeploys the addon without
+// the binding's user. Like Bind, it runs inside the caller's transaction.
+func (s *Service) Unbind(ctx context.Context, userID, applicationID, bindingID uint) error {
+	bindings, err := s.repo.FindBindingsByApplicationID(ctx, applicationID)
+	if err != nil {
+		return err
+	}
+
+	for _, binding := range bindings {
+		if binding.ID == bindingID {
+			return s.removeBinding(ctx, userID, binding)
+		}
+	}
+
+	return errors.NotFound("Binding not found")
+}
+
+// UnbindApplication removes every binding of an application that is being deleted.
+func (s *Service) UnbindApplication(ctx context.Context, userID, applicationID uint) error {
+	bindings, err := s.repo.FindBindingsByApplicationID(ctx, applicationID)
+	if err != nil {
+		return err
+	}
+
+	for _, binding := range bindings {
+		if err := s.removeBinding(ctx, userID, binding); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) removeBinding(ctx context.Context, userID uint, binding *Binding) error {
+	if err := s.repo.DeleteBinding(ctx, binding.ID); err != nil {
+		return err
+	}
+
+	addon, err := s.repo.FindByID(ctx, binding.AddonID)
+	if err != nil {
+		return err
+	}
+
+	return s.triggerAddonDeployment(ctx, addon, "apply", userID)
+}
+
+// bindingEnv loads the bound addon and computes the binding's connection variables.
+func (s *Service) bindingEnv(ctx context.Context, binding *Binding) (*Addon, map[string]string, map[string]string, error) {
+	addon, err := s.repo.FindByID(ctx, binding.AddonID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	engine := findEngine(addon.Type)
+	if engine == nil {
+		return addon, map[string]string{}, map[string]string{}, nil
+	}
+
+	proj, err := s.projectRepo.FindByID(ctx, addon.ProjectID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	password, err := s.bindingPassword(binding)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	credentials := *binding
+	credentials.Password = password
+	env, secrets := connectionEnv(engine, addon, &credentials, addonHost(proj.Slug, addon.Name))
+	return addon, env, secrets, nil
+}
+
+// bindingPassword decrypts the binding's password. Bindings created before
+// secrets were encrypted at rest hold it in plain text until re-encrypted.
+func (s *Service) bindingPassword(binding *Binding) (string, error) {
+	if !crypto.IsEncrypted(binding.Password) {
+		return binding.Password, nil
+	}
+
+	password, err := s.keyring.Decrypt(binding.Password)
+	if err != nil {
+		return "", errors.Internal("Failed to decrypt addon credentials")
+	}
+	return password, nil
+}
+
+// ReEncryptBindings seals every binding password that is still in plain text
+// or encrypted with a retired key with the primary key, and returns how many
+// were updated.
+func (s *Service) ReEncryptBindings(ctx context.Context) (int, error) {
+	bindings, err := s.repo.FindBindings(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	updated := 0
+	for _, binding := range bindings {
+		if !s.keyring.NeedsReEncryption(binding.Password) {
+			continue
+		}
+
+		password, err := s.bindingPassword(binding)
+		if err != nil {
+			return updated, err
+		}
+		encrypted, err := s.keyring.Encrypt(password)
+		if err != nil {
+			return updated, errors.Internal("Failed to encrypt addon credentials")
+		}
+		if err := s.repo.UpdateBindingPassword(ctx, binding.ID, encrypted); err != nil {
+			return updated, err
+		}
+		updated++
+	}
+
+	return updated, nil
+}
+
+func envNames(values map[string]string) []string {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func toBindingResponse(addon *Addon, binding *Binding, env, secrets map[string]string) *BindingResponse {
+	return &BindingResponse{
+		ID:            binding.ID,
+		AddonID:       addon.ID,
+		AddonName:     addon.Name,
+		AddonType:     addon.Type,
+		ApplicationID: binding.ApplicationID,
+		EnvPrefix:     binding.EnvPrefix,
+		Env:           env,
+		SecretEnv:     envNames(secrets),
+		CreatedAt:     binding.CreatedAt,
+	}
+}
@@ -0,0 +1,117 @@
+package addon
+
+// Engine describes an addon type the platform can provision. Versions are
+// listed oldest first; the last one is the default for new addons. Port is
+// the port bound applications connect to. Tiers are the tiers of the tier
+// catalog the engine is offered in, filled in by withTiers.
+type Engine struct {
+	Type         string   `json:"type"`
+	DisplayName  string   `json:"display_name"`
+	Port         int      `json:"port"`
+	Versions     []string `json:"versions"`
+	Tiers        []string `json:"tiers"`
+	StorageSizes []string `json:"storage_sizes"`
+
+	// excludedTiers are tiers too small to run the engine
+	excludedTiers []string
+}
+
+var storageSizes = []string{"1Gi", "5Gi", "10Gi", "20Gi", "50Gi"}
+
+var catalog = []Engine{
+	{
+		Type:         "mysql",
+		DisplayName:  "MySQL",
+		Port:         3306,
+		Versions:     []string{"8.0", "8.4"},
+		StorageSizes: storageSizes,
+	},
+	{
+		Type:         "postgresql",
+		DisplayName:  "PostgreSQL",
+		Port:         5432,
+		Versions:     []string{"15", "16", "17"},
+		StorageSizes: storageSizes,
+	},
+	{
+		Type:         "redis",
+		DisplayName:  "Redis",
+		Port:         6379,
+		Versions:     []string{"7.2", "7.4"},
+		StorageSizes: storageSizes,
+	},
+	{
+		Type:          "mongodb",
+		DisplayName:   "MongoDB",
+		Port:          27017,
+		Versions:      []string{"6.0", "7.0"},
+		StorageSizes:  storageSizes,
+		excludedTiers: []string{"x3.micro"},
+	},
+	{
+		Type:         "rabbitmq",
+		DisplayName:  "RabbitMQ",
+		Port:         5672,
+		Versions:     []string{"3.13", "4.0"},
+		StorageSizes: []string{"1Gi", "5Gi", "10Gi"},
+	},
+}
+
+// Catalog returns the supported addon engines, each offered the tiers of the
+// tier catalog it can run on.
+func Catalog(tiers []string) []Engine {
+	engines := make([]Engine, len(catalog))
+	for i := range catalog {
+		engines[i] = catalog[i].withTiers(tiers)
+	}
+	return engines
+}
+
+// findEngine returns the catalog entry of an addon type without its tiers, or
+// nil when the type is not in the catalog.
+func findEngine(addonType string) *Engine {
+	for i := range catalog {
+		if catalog[i].Type == addonType {
+			return &catalog[i]
+		}
+	}
+	return nil
+}
+
+// withTiers returns a copy of the engine offered the given tiers, less the
+// ones it cannot run on.
+func (e Engine) withTiers(tiers []string) Engine {
+	e.Tiers = make([]string, 0, len(tiers))
+	for _, tier := range tiers {
+		if indexOf(e.excludedTiers, tier) < 0 {
+			e.Tiers = append(e.Tiers, tier)
+		}
+	}
+	return e
+}
+
+func (e *Engine) DefaultVersion() string {
+	return e.Versions[len(e.Versions)-1]
+}
+
+// versionIndex returns the position of version in Versions, or -1.
+func (e *Engine) versionIndex(version string) int {
+	return indexOf(e.Versions, version)
+}
+
+func (e *Engine) supportsTier(tier string) bool {
+	return indexOf(e.Tiers, tier) >= 0
+}
+
+func (e *Engine) supportsStorage(storage string) bool {
+	return indexOf(e.StorageSizes, storage) >= 0
+}
+
+func indexOf(values []string, value string) int {
+	for i, v := range values {
+		if v == value {
+			return i
+		}
+	}
+	return -1
+}
@@ -1,17 +1,29 @@
 package addon
 
-import "time"
+import (
+	"time"
 
+	"github.com/team-xquare/deployment-platform/internal/pkg/utils/diff"
+	"github.com/team-xquare/deployment-platform/internal/pkg/utils/pagination"
+)
+
+// CreateAddonRequest is validated against the catalog. Version defaults to the
+// engine's newest version and Storage to its smallest size.
 type CreateAddonRequest struct {
 	Name    string `json:"name" binding:"required"`
 	Type    string `json:"type" binding:"required"`
+	Version string `json:"version"`
 	Tier    string `json:"tier" binding:"required"`
 	Storage string `json:"storage"`
 }
 
+// UpdateAddonRequest cannot change the name or type of an addon; they may be
+// omitted or repeated unchanged. Version can only be upgraded and Storage can
+// only grow.
 type UpdateAddonRequest struct {
-	Name    string `json:"name" binding:"required"`
-	Type    string `json:"type" binding:"required"`
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	Version string `json:"version"`
 	Tier    string `json:"tier" binding:"required"`
 	Storage string `json:"storage"`
 }
@@ -21,8 +33,55 @@ type AddonResponse struct {
 	ProjectID uint      `json:"project_id"`
 	Name      string    `json:"name"`
 	Type      string    `json:"type"`
+	Version   string    `json:"version,omitempty"`
 	Tier      string    `json:"tier"`
 	Storage   string    `json:"storage"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
-}
\ No newline at end of file
+}
+
+type AddonListResponse struct {
+	Addons []*AddonResponse `json:"addons"`
+	pagination.Page
+}
+
+// CreateBindingRequest binds an addon of the application's project. EnvPrefix
+// is optional and must be upper case, e.g. "CACHE" yields CACHE_REDIS_HOST.
+type CreateBindingRequest struct {
+	AddonID   uint   `json:"addon_id" binding:"required"`
+	EnvPrefix string `json:"env_prefix"`
+}
+
+// BindingResponse lists the non-secret connection variables with their values
+// and only the names of the secret ones.
+type BindingResponse struct {
+	ID            uint              `json:"id"`
+	AddonID       uint              `json:"addon_id"`
+	AddonName     string            `json:"addon_name"`
+	AddonType     string            `json:"addon_type"`
+	ApplicationID uint              `json:"application_id"`
+	EnvPrefix     string            `json:"env_prefix,omitempty"`
+	Env           map[string]string `json:"env"`
+	SecretEnv     []string          `json:"secret_env"`
+	CreatedAt     time.Time         `json:"created_at"`
+}
+
+type RevisionResponse struct {
+	Number    int            `json:"number"`
+	Config    RevisionConfig `json:"config"`
+	CreatedBy *uint          `json:"created_by"`
+	CreatedAt time.Time      `json:"created_at"`
+}
+
+type RevisionListResponse struct {
+	Revisions []*RevisionResponse `json:"revisions"`
+	pagination.Page
+}
+
+// RevisionDiffResponse lists the changes from one revision to another; an
+// empty list means they hold the same configuration.
+type RevisionDiffResponse struct {
+	From    int           `json:"from"`
+	To      int           `json:"to"`
+	Changes []diff.Change `json:"changes"`
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/team-xquare/deployment-platform/internal/pkg/middleware"
 	"github.com/team-xquare/deployment-platform/internal/pkg/utils/errors"
+	"github.com/team-xquare/deployment-platform/internal/pkg/utils/pagination"
 )
 
 type Handler struct {
@@ -21,9 +22,14 @@ func (h *Handler) RegisterRoutes(r *gin.RouterGroup) {
 	addons := r.Group("/addons")
 	addons.Use(middleware.Auth())
 	{
+		addons.GET("/catalog", h.GetCatalog)
 		addons.GET("/:id", h.GetAddon)
 		addons.PUT("/:id", h.UpdateAddon)
 		addons.DELETE("/:id", h.DeleteAddon)
+		addons.GET("/:id/deployments", h.GetDeployments)
+		addons.GET("/:id/revisions", h.GetRevisions)
+		addons.GET("/:id/revisions/diff", h.DiffRevisions)
+		addons.GET("/:id/revisions/:number", h.GetRevision)
 	}
 
 	// Project-specific addon routes
@@ -49,9 +55,9 @@ func (h *Handler) CreateAddon(c *gin.Context) {
 		return
 	}
 
-	_ = c.GetUint("user_id")
+	userID := c.GetUint("user_id")
 
-	addon, err := h.service.CreateAddon(c.Request.Context(), uint(projectID), req)
+	addon, err := h.service.CreateAddon(c.Request.Context(), userID, uint(projectID), req)
 	if err != nil {
 		c.Error(err)
 		return
@@ -60,6 +66,16 @@ func (h *Handler) CreateAddon(c *gin.Context) {
 	c.JSON(http.StatusCreated, addon)
 }
 
+func (h *Handler) GetCatalog(c *gin.Context) {
+	catalog, err := h.service.GetCatalog(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, catalog)
+}
+
 func (h *Handler) GetAddon(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
@@ -68,9 +84,9 @@ func (h *Handler) GetAddon(c *gin.Context) {
 		return
 	}
 
-	_ = c.GetUint("user_id")
+	userID := c.GetUint("user_id")
 
-	addon, err := h.service.GetAddon(c.Request.Context(), uint(id))
+	addon, err := h.service.GetAddon(c.Request.Context(), userID, uint(id))
 	if err != nil {
 		c.Error(err)
 		return
@@ -87,9 +103,21 @@ func (h *Handler) GetAddonsByProject(c *gin.Context) {
 		return
 	}
 
-	_ = c.GetUint("user_id")
+	page, err := pagination.Parse(c, "-"+pagination.SortCreated, pagination.SortName, pagination.SortCreated)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	filter := ListFilter{
+		Name: c.Query("name"),
+		Type: c.Query("type"),
+		Tier: c.Query("tier"),
+		Page: page,
+	}
+
+	userID := c.GetUint("user_id")
 
-	addons, err := h.service.GetAddonsByProject(c.Request.Context(), uint(projectID))
+	addons, err := h.service.GetAddonsByProject(c.Request.Context(), userID, uint(projectID), filter)
 	if err != nil {
 		c.Error(err)
 		return
@@ -112,9 +140,9 @@ func (h *Handler) UpdateAddon(c *gin.Context) {
 		return
 	}
 
-	_ = c.GetUint("user_id")
+	userID := c.GetUint("user_id")
 
-	addon, err := h.service.UpdateAddon(c.Request.Context(), uint(id), req)
+	addon, err := h.service.UpdateAddon(c.Request.Context(), userID, uint(id), req)
 	if err != nil {
 		c.Error(err)
 		return
@@ -131,13 +159,118 @@ func (h *Handler) DeleteAddon(c *gin.Context) {
 		return
 	}
 
-	_ = c.GetUint("user_id")
+	userID := c.GetUint("user_id")
 
-	err = h.service.DeleteAddon(c.Request.Context(), uint(id))
+	err = h.service.DeleteAddon(c.Request.Context(), userID, uint(id))
 	if err != nil {
 		c.Error(err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "Addon deleted successfully"})
-}
\ No newline at end of file
+}
+
+func (h *Handler) GetDeployments(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.Error(errors.BadRequest("Invalid addon ID"))
+		return
+	}
+
+	page, err := pagination.Parse(c, "-"+pagination.SortCreated, pagination.SortCreated)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	userID := c.GetUint("user_id")
+
+	deployments, err := h.service.GetDeployments(c.Request.Context(), userID, uint(id), page)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, deployments)
+}
+
+func (h *Handler) GetRevisions(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.Error(errors.BadRequest("Invalid addon ID"))
+		return
+	}
+
+	page, err := pagination.Parse(c, "-"+pagination.SortCreated, pagination.SortCreated)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	userID := c.GetUint("user_id")
+
+	revisions, err := h.service.GetRevisions(c.Request.Context(), userID, uint(id), page)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, revisions)
+}
+
+func (h *Handler) GetRevision(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.Error(errors.BadRequest("Invalid addon ID"))
+		return
+	}
+
+	number, err := strconv.Atoi(c.Param("number"))
+	if err != nil {
+		c.Error(errors.BadRequest("Invalid revision number"))
+		return
+	}
+
+	userID := c.GetUint("user_id")
+
+	revision, err := h.service.GetRevision(c.Request.Context(), userID, uint(id), number)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, revision)
+}
+
+func (h *Handler) DiffRevisions(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.Error(errors.BadRequest("Invalid addon ID"))
+		return
+	}
+
+	from, err := strconv.Atoi(c.Query("from"))
+	if err != nil {
+		c.Error(errors.BadRequest("Invalid from revision number"))
+		return
+	}
+	to, err := strconv.Atoi(c.Query("to"))
+	if err != nil {
+		c.Error(errors.BadRequest("Invalid to revision number"))
+		return
+	}
+
+	userID := c.GetUint("user_id")
+
+	revisionDiff, err := h.service.DiffRevisions(c.Request.Context(), userID, uint(id), from, to)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, revisionDiff)
+}
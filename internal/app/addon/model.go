@@ -1,14 +1,61 @@
 package addon
 
-import "time"
+import (
+	"time"
+
+	"github.com/team-xquare/deployment-platform/internal/pkg/utils/pagination"
+)
 
 type Addon struct {
 	ID        uint      `json:"id" db:"id"`
 	ProjectID uint      `json:"project_id" db:"project_id"`
 	Name      string    `json:"name" db:"name"`
 	Type      string    `json:"type" db:"type"`
+	Version   string    `json:"version" db:"version"`
 	Tier      string    `json:"tier" db:"tier"`
 	Storage   string    `json:"storage" db:"storage"`
 	CreatedAt time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
-}
\ No newline at end of file
+}
+
+// Binding attaches an addon to an application. Every binding has its own
+// generated addon user whose password is stored encrypted; EnvPrefix is prepended to the connection variable
+// names so that several addons of the same type can be bound to one application.
+type Binding struct {
+	ID            uint      `json:"id" db:"id"`
+	AddonID       uint      `json:"addon_id" db:"addon_id"`
+	ApplicationID uint      `json:"application_id" db:"application_id"`
+	EnvPrefix     string    `json:"env_prefix" db:"env_prefix"`
+	Username      string    `json:"-" db:"username"`
+	Password      string    `json:"-" db:"password"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+}
+
+// Revision is an immutable snapshot of an addon as it was saved, numbered from
+// 1 per addon.
+type Revision struct {
+	ID        uint           `json:"id" db:"id"`
+	AddonID   uint           `json:"addon_id" db:"addon_id"`
+	Number    int            `json:"number" db:"number"`
+	Config    RevisionConfig `json:"config" db:"config"`
+	CreatedBy *uint          `json:"created_by" db:"created_by"`
+	CreatedAt time.Time      `json:"created_at" db:"created_at"`
+}
+
+// RevisionConfig is the saved configuration of an addon.
+type RevisionConfig struct {
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	Version string `json:"version,omitempty"`
+	Tier    string `json:"tier"`
+	Storage string `json:"storage"`
+}
+
+// ListFilter narrows and pages the addons of a project. Name matches addons
+// whose name contains it; the other fields match exactly.
+type ListFilter struct {
+	Name string
+	Type string
+	Tier string
+	Page pagination.Params
+}
@@ -0,0 +1,53 @@
+package addon
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// Binary and decimal suffixes of Kubernetes resource quantities, in bytes.
+var quantitySuffixes = []struct {
+	suffix     string
+	multiplier int64
+}{
+	{"Ki", 1 << 10},
+	{"Mi", 1 << 20},
+	{"Gi", 1 << 30},
+	{"Ti", 1 << 40},
+	{"Pi", 1 << 50},
+	{"Ei", 1 << 60},
+	{"k", 1e3},
+	{"M", 1e6},
+	{"G", 1e9},
+	{"T", 1e12},
+	{"P", 1e15},
+	{"E", 1e18},
+}
+
+// parseQuantity parses a Kubernetes-style storage quantity such as "10Gi",
+// "500M" or "1.5Ti" into an exact number of bytes.
+func parseQuantity(quantity string) (*big.Rat, error) {
+	number := strings.TrimSpace(quantity)
+	multiplier := int64(1)
+
+	// Two-letter suffixes come first so "Mi" is not read as "M"
+	for _, s := range quantitySuffixes {
+		if strings.HasSuffix(number, s.suffix) {
+			number = strings.TrimSuffix(number, s.suffix)
+			multiplier = s.multiplier
+			break
+		}
+	}
+
+	if number == "" || strings.ContainsAny(number, "eE/+-") {
+		return nil, fmt.Errorf("invalid quantity %q", quantity)
+	}
+
+	value, ok := new(big.Rat).SetString(number)
+	if !ok || value.Sign() <= 0 {
+		return nil, fmt.Errorf("invalid quantity %q", quantity)
+	}
+
+	return value.Mul(value, new(big.Rat).SetInt64(multiplier)), nil
+}
@@ -1,10 +1,27 @@
 package addon
 
-import "context"
+import (
+	"context"
+
+	"github.com/team-xquare/deployment-platform/internal/pkg/utils/pagination"
+)
 
 type Repository interface {
 	Save(ctx context.Context, addon *Addon) error
 	FindByID(ctx context.Context, id uint) (*Addon, error)
-	FindByProjectID(ctx context.Context, projectID uint) ([]*Addon, error)
+	FindByProjectID(ctx context.Context, projectID uint, filter ListFilter) ([]*Addon, int, error)
 	Delete(ctx context.Context, id uint) error
+	// SaveRevision assigns the revision the addon's next number
+	SaveRevision(ctx context.Context, revision *Revision) error
+	FindRevisions(ctx context.Context, addonID uint, page pagination.Params) ([]*Revision, int, error)
+	// FindRevision returns nil when the addon has no revision with that number
+	FindRevision(ctx context.Context, addonID uint, number int) (*Revision, error)
+	SaveBinding(ctx context.Context, binding *Binding) error
+	// FindBinding returns nil when the addon is not bound to the application
+	FindBinding(ctx context.Context, addonID, applicationID uint) (*Binding, error)
+	FindBindingsByAddonID(ctx context.Context, addonID uint) ([]*Binding, error)
+	FindBindingsByApplicationID(ctx context.Context, applicationID uint) ([]*Binding, error)
+	FindBindings(ctx context.Context) ([]*Binding, error)
+	UpdateBindingPassword(ctx context.Context, id uint, password string) error
+	DeleteBinding(ctx context.Context, id uint) error
 }
\ No newline at end of file
@@ -0,0 +1,109 @@
+package addon
+
+import (
+	"context"
+
+	"github.com/team-xquare/deployment-platform/internal/app/member"
+	"github.com/team-xquare/deployment-platform/internal/pkg/utils/diff"
+	"github.com/team-xquare/deployment-platform/internal/pkg/utils/errors"
+	"github.com/team-xquare/deployment-platform/internal/pkg/utils/pagination"
+)
+
+func (s *Service) GetRevisions(ctx context.Context, userID, id uint, page pagination.Params) (*RevisionListResponse, error) {
+	addon, err := s.findAuthorized(ctx, userID, id, member.RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	revisions, total, err := s.repo.FindRevisions(ctx, addon.ID, page)
+	if err != nil {
+		return nil, err
+	}
+
+	revisions, meta := pagination.Paginate(revisions, page, total, func(rev *Revision) (uint, string) {
+		return rev.ID, ""
+	})
+
+	responses := make([]*RevisionResponse, len(revisions))
+	for i, rev := range revisions {
+		responses[i] = toRevisionResponse(rev)
+	}
+
+	return &RevisionListResponse{Revisions: responses, Page: meta}, nil
+}
+
+func (s *Service) GetRevision(ctx context.Context, userID, id uint, number int) (*RevisionResponse, error) {
+	addon, err := s.findAuthorized(ctx, userID, id, member.RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	rev, err := s.findRevision(ctx, addon.ID, number)
+	if err != nil {
+		return nil, err
+	}
+
+	return toRevisionResponse(rev), nil
+}
+
+// DiffRevisions compares the configuration of two revisions field by field.
+func (s *Service) DiffRevisions(ctx context.Context, userID, id uint, from, to int) (*RevisionDiffResponse, error) {
+	addon, err := s.findAuthorized(ctx, userID, id, member.RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	fromRev, err := s.findRevision(ctx, addon.ID, from)
+	if err != nil {
+		return nil, err
+	}
+	toRev, err := s.findRevision(ctx, addon.ID, to)
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := diff.Compare(fromRev.Config, toRev.Config)
+	if err != nil {
+		return nil, errors.Internal("Failed to compare revisions")
+	}
+
+	return &RevisionDiffResponse{From: from, To: to, Changes: changes}, nil
+}
+
+// recordRevision snapshots the addon as it is being saved. It must run inside
+// the transaction that saves the addon.
+func (s *Service) recordRevision(ctx context.Context, addon *Addon, userID uint) error {
+	rev := &Revision{
+		AddonID: addon.ID,
+		Config: RevisionConfig{
+			Name:    addon.Name,
+			Type:    addon.Type,
+			Version: addon.Version,
+			Tier:    addon.Tier,
+			Storage: addon.Storage,
+		},
+		CreatedBy: &userID,
+	}
+
+	return s.repo.SaveRevision(ctx, rev)
+}
+
+func (s *Service) findRevision(ctx context.Context, addonID uint, number int) (*Revision, error) {
+	rev, err := s.repo.FindRevision(ctx, addonID, number)
+	if err != nil {
+		return nil, err
+	}
+	if rev == nil {
+		return nil, errors.NotFound("Revision not found")
+	}
+	return rev, nil
+}
+
+func toRevisionResponse(rev *Revision) *RevisionResponse {
+	return &RevisionResponse{
+		Number:    rev.Number,
+		Config:    rev.Config,
+		CreatedBy: rev.CreatedBy,
+		CreatedAt: rev.CreatedAt,
+	}
+}
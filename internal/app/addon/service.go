@@ -2,43 +2,143 @@ package addon
 
 import (
 	"context"
+	"regexp"
+	"strings"
 
+	"github.com/team-xquare/deployment-platform/internal/app/audit"
+	"github.com/team-xquare/deployment-platform/internal/app/deployment"
 	"github.com/team-xquare/deployment-platform/internal/app/github"
+	"github.com/team-xquare/deployment-platform/internal/app/gitops"
+	"github.com/team-xquare/deployment-platform/internal/app/member"
+	"github.com/team-xquare/deployment-platform/internal/app/outbox"
+	"github.com/team-xquare/deployment-platform/internal/app/project"
+	"github.com/team-xquare/deployment-platform/internal/app/tier"
+	"github.com/team-xquare/deployment-platform/internal/pkg/utils/crypto"
+	"github.com/team-xquare/deployment-platform/internal/pkg/utils/errors"
+	"github.com/team-xquare/deployment-platform/internal/pkg/utils/pagination"
 )
 
 type Service struct {
-	repo      Repository
-	githubSvc *github.Service
+	repo          Repository
+	projectRepo   project.Repository
+	tx            outbox.Transactor
+	keyring       *crypto.Keyring
+	githubSvc     *github.Service
+	gitopsSvc     *gitops.Service
+	memberSvc     *member.Service
+	deploymentSvc *deployment.Service
+	tierSvc       *tier.Service
+	outboxSvc     *outbox.Service
 }
 
-func NewService(repo Repository, githubSvc *github.Service) *Service {
+func NewService(repo Repository, projectRepo project.Repository, tx outbox.Transactor, keyring *crypto.Keyring, githubSvc *github.Service, gitopsSvc *gitops.Service, memberSvc *member.Service, deploymentSvc *deployment.Service, tierSvc *tier.Service, outboxSvc *outbox.Service) *Service {
 	return &Service{
-		repo:      repo,
-		githubSvc: githubSvc,
+		repo:          repo,
+		projectRepo:   projectRepo,
+		tx:            tx,
+		keyring:       keyring,
+		githubSvc:     githubSvc,
+		gitopsSvc:     gitopsSvc,
+		memberSvc:     memberSvc,
+		deploymentSvc: deploymentSvc,
+		tierSvc:       tierSvc,
+		outboxSvc:     outboxSvc,
 	}
 }
 
-func (s *Service) CreateAddon(ctx context.Context, projectID uint, req CreateAddonRequest) (*AddonResponse, error) {
+func (s *Service) CreateAddon(ctx context.Context, userID, projectID uint, req CreateAddonRequest) (*AddonResponse, error) {
+	if _, err := s.memberSvc.Authorize(ctx, userID, projectID, member.RoleDeveloper); err != nil {
+		return nil, err
+	}
+
+	engine, err := s.findEngine(ctx, req.Type)
+	if err != nil {
+		return nil, err
+	}
+	if engine == nil {
+		return nil, errors.Validation([]errors.FieldError{
+			{Field: "type", Message: "is not in the addon catalog"},
+		})
+	}
+
+	if req.Version == "" {
+		req.Version = engine.DefaultVersion()
+	}
+	if req.Storage == "" {
+		req.Storage = engine.StorageSizes[0]
+	}
+	if fieldErrors := validateCreate(engine, req); len(fieldErrors) > 0 {
+		return nil, errors.Validation(fieldErrors)
+	}
+
 	addon := &Addon{
 		ProjectID: projectID,
 		Name:      req.Name,
 		Type:      req.Type,
+		Version:   req.Version,
 		Tier:      req.Tier,
 		Storage:   req.Storage,
 	}
 
-	if err := s.repo.Save(ctx, addon); err != nil {
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.tierSvc.CheckQuota(ctx, projectID, "", addon.Tier); err != nil {
+			return err
+		}
+		if err := s.repo.Save(ctx, addon); err != nil {
+			return err
+		}
+
+		if err := s.recordRevision(ctx, addon, userID); err != nil {
+			return err
+		}
+
+		// Trigger GitHub Actions workflow for addon deployment
+		return s.triggerAddonDeployment(ctx, addon, "apply", userID)
+	})
+	if err != nil {
 		return nil, err
 	}
 
-	// Trigger GitHub Actions workflow for addon deployment
-	go s.triggerAddonDeployment(addon, "apply")
+	response := s.toResponse(addon)
+	audit.Record(ctx, audit.Event{
+		Action:       "addon.create",
+		ResourceType: "addon",
+		ResourceID:   audit.ID(addon.ID),
+		ProjectID:    &addon.ProjectID,
+		After:        response,
+	})
 
-	return s.toResponse(addon), nil
+	return response, nil
 }
 
-func (s *Service) GetAddon(ctx context.Context, id uint) (*AddonResponse, error) {
-	addon, err := s.repo.FindByID(ctx, id)
+func (s *Service) GetCatalog(ctx context.Context) ([]Engine, error) {
+	tiers, err := s.tierSvc.Names(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return Catalog(tiers), nil
+}
+
+// findEngine returns the catalog entry of an addon type offered the tiers of
+// the tier catalog, or nil when the type is not in the catalog.
+func (s *Service) findEngine(ctx context.Context, addonType string) (*Engine, error) {
+	engine := findEngine(addonType)
+	if engine == nil {
+		return nil, nil
+	}
+
+	tiers, err := s.tierSvc.Names(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	offered := engine.withTiers(tiers)
+	return &offered, nil
+}
+
+func (s *Service) GetAddon(ctx context.Context, userID, id uint) (*AddonResponse, error) {
+	addon, err := s.findAuthorized(ctx, userID, id, member.RoleViewer)
 	if err != nil {
 		return nil, err
 	}
@@ -46,49 +146,211 @@ func (s *Service) GetAddon(ctx context.Context, id uint) (*AddonResponse, error)
 	return s.toResponse(addon), nil
 }
 
-func (s *Service) GetAddonsByProject(ctx context.Context, projectID uint) ([]*AddonResponse, error) {
-	addons, err := s.repo.FindByProjectID(ctx, projectID)
+func (s *Service) GetAddonsByProject(ctx context.Context, userID, projectID uint, filter ListFilter) (*AddonListResponse, error) {
+	if _, err := s.memberSvc.Authorize(ctx, userID, projectID, member.RoleViewer); err != nil {
+		return nil, err
+	}
+
+	addons, total, err := s.repo.FindByProjectID(ctx, projectID, filter)
 	if err != nil {
 		return nil, err
 	}
 
+	addons, page := pagination.Paginate(addons, filter.Page, total, func(addon *Addon) (uint, string) {
+		return addon.ID, addon.Name
+	})
+
 	responses := make([]*AddonResponse, len(addons))
 	for i, addon := range addons {
 		responses[i] = s.toResponse(addon)
 	}
 
-	return responses, nil
+	return &AddonListResponse{Addons: responses, Page: page}, nil
 }
 
-func (s *Service) UpdateAddon(ctx context.Context, id uint, req UpdateAddonRequest) (*AddonResponse, error) {
-	addon, err := s.repo.FindByID(ctx, id)
+func (s *Service) UpdateAddon(ctx context.Context, userID, id uint, req UpdateAddonRequest) (*AddonResponse, error) {
+	addon, err := s.findAuthorized(ctx, userID, id, member.RoleDeveloper)
 	if err != nil {
 		return nil, err
 	}
 
-	// Update fields
-	addon.Name = req.Name
-	addon.Type = req.Type
+	// Addons created before the catalog existed may use an engine it no longer lists
+	engine, err := s.findEngine(ctx, addon.Type)
+	if err != nil {
+		return nil, err
+	}
+	if fieldErrors := validateUpdate(addon, engine, req); len(fieldErrors) > 0 {
+		return nil, errors.Validation(fieldErrors)
+	}
+	before := s.toResponse(addon)
+	fromTier := addon.Tier
 	addon.Tier = req.Tier
-	addon.Storage = req.Storage
+	if req.Version != "" {
+		addon.Version = req.Version
+	}
+	if req.Storage != "" {
+		addon.Storage = req.Storage
+	}
+
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.tierSvc.CheckQuota(ctx, addon.ProjectID, fromTier, addon.Tier); err != nil {
+			return err
+		}
+		if err := s.repo.Save(ctx, addon); err != nil {
+			return err
+		}
+		if err := s.recordRevision(ctx, addon, userID); err != nil {
+			return err
+		}
 
-	if err := s.repo.Save(ctx, addon); err != nil {
+		return s.triggerAddonDeployment(ctx, addon, "apply", userID)
+	})
+	if err != nil {
 		return nil, err
 	}
 
-	return s.toResponse(addon), nil
+	response := s.toResponse(addon)
+	audit.Record(ctx, audit.Event{
+		Action:       "addon.update",
+		ResourceType: "addon",
+		ResourceID:   audit.ID(addon.ID),
+		ProjectID:    &addon.ProjectID,
+		Before:       before,
+		After:        response,
+	})
+
+	return response, nil
 }
 
-func (s *Service) DeleteAddon(ctx context.Context, id uint) error {
-	addon, err := s.repo.FindByID(ctx, id)
+func (s *Service) DeleteAddon(ctx context.Context, userID, id uint) error {
+	addon, err := s.findAuthorized(ctx, userID, id, member.RoleMaintainer)
+	if err != nil {
+		return err
+	}
+
+	bindings, err := s.repo.FindBindingsByAddonID(ctx, id)
 	if err != nil {
 		return err
 	}
+	if len(bindings) > 0 {
+		return errors.BadRequest("Addon is still bound to applications; remove their bindings first")
+	}
 
-	// Trigger GitHub Actions workflow for addon removal
-	go s.triggerAddonDeployment(addon, "remove")
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		// Trigger GitHub Actions workflow for addon removal
+		if err := s.triggerAddonDeployment(ctx, addon, "remove", userID); err != nil {
+			return err
+		}
 
-	return s.repo.Delete(ctx, id)
+		return s.repo.Delete(ctx, id)
+	})
+	if err != nil {
+		return err
+	}
+
+	audit.Record(ctx, audit.Event{
+		Action:       "addon.delete",
+		ResourceType: "addon",
+		ResourceID:   audit.ID(addon.ID),
+		ProjectID:    &addon.ProjectID,
+		Before:       s.toResponse(addon),
+	})
+
+	return nil
+}
+
+func (s *Service) GetDeployments(ctx context.Context, userID, id uint, page pagination.Params) (*deployment.DeploymentListResponse, error) {
+	addon, err := s.findAuthorized(ctx, userID, id, member.RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.deploymentSvc.GetAddonDeployments(ctx, addon.ID, page)
+}
+
+// Addon names are used as a directory in the infrastructure config repository
+// and in the addon's hostname, so they follow the same DNS label rules as
+// project slugs.
+var namePattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]*[a-z0-9])?$`)
+
+// validateCreate checks a request against the engine's catalog entry.
+func validateCreate(engine *Engine, req CreateAddonRequest) []errors.FieldError {
+	var fieldErrors []errors.FieldError
+
+	if len(req.Name) > 63 || !namePattern.MatchString(req.Name) {
+		fieldErrors = append(fieldErrors, errors.FieldError{Field: "name", Message: "must be at most 63 lowercase letters, digits or hyphens and start and end with a letter or digit"})
+	}
+
+	if engine.versionIndex(req.Version) < 0 {
+		fieldErrors = append(fieldErrors, errors.FieldError{Field: "version", Message: "must be one of " + strings.Join(engine.Versions, ", ")})
+	}
+	if !engine.supportsTier(req.Tier) {
+		fieldErrors = append(fieldErrors, errors.FieldError{Field: "tier", Message: "must be one of " + strings.Join(engine.Tiers, ", ")})
+	}
+	if !engine.supportsStorage(req.Storage) {
+		fieldErrors = append(fieldErrors, errors.FieldError{Field: "storage", Message: "must be one of " + strings.Join(engine.StorageSizes, ", ")})
+	}
+
+	return fieldErrors
+}
+
+// validateUpdate checks a request against the addon's catalog entry, which is
+// nil for engines the catalog no longer lists, and rejects changes that would
+// replace or shrink the addon's underlying volume and lose data.
+func validateUpdate(addon *Addon, engine *Engine, req UpdateAddonRequest) []errors.FieldError {
+	var fieldErrors []errors.FieldError
+
+	if req.Name != "" && req.Name != addon.Name {
+		fieldErrors = append(fieldErrors, errors.FieldError{Field: "name", Message: "cannot be changed"})
+	}
+	if req.Type != "" && req.Type != addon.Type {
+		fieldErrors = append(fieldErrors, errors.FieldError{Field: "type", Message: "cannot be changed from " + addon.Type})
+	}
+
+	if engine != nil {
+		if req.Version != "" && req.Version != addon.Version {
+			requested := engine.versionIndex(req.Version)
+			if requested < 0 {
+				fieldErrors = append(fieldErrors, errors.FieldError{Field: "version", Message: "must be one of " + strings.Join(engine.Versions, ", ")})
+			} else if requested < engine.versionIndex(addon.Version) {
+				fieldErrors = append(fieldErrors, errors.FieldError{Field: "version", Message: "cannot be downgraded from " + addon.Version})
+			}
+		}
+		if !engine.supportsTier(req.Tier) {
+			fieldErrors = append(fieldErrors, errors.FieldError{Field: "tier", Message: "must be one of " + strings.Join(engine.Tiers, ", ")})
+		}
+	}
+
+	if req.Storage != "" && req.Storage != addon.Storage {
+		requested, err := parseQuantity(req.Storage)
+		switch {
+		case engine != nil && !engine.supportsStorage(req.Storage):
+			fieldErrors = append(fieldErrors, errors.FieldError{Field: "storage", Message: "must be one of " + strings.Join(engine.StorageSizes, ", ")})
+		case err != nil:
+			fieldErrors = append(fieldErrors, errors.FieldError{Field: "storage", Message: "must be a storage quantity such as 10Gi"})
+		case addon.Storage != "":
+			// Storage saved before quantities were validated may not parse; it cannot be compared
+			if current, err := parseQuantity(addon.Storage); err == nil && requested.Cmp(current) < 0 {
+				fieldErrors = append(fieldErrors, errors.FieldError{Field: "storage", Message: "cannot be reduced below " + addon.Storage})
+			}
+		}
+	}
+
+	return fieldErrors
+}
+
+// findAuthorized loads an addon and checks the user's role in its project.
+func (s *Service) findAuthorized(ctx context.Context, userID, id uint, required member.Role) (*Addon, error) {
+	addon, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.memberSvc.Authorize(ctx, userID, addon.ProjectID, required); err != nil {
+		return nil, err
+	}
+
+	return addon, nil
 }
 
 func (s *Service) toResponse(addon *Addon) *AddonResponse {
@@ -97,6 +359,7 @@ func (s *Service) toResponse(addon *Addon) *AddonResponse {
 		ProjectID: addon.ProjectID,
 		Name:      addon.Name,
 		Type:      addon.Type,
+		Version:   addon.Version,
 		Tier:      addon.Tier,
 		Storage:   addon.Storage,
 		CreatedAt: addon.CreatedAt,
@@ -104,30 +367,89 @@ func (s *Service) toResponse(addon *Addon) *AddonResponse {
 	}
 }
 
-func (s *Service) triggerAddonDeployment(addon *Addon, action string) {
-	if s.githubSvc == nil {
-		return
+// triggerAddonDeployment records a deployment for the addon and queues its
+// GitHub Actions dispatch in the outbox. It must run inside the transaction
+// that saves or deletes the addon.
+func (s *Service) triggerAddonDeployment(ctx context.Context, addon *Addon, action string, userID uint) error {
+	target, err := s.gitopsSvc.Resolve(ctx, addon.ProjectID, gitops.ResourceAddon)
+	if err != nil {
+		return err
+	}
+	if target == nil {
+		return errors.BadRequest("No GitOps target is configured for addons")
+	}
+
+	proj, err := s.projectRepo.FindByID(ctx, addon.ProjectID)
+	if err != nil {
+		return err
 	}
+	path := project.AddonPath(proj.Slug, addon.Name)
 
-	// Use a default GitHub repo for addons (this would be configurable)
-	owner := "team-xquare"  // This should come from config
-	repo := "infrastructure-configs"  // This should come from config
-	
-	projectName := "project-" + string(rune(addon.ProjectID))
-	path := "projects/" + projectName + "/addons/" + addon.Name
-	
 	spec := map[string]interface{}{
 		"type":    addon.Type,
+		"version": addon.Version,
 		"tier":    addon.Tier,
 		"storage": addon.Storage,
 	}
-	
+
+	resources, err := s.tierSvc.Spec(ctx, addon.Tier)
+	if err != nil {
+		return err
+	}
+	if resources != nil {
+		spec["resources"] = resources
+	}
+
+	// Bound applications each get a user; passwords travel outside the recorded
+	// spec, sealed until the outbox worker dispatches them
+	var secrets map[string]string
+	if action == "apply" {
+		bindings, err := s.repo.FindBindingsByAddonID(ctx, addon.ID)
+		if err != nil {
+			return err
+		}
+		if len(bindings) > 0 {
+			users := make([]string, len(bindings))
+			secrets = make(map[string]string, len(bindings))
+			for i, binding := range bindings {
+				password, err := s.bindingPassword(binding)
+				if err != nil {
+					return err
+				}
+				sealed, err := s.keyring.Encrypt(password)
+				if err != nil {
+					return errors.Internal("Failed to encrypt addon credentials")
+				}
+				users[i] = binding.Username
+				secrets[binding.Username] = sealed
+			}
+			spec["database"] = databaseName(addon.Name)
+			spec["users"] = users
+		}
+	}
+
+	d := &deployment.Deployment{
+		ProjectID:   addon.ProjectID,
+		AddonID:     &addon.ID,
+		AddonName:   addon.Name,
+		Action:      action,
+		Spec:        spec,
+		TargetOwner: target.Owner,
+		TargetRepo:  target.Repo,
+		RequestedBy: &userID,
+	}
+	if err := s.deploymentSvc.Record(ctx, d); err != nil {
+		return err
+	}
+
 	payload := github.ConfigAPIPayload{
-		Path:   path,
-		Action: action,
-		Spec:   spec,
-	}
-	
-	ctx := context.Background()
-	s.githubSvc.TriggerGitHubAction(ctx, owner, repo, payload)
-}
\ No newline at end of file
+		Path:          path,
+		Action:        action,
+		Spec:          spec,
+		Secrets:       secrets,
+		CorrelationID: d.CorrelationID,
+	}
+
+	return s.outboxSvc.Enqueue(ctx, outbox.AggregateDeployment, d.ID,
+		target.InstallationID, target.Owner, target.Repo, target.EventType, path, payload)
+}
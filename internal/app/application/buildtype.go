@@ -0,0 +1,326 @@
+package application
+
+import (
+	"bytes"
+	"encoding/json"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/team-xquare/deployment-platform/internal/pkg/utils/errors"
+)
+
+// BuildType describes one way of building an application from source. Each
+// type registers the struct its build block decodes into, defaults for unset
+// fields, and the language versions it supports, oldest first; the newest is
+// the default. String fields that are still empty after defaults are applied
+// are required, and fields ending in "Path" must be relative to the repository.
+type BuildType struct {
+	Name string
+	// VersionField is the JSON name of the language version field, or "" when
+	// the type has none
+	VersionField string
+	Versions     []string
+	Defaults     map[string]string
+	NewConfig    func() interface{}
+	// Validate adds rules of the type's own; it may be nil
+	Validate func(config interface{}) []errors.FieldError
+}
+
+var (
+	buildTypes     = map[string]*BuildType{}
+	buildTypeNames []string
+)
+
+// RegisterBuildType adds a build type to the registry. Registering a name twice
+// is a programming error and panics.
+func RegisterBuildType(buildType *BuildType) {
+	if _, exists := buildTypes[buildType.Name]; exists {
+		panic("application: build type " + buildType.Name + " registered twice")
+	}
+	buildTypes[buildType.Name] = buildType
+	buildTypeNames = append(buildTypeNames, buildType.Name)
+}
+
+// BuildTypes returns the registered build types in registration order.
+func BuildTypes() []*BuildType {
+	types := make([]*BuildType, len(buildTypeNames))
+	for i, name := range buildTypeNames {
+		types[i] = buildTypes[name]
+	}
+	return types
+}
+
+func (b *BuildType) defaultVersion() string {
+	if len(b.Versions) == 0 {
+		return ""
+	}
+	return b.Versions[len(b.Versions)-1]
+}
+
+// normalizeBuild decodes the single build block of a request, applies its
+// defaults and validates it. It returns the build type and the block as it
+// should be stored.
+func normalizeBuild(build BuildConfig) (string, BuildConfig, error) {
+	if len(build) != 1 {
+		names := make([]string, 0, len(build))
+		for name := range build {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		message := "must contain exactly one build type"
+		if len(names) > 1 {
+			message += ", got " + strings.Join(names, ", ")
+		}
+		return "", nil, errors.Validation([]errors.FieldError{{Field: "build", Message: message}})
+	}
+
+	var name string
+	var raw json.RawMessage
+	for n, r := range build {
+		name, raw = n, r
+	}
+
+	buildType, ok := buildTypes[name]
+	if !ok {
+		return "", nil, errors.Validation([]errors.FieldError{
+			{Field: "build", Message: "unknown build type " + name + "; must be one of " + strings.Join(buildTypeNames, ", ")},
+		})
+	}
+
+	config := buildType.NewConfig()
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(config); err != nil {
+		return "", nil, errors.Validation([]errors.FieldError{
+			{Field: "build." + name, Message: "is not a valid " + name + " build: " + err.Error()},
+		})
+	}
+
+	buildType.applyDefaults(config)
+	if fieldErrors := buildType.validate(config); len(fieldErrors) > 0 {
+		return "", nil, errors.Validation(fieldErrors)
+	}
+
+	normalized, err := json.Marshal(config)
+	if err != nil {
+		return "", nil, errors.Internal("Failed to encode build configuration")
+	}
+
+	return name, BuildConfig{name: normalized}, nil
+}
+
+func (b *BuildType) applyDefaults(config interface{}) {
+	eachStringField(config, func(field string, value reflect.Value) {
+		if value.String() != "" {
+			return
+		}
+		if field == b.VersionField {
+			value.SetString(b.defaultVersion())
+		} else if def, ok := b.Defaults[field]; ok {
+			value.SetString(def)
+		}
+	})
+}
+
+func (b *BuildType) validate(config interface{}) []errors.FieldError {
+	var fieldErrors []errors.FieldError
+
+	eachStringField(config, func(field string, value reflect.Value) {
+		name := "build." + b.Name + "." + field
+		switch {
+		case value.String() == "":
+			fieldErrors = append(fieldErrors, errors.FieldError{Field: name, Message: "is required"})
+		case field == b.VersionField && indexOf(b.Versions, value.String()) < 0:
+			fieldErrors = append(fieldErrors, errors.FieldError{Field: name, Message: "must be one of " + strings.Join(b.Versions, ", ")})
+		case strings.HasSuffix(field, "Path") && !isRelativePath(value.String()):
+			fieldErrors = append(fieldErrors, errors.FieldError{Field: name, Message: "must be a path inside the repository"})
+		}
+	})
+
+	if b.Validate != nil {
+		fieldErrors = append(fieldErrors, b.Validate(config)...)
+	}
+
+	return fieldErrors
+}
+
+// Schema returns a JSON Schema describing the type's build block.
+func (b *BuildType) Schema() map[string]interface{} {
+	properties := map[string]interface{}{}
+	var required []string
+
+	defaults := b.NewConfig()
+	b.applyDefaults(defaults)
+	eachStringField(defaults, func(field string, value reflect.Value) {
+		property := map[string]interface{}{"type": "string"}
+		if field == b.VersionField {
+			property["enum"] = b.Versions
+		}
+		if value.String() != "" {
+			property["default"] = value.String()
+		} else {
+			required = append(required, field)
+		}
+		properties[field] = property
+	})
+
+	schema := map[string]interface{}{
+		"$schema":              "https://json-schema.org/draft/2020-12/schema",
+		"title":                b.Name,
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+
+	return schema
+}
+
+// eachStringField calls fn for every string field of the struct config points
+// to, named by its JSON tag.
+func eachStringField(config interface{}, fn func(field string, value reflect.Value)) {
+	v := reflect.ValueOf(config).Elem()
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).Type.Kind() != reflect.String {
+			continue
+		}
+		field, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if field == "" || field == "-" {
+			field = t.Field(i).Name
+		}
+		fn(field, v.Field(i))
+	}
+}
+
+func isRelativePath(p string) bool {
+	if strings.HasPrefix(p, "/") {
+		return false
+	}
+	cleaned := path.Clean(p)
+	return cleaned != ".." && !strings.HasPrefix(cleaned, "../")
+}
+
+func indexOf(values []string, value string) int {
+	for i, v := range values {
+		if v == value {
+			return i
+		}
+	}
+	return -1
+}
+
+var (
+	javaVersions   = []string{"11", "17", "21"}
+	nodeVersions   = []string{"18", "20", "22"}
+	pythonVersions = []string{"3.10", "3.11", "3.12"}
+)
+
+func init() {
+	RegisterBuildType(&BuildType{
+		Name:         "gradle",
+		VersionField: "javaVersion",
+		Versions:     javaVersions,
+		Defaults:     map[string]string{"jarOutputPath": "build/libs/*.jar", "buildCommand": "./gradlew build -x test"},
+		NewConfig:    func() interface{} { return &GradleBuild{} },
+	})
+	RegisterBuildType(&BuildType{
+		Name:         "nodejs",
+		VersionField: "nodeVersion",
+		Versions:     nodeVersions,
+		Defaults:     map[string]string{"buildCommand": "npm ci", "startCommand": "npm start"},
+		NewConfig:    func() interface{} { return &NodeJSBuild{} },
+	})
+	RegisterBuildType(&BuildType{
+		Name:         "react",
+		VersionField: "nodeVersion",
+		Versions:     nodeVersions,
+		Defaults:     map[string]string{"buildCommand": "npm ci && npm run build", "distPath": "build"},
+		NewConfig:    func() interface{} { return &ReactBuild{} },
+	})
+	RegisterBuildType(&BuildType{
+		Name:         "vite",
+		VersionField: "nodeVersion",
+		Versions:     nodeVersions,
+		Defaults:     map[string]string{"buildCommand": "npm ci && npm run build", "distPath": "dist"},
+		NewConfig:    func() interface{} { return &ViteBuild{} },
+	})
+	RegisterBuildType(&BuildType{
+		Name:         "vue",
+		VersionField: "nodeVersion",
+		Versions:     nodeVersions,
+		Defaults:     map[string]string{"buildCommand": "npm ci && npm run build", "distPath": "dist"},
+		NewConfig:    func() interface{} { return &VueBuild{} },
+	})
+	RegisterBuildType(&BuildType{
+		Name:         "nextjs",
+		VersionField: "nodeVersion",
+		Versions:     nodeVersions,
+		Defaults:     map[string]string{"buildCommand": "npm ci && npm run build", "startCommand": "npm start"},
+		NewConfig:    func() interface{} { return &NextJSBuild{} },
+	})
+	RegisterBuildType(&BuildType{
+		Name:         "go",
+		VersionField: "goVersion",
+		Versions:     []string{"1.22", "1.23"},
+		Defaults:     map[string]string{"buildCommand": "go build -o app .", "binaryName": "app"},
+		NewConfig:    func() interface{} { return &GoBuild{} },
+		Validate:     validateBinaryName("go"),
+	})
+	RegisterBuildType(&BuildType{
+		Name:         "rust",
+		VersionField: "rustVersion",
+		Versions:     []string{"1.80", "1.81", "1.82"},
+		Defaults:     map[string]string{"buildCommand": "cargo build --release"},
+		NewConfig:    func() interface{} { return &RustBuild{} },
+		Validate:     validateBinaryName("rust"),
+	})
+	RegisterBuildType(&BuildType{
+		Name:         "maven",
+		VersionField: "javaVersion",
+		Versions:     javaVersions,
+		Defaults:     map[string]string{"buildCommand": "mvn package -DskipTests", "jarOutputPath": "target/*.jar"},
+		NewConfig:    func() interface{} { return &MavenBuild{} },
+	})
+	RegisterBuildType(&BuildType{
+		Name:         "django",
+		VersionField: "pythonVersion",
+		Versions:     pythonVersions,
+		Defaults:     map[string]string{"buildCommand": "pip install -r requirements.txt"},
+		NewConfig:    func() interface{} { return &DjangoBuild{} },
+	})
+	RegisterBuildType(&BuildType{
+		Name:         "flask",
+		VersionField: "pythonVersion",
+		Versions:     pythonVersions,
+		Defaults:     map[string]string{"buildCommand": "pip install -r requirements.txt", "startCommand": "gunicorn app:app"},
+		NewConfig:    func() interface{} { return &FlaskBuild{} },
+	})
+	RegisterBuildType(&BuildType{
+		Name:      "docker",
+		Defaults:  map[string]string{"dockerfilePath": "Dockerfile", "contextPath": "."},
+		NewConfig: func() interface{} { return &DockerBuild{} },
+	})
+}
+
+// validateBinaryName rejects binary names that are paths rather than file names.
+func validateBinaryName(buildType string) func(config interface{}) []errors.FieldError {
+	return func(config interface{}) []errors.FieldError {
+		var binaryName string
+		eachStringField(config, func(field string, value reflect.Value) {
+			if field == "binaryName" {
+				binaryName = value.String()
+			}
+		})
+
+		if strings.ContainsAny(binaryName, "/\\") || binaryName == "." || binaryName == ".." {
+			return []errors.FieldError{{Field: "build." + buildType + ".binaryName", Message: "must be a file name"}}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,436 @@
+package application
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/team-xquare/deployment-platform/internal/pkg/utils/errors"
+)
+
+// repositoryFiles is the part of github.RepositoryFiles build detection needs.
+type repositoryFiles interface {
+	Has(name string) bool
+	Read(ctx context.Context, path string) ([]byte, error)
+}
+
+// detection is a suggested build block before defaults are applied. Fields
+// left out are filled in from the build type's defaults.
+type detection struct {
+	buildType string
+	fields    map[string]string
+	reason    string
+}
+
+// detector recognises one kind of project. It returns nil when the repository
+// is not of its kind.
+type detector func(ctx context.Context, files repositoryFiles) (*detection, error)
+
+// detectors are tried in order. Language manifests come before the Dockerfile,
+// which many projects keep only for local development.
+var detectors = []detector{
+	detectNode,
+	detectGradle,
+	detectMaven,
+	detectGo,
+	detectRust,
+	detectPython,
+	detectDocker,
+}
+
+// DetectBuild inspects the root of the linked repository and suggests a build
+// block for it.
+func (s *Service) DetectBuild(ctx context.Context, userID uint, req GitHubConfig) (*DetectBuildResponse, error) {
+	if req.Owner == "" || req.Repo == "" {
+		return nil, errors.Validation([]errors.FieldError{{Field: "github", Message: "owner and repo are required"}})
+	}
+
+	files, err := s.githubSvc.OpenRepository(ctx, userID, req.InstallationID, req.Owner, req.Repo, req.Branch)
+	if err != nil {
+		return nil, err
+	}
+
+	found, err := detectBuild(ctx, files)
+	if err != nil {
+		return nil, err
+	}
+	if found == nil {
+		return nil, errors.NotFound("No supported build type was detected in the repository")
+	}
+
+	block, err := json.Marshal(found.fields)
+	if err != nil {
+		return nil, errors.Internal("Failed to encode build configuration")
+	}
+	buildType, build, err := normalizeBuild(BuildConfig{found.buildType: block})
+	if err != nil {
+		return nil, err
+	}
+
+	return &DetectBuildResponse{BuildType: buildType, Build: build, Reason: found.reason}, nil
+}
+
+func detectBuild(ctx context.Context, files repositoryFiles) (*detection, error) {
+	for _, detect := range detectors {
+		found, err := detect(ctx, files)
+		if err != nil || found != nil {
+			return found, err
+		}
+	}
+	return nil, nil
+}
+
+type packageJSON struct {
+	Main            string            `json:"main"`
+	Scripts         map[string]string `json:"scripts"`
+	Dependencies    map[string]string `json:"dependencies"`
+	DevDependencies map[string]string `json:"devDependencies"`
+	Engines         struct {
+		Node string `json:"node"`
+	} `json:"engines"`
+}
+
+func (p *packageJSON) dependsOn(name string) bool {
+	_, ok := p.Dependencies[name]
+	if !ok {
+		_, ok = p.DevDependencies[name]
+	}
+	return ok
+}
+
+func detectNode(ctx context.Context, files repositoryFiles) (*detection, error) {
+	if !files.Has("package.json") {
+		return nil, nil
+	}
+	content, err := files.Read(ctx, "package.json")
+	if err != nil || content == nil {
+		return nil, err
+	}
+
+	var pkg packageJSON
+	if err := json.Unmarshal(content, &pkg); err != nil {
+		return nil, errors.BadRequest("package.json is not valid JSON")
+	}
+
+	install, run := "npm install", "npm run "
+	switch {
+	case files.Has("pnpm-lock.yaml"):
+		install, run = "pnpm install --frozen-lockfile", "pnpm run "
+	case files.Has("yarn.lock"):
+		install, run = "yarn install --frozen-lockfile", "yarn run "
+	case files.Has("package-lock.json"):
+		install = "npm ci"
+	}
+
+	build := install
+	if _, ok := pkg.Scripts["build"]; ok {
+		build += " && " + run + "build"
+	}
+
+	nodeVersion := pkg.Engines.Node
+	if nodeVersion == "" && files.Has(".nvmrc") {
+		nvmrc, err := files.Read(ctx, ".nvmrc")
+		if err != nil {
+			return nil, err
+		}
+		nodeVersion = string(nvmrc)
+	}
+
+	fields := map[string]string{"buildCommand": build}
+	if version := matchVersion(nodeVersions, leadingVersion(nodeVersion, 1)); version != "" {
+		fields["nodeVersion"] = version
+	}
+
+	switch {
+	case pkg.dependsOn("next"):
+		fields["startCommand"] = run + "start"
+		return &detection{buildType: "nextjs", fields: fields, reason: "package.json depends on next"}, nil
+	case pkg.dependsOn("vue"):
+		fields["distPath"] = "dist"
+		return &detection{buildType: "vue", fields: fields, reason: "package.json depends on vue"}, nil
+	case pkg.dependsOn("vite"):
+		fields["distPath"] = "dist"
+		return &detection{buildType: "vite", fields: fields, reason: "package.json depends on vite"}, nil
+	case pkg.dependsOn("react-scripts"):
+		fields["distPath"] = "build"
+		return &detection{buildType: "react", fields: fields, reason: "package.json depends on react-scripts"}, nil
+	}
+
+	if _, ok := pkg.Scripts["start"]; ok {
+		fields["startCommand"] = run + "start"
+	} else {
+		main := pkg.Main
+		if main == "" {
+			main = "index.js"
+		}
+		fields["startCommand"] = "node " + main
+	}
+	return &detection{buildType: "nodejs", fields: fields, reason: "package.json found"}, nil
+}
+
+var gradleJavaVersionPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`JavaLanguageVersion\.of\(\s*(\d+)\s*\)`),
+	regexp.MustCompile(`(?:source|target)Compatibility\s*=\s*['"]?(?:JavaVersion\.VERSION_)?(?:1\.)?(\d+)`),
+}
+
+func detectGradle(ctx context.Context, files repositoryFiles) (*detection, error) {
+	manifest := "build.gradle"
+	if !files.Has(manifest) {
+		manifest = "build.gradle.kts"
+		if !files.Has(manifest) {
+			return nil, nil
+		}
+	}
+	content, err := files.Read(ctx, manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := map[string]string{}
+	if version := matchVersion(javaVersions, firstSubmatch(content, gradleJavaVersionPatterns)); version != "" {
+		fields["javaVersion"] = version
+	}
+	if !files.Has("gradlew") {
+		fields["buildCommand"] = "gradle build -x test"
+	}
+
+	return &detection{buildType: "gradle", fields: fields, reason: manifest + " found"}, nil
+}
+
+var mavenJavaVersionPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`<java\.version>\s*(?:1\.)?(\d+)\s*</java\.version>`),
+	regexp.MustCompile(`<maven\.compiler\.release>\s*(\d+)\s*</maven\.compiler\.release>`),
+	regexp.MustCompile(`<maven\.compiler\.source>\s*(?:1\.)?(\d+)\s*</maven\.compiler\.source>`),
+}
+
+func detectMaven(ctx context.Context, files repositoryFiles) (*detection, error) {
+	if !files.Has("pom.xml") {
+		return nil, nil
+	}
+	content, err := files.Read(ctx, "pom.xml")
+	if err != nil {
+		return nil, err
+	}
+
+	fields := map[string]string{}
+	if version := matchVersion(javaVersions, firstSubmatch(content, mavenJavaVersionPatterns)); version != "" {
+		fields["javaVersion"] = version
+	}
+	if files.Has("mvnw") {
+		fields["buildCommand"] = "./mvnw package -DskipTests"
+	}
+
+	return &detection{buildType: "maven", fields: fields, reason: "pom.xml found"}, nil
+}
+
+var goDirectivePattern = regexp.MustCompile(`(?m)^go\s+(\d+\.\d+)`)
+
+func detectGo(ctx context.Context, files repositoryFiles) (*detection, error) {
+	if !files.Has("go.mod") {
+		return nil, nil
+	}
+	content, err := files.Read(ctx, "go.mod")
+	if err != nil {
+		return nil, err
+	}
+
+	fields := map[string]string{}
+	if version := matchVersion(buildTypes["go"].Versions, firstSubmatch(content, []*regexp.Regexp{goDirectivePattern})); version != "" {
+		fields["goVersion"] = version
+	}
+
+	// Name the binary after the last element of the module path
+	for _, line := range lines(content) {
+		if module, ok := strings.CutPrefix(line, "module "); ok {
+			binary := path.Base(strings.Trim(strings.TrimSpace(module), `"`))
+			if binary != "." && binary != "/" {
+				fields["binaryName"] = binary
+				fields["buildCommand"] = "go build -o " + binary + " ."
+			}
+			break
+		}
+	}
+
+	return &detection{buildType: "go", fields: fields, reason: "go.mod found"}, nil
+}
+
+func detectRust(ctx context.Context, files repositoryFiles) (*detection, error) {
+	if !files.Has("Cargo.toml") {
+		return nil, nil
+	}
+	content, err := files.Read(ctx, "Cargo.toml")
+	if err != nil {
+		return nil, err
+	}
+
+	fields := map[string]string{}
+	section := ""
+	for _, line := range lines(content) {
+		if strings.HasPrefix(line, "[") {
+			section = strings.Trim(line, "[] ")
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok || section != "package" {
+			continue
+		}
+		value = strings.Trim(strings.TrimSpace(value), `"'`)
+		switch strings.TrimSpace(key) {
+		case "name":
+			fields["binaryName"] = value
+		case "rust-version":
+			if version := matchVersion(buildTypes["rust"].Versions, leadingVersion(value, 2)); version != "" {
+				fields["rustVersion"] = version
+			}
+		}
+	}
+
+	// Workspaces have no package of their own to name the binary after
+	if fields["binaryName"] == "" {
+		return nil, nil
+	}
+
+	return &detection{buildType: "rust", fields: fields, reason: "Cargo.toml found"}, nil
+}
+
+var (
+	djangoSettingsPattern  = regexp.MustCompile(`DJANGO_SETTINGS_MODULE['"]\s*,\s*['"]([A-Za-z0-9_]+)\.settings`)
+	runtimePythonPattern   = regexp.MustCompile(`python-(\d+\.\d+)`)
+	requirementNamePattern = regexp.MustCompile(`^[A-Za-z0-9_.-]+`)
+)
+
+func detectPython(ctx context.Context, files repositoryFiles) (*detection, error) {
+	hasManage, hasRequirements := files.Has("manage.py"), files.Has("requirements.txt")
+	if !hasManage && !hasRequirements {
+		return nil, nil
+	}
+
+	requirements := map[string]bool{}
+	if hasRequirements {
+		content, err := files.Read(ctx, "requirements.txt")
+		if err != nil {
+			return nil, err
+		}
+		for _, line := range lines(content) {
+			if name := requirementNamePattern.FindString(line); name != "" {
+				requirements[strings.ToLower(name)] = true
+			}
+		}
+	}
+
+	fields := map[string]string{}
+	if version, err := pythonVersion(ctx, files); err != nil {
+		return nil, err
+	} else if version != "" {
+		fields["pythonVersion"] = version
+	}
+
+	if hasManage || requirements["django"] {
+		project := ""
+		if hasManage {
+			content, err := files.Read(ctx, "manage.py")
+			if err != nil {
+				return nil, err
+			}
+			project = firstSubmatch(content, []*regexp.Regexp{djangoSettingsPattern})
+		}
+		// Without a settings module to go by, assume wsgi.py sits at the root
+		fields["startCommand"] = "gunicorn wsgi:application"
+		if project != "" {
+			fields["startCommand"] = "gunicorn " + project + ".wsgi"
+		}
+		if !hasRequirements {
+			fields["buildCommand"] = "pip install django gunicorn"
+		}
+
+		reason := "manage.py found"
+		if !hasManage {
+			reason = "requirements.txt depends on django"
+		}
+		return &detection{buildType: "django", fields: fields, reason: reason}, nil
+	}
+
+	if requirements["flask"] {
+		return &detection{buildType: "flask", fields: fields, reason: "requirements.txt depends on flask"}, nil
+	}
+
+	return nil, nil
+}
+
+// pythonVersion reads the version pinned in .python-version or runtime.txt.
+func pythonVersion(ctx context.Context, files repositoryFiles) (string, error) {
+	for _, name := range []string{".python-version", "runtime.txt"} {
+		if !files.Has(name) {
+			continue
+		}
+		content, err := files.Read(ctx, name)
+		if err != nil {
+			return "", err
+		}
+
+		version := leadingVersion(string(content), 2)
+		if match := runtimePythonPattern.FindSubmatch(content); match != nil {
+			version = string(match[1])
+		}
+		if version = matchVersion(pythonVersions, version); version != "" {
+			return version, nil
+		}
+	}
+	return "", nil
+}
+
+func detectDocker(ctx context.Context, files repositoryFiles) (*detection, error) {
+	if !files.Has("Dockerfile") {
+		return nil, nil
+	}
+	return &detection{buildType: "docker", fields: map[string]string{}, reason: "Dockerfile found"}, nil
+}
+
+var versionPattern = regexp.MustCompile(`\d+(\.\d+)*`)
+
+// leadingVersion returns the first version number in constraint, cut to the
+// given number of components, e.g. "^20.11.1" becomes "20" or "20.11".
+func leadingVersion(constraint string, components int) string {
+	version := versionPattern.FindString(constraint)
+	parts := strings.Split(version, ".")
+	if len(parts) > components {
+		parts = parts[:components]
+	}
+	return strings.Join(parts, ".")
+}
+
+// matchVersion returns version when it is one the build type supports, or ""
+// so that the type's default is used.
+func matchVersion(versions []string, version string) string {
+	if indexOf(versions, version) < 0 {
+		return ""
+	}
+	return version
+}
+
+func firstSubmatch(content []byte, patterns []*regexp.Regexp) string {
+	for _, pattern := range patterns {
+		if match := pattern.FindSubmatch(content); match != nil {
+			return string(match[1])
+		}
+	}
+	return ""
+}
+
+// lines returns the trimmed lines of content without blank lines and comments.
+func lines(content []byte) []string {
+	var result []string
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "//") {
+			continue
+		}
+		result = append(result, line)
+	}
+	return result
+}
@@ -0,0 +1,397 @@
+package application
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/team-xquare/deployment-platform/internal/app/audit"
+	"github.com/team-xquare/deployment-platform/internal/app/member"
+	"github.com/team-xquare/deployment-platform/internal/pkg/config"
+	"github.com/team-xquare/deployment-platform/internal/pkg/utils/errors"
+)
+
+// TXTResolver looks up the TXT records of a DNS name. *net.Resolver satisfies
+// it; tests plug in a fake with SetResolver.
+type TXTResolver interface {
+	LookupTXT(ctx context.Context, name string) ([]string, error)
+}
+
+// A domain is verified by a TXT record at _xquare-challenge.<domain> holding
+// xquare-verification=<token>.
+const (
+	domainChallengePrefix = "_xquare-challenge."
+	domainChallengeValue  = "xquare-verification="
+	domainLookupTimeout   = 10 * time.Second
+)
+
+// SetResolver replaces the resolver custom domains are verified with.
+func (s *Service) SetResolver(resolver TXTResolver) {
+	s.resolver = resolver
+}
+
+func normalizeDomain(domain string) (string, error) {
+	domain = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(domain)), ".")
+
+	message := ""
+	switch base := config.PlatformBaseDomain(); {
+	case len(domain) > 253 || !hostPattern.MatchString(domain):
+		message = "must be a valid host name"
+	case domain == base || strings.HasSuffix(domain, "."+base):
+		message = "must not be under the platform domain " + base
+	}
+	if message != "" {
+		return "", errors.Validation([]errors.FieldError{{Field: "domain", Message: message}})
+	}
+
+	return domain, nil
+}
+
+func (s *Service) GetDomains(ctx context.Context, userID, id uint) ([]*DomainResponse, error) {
+	app, err := s.findAuthorized(ctx, userID, id, member.RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	domains, err := s.repo.FindDomains(ctx, app.ID)
+	if err != nil {
+		return nil, err
+	}
+	envs, err := s.repo.FindEnvironments(ctx, app.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[uint]string, len(envs))
+	for _, env := range envs {
+		names[env.ID] = env.Name
+	}
+
+	responses := make([]*DomainResponse, len(domains))
+	for i, domain := range domains {
+		responses[i] = toDomainResponse(domain, names[domain.EnvironmentID])
+	}
+
+	return responses, nil
+}
+
+// CreateDomain attaches a domain to an endpoint and issues the token that
+// verifies it. Several applications may hold the same pending domain; only
+// the one that controls its DNS can verify it.
+func (s *Service) CreateDomain(ctx context.Context, userID, id uint, req CreateDomainRequest) (*DomainResponse, error) {
+	app, err := s.findAuthorized(ctx, userID, id, member.RoleDeveloper)
+	if err != nil {
+		return nil, err
+	}
+
+	name, err := normalizeDomain(req.Domain)
+	if err != nil {
+		return nil, err
+	}
+
+	env, err := s.domainEnvironment(ctx, app, req.Environment)
+	if err != nil {
+		return nil, err
+	}
+	if !hasPort(env, req.Port) {
+		return nil, errors.Validation([]errors.FieldError{
+			{Field: "port", Message: "must be the port of one of the environment's endpoints"},
+		})
+	}
+
+	attachments, err := s.repo.FindDomainsByName(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	for _, other := range attachments {
+		if other.EnvironmentID == env.ID {
+			return nil, errors.BadRequest("Domain " + name + " is already attached to this environment")
+		}
+		if other.Status == DomainVerified {
+			return nil, errors.BadRequest("Domain " + name + " is already in use by another application")
+		}
+	}
+
+	token, err := newDomainToken()
+	if err != nil {
+		return nil, errors.Internal("Failed to generate verification token")
+	}
+
+	domain := &CustomDomain{
+		ApplicationID: app.ID,
+		EnvironmentID: env.ID,
+		Domain:        name,
+		Port:          req.Port,
+		Token:         token,
+		Status:        DomainPending,
+	}
+	if err := s.repo.SaveDomain(ctx, domain); err != nil {
+		return nil, err
+	}
+
+	response := toDomainResponse(domain, env.Name)
+	audit.Record(ctx, audit.Event{
+		Action:       "domain.create",
+		ResourceType: "domain",
+		ResourceID:   audit.ID(domain.ID),
+		ProjectID:    &app.ProjectID,
+		After:        response,
+	})
+
+	return response, nil
+}
+
+// VerifyDomain looks up the domain's TXT record and records the outcome. A
+// failed check is not an error: the domain is returned as failed with the
+// reason and can be checked again once DNS has propagated. The environment is
+// redeployed whenever the domain starts or stops being served.
+func (s *Service) VerifyDomain(ctx context.Context, userID, id, domainID uint) (*DomainResponse, error) {
+	app, err := s.findAuthorized(ctx, userID, id, member.RoleDeveloper)
+	if err != nil {
+		return nil, err
+	}
+
+	domain, err := s.findDomain(ctx, app.ID, domainID)
+	if err != nil {
+		return nil, err
+	}
+	env, err := s.environmentByID(ctx, app.ID, domain.EnvironmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	before := toDomainResponse(domain, env.Name)
+	wasVerified := domain.Status == DomainVerified
+	lastError, err := s.checkDomain(ctx, domain)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	domain.CheckedAt = &now
+	domain.LastError = lastError
+	if lastError == "" {
+		domain.Status = DomainVerified
+		if !wasVerified {
+			domain.VerifiedAt = &now
+		}
+	} else {
+		domain.Status = DomainFailed
+		domain.VerifiedAt = nil
+	}
+
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.SaveDomain(ctx, domain); err != nil {
+			return err
+		}
+
+		if wasVerified == (domain.Status == DomainVerified) {
+			return nil
+		}
+		return s.triggerDeployment(ctx, app, env, "apply", &userID, "")
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response := toDomainResponse(domain, env.Name)
+	audit.Record(ctx, audit.Event{
+		Action:       "domain.verify",
+		ResourceType: "domain",
+		ResourceID:   audit.ID(domain.ID),
+		ProjectID:    &app.ProjectID,
+		Before:       before,
+		After:        response,
+	})
+
+	return response, nil
+}
+
+// checkDomain returns why the domain cannot be verified, or "" when it can.
+func (s *Service) checkDomain(ctx context.Context, domain *CustomDomain) (string, error) {
+	attachments, err := s.repo.FindDomainsByName(ctx, domain.Domain)
+	if err != nil {
+		return "", err
+	}
+	for _, other := range attachments {
+		if other.ID != domain.ID && other.Status == DomainVerified {
+			return "Domain is already verified by another application", nil
+		}
+	}
+
+	lookupCtx, cancel := context.WithTimeout(ctx, domainLookupTimeout)
+	defer cancel()
+
+	name := domainChallengePrefix + domain.Domain
+	records, err := s.resolver.LookupTXT(lookupCtx, name)
+	if err != nil {
+		return "TXT lookup for " + name + " failed: " + err.Error(), nil
+	}
+
+	expected := domainChallengeValue + domain.Token
+	for _, record := range records {
+		if strings.TrimSpace(record) == expected {
+			return "", nil
+		}
+	}
+
+	return "No TXT record " + name + " with the verification value was found", nil
+}
+
+// DeleteDomain detaches a domain. Routes that still use it must be removed
+// from the environment's endpoints first.
+func (s *Service) DeleteDomain(ctx context.Context, userID, id, domainID uint) error {
+	app, err := s.findAuthorized(ctx, userID, id, member.RoleDeveloper)
+	if err != nil {
+		return err
+	}
+
+	domain, err := s.findDomain(ctx, app.ID, domainID)
+	if err != nil {
+		return err
+	}
+	env, err := s.environmentByID(ctx, app.ID, domain.EnvironmentID)
+	if err != nil {
+		return err
+	}
+
+	for _, endpoint := range env.Endpoints {
+		for _, route := range endpoint.Routes {
+			if host, _, _ := parseRoute(route); host == domain.Domain {
+				return errors.BadRequest("Route " + route + " still uses " + domain.Domain + "; remove it from the environment's endpoints first")
+			}
+		}
+	}
+
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.DeleteDomain(ctx, domain.ID); err != nil {
+			return err
+		}
+
+		if domain.Status != DomainVerified {
+			return nil
+		}
+		return s.triggerDeployment(ctx, app, env, "apply", &userID, "")
+	})
+	if err != nil {
+		return err
+	}
+
+	audit.Record(ctx, audit.Event{
+		Action:       "domain.delete",
+		ResourceType: "domain",
+		ResourceID:   audit.ID(domain.ID),
+		ProjectID:    &app.ProjectID,
+		Before:       toDomainResponse(domain, env.Name),
+	})
+
+	return nil
+}
+
+// domainSpec lists the verified domains of the environment that point at one
+// of its current endpoints, for the dispatched spec.
+func (s *Service) domainSpec(ctx context.Context, env *Environment) ([]map[string]interface{}, error) {
+	domains, err := s.repo.FindVerifiedDomains(ctx, env.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var spec []map[string]interface{}
+	for _, domain := range domains {
+		if hasPort(env, domain.Port) {
+			spec = append(spec, map[string]interface{}{"domain": domain.Domain, "port": domain.Port})
+		}
+	}
+
+	return spec, nil
+}
+
+// isVerifiedDomain reports whether host is a verified domain of the environment.
+func (s *Service) isVerifiedDomain(ctx context.Context, env *Environment, host string) (bool, error) {
+	domains, err := s.repo.FindVerifiedDomains(ctx, env.ID)
+	if err != nil {
+		return false, err
+	}
+
+	for _, domain := range domains {
+		if domain.Domain == host {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// domainEnvironment resolves the environment a domain is attached to, the
+// default one when name is empty.
+func (s *Service) domainEnvironment(ctx context.Context, app *Application, name string) (*Environment, error) {
+	if name == "" {
+		return s.defaultEnvironment(ctx, app.ID)
+	}
+	return s.findEnvironment(ctx, app.ID, name)
+}
+
+func (s *Service) environmentByID(ctx context.Context, applicationID, environmentID uint) (*Environment, error) {
+	envs, err := s.repo.FindEnvironments(ctx, applicationID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, env := range envs {
+		if env.ID == environmentID {
+			return env, nil
+		}
+	}
+	return nil, errors.NotFound("Environment not found")
+}
+
+func (s *Service) findDomain(ctx context.Context, applicationID, domainID uint) (*CustomDomain, error) {
+	domains, err := s.repo.FindDomains(ctx, applicationID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, domain := range domains {
+		if domain.ID == domainID {
+			return domain, nil
+		}
+	}
+	return nil, errors.NotFound("Custom domain not found")
+}
+
+func hasPort(env *Environment, port int) bool {
+	for _, endpoint := range env.Endpoints {
+		if endpoint.Port == port {
+			return true
+		}
+	}
+	return false
+}
+
+func newDomainToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func toDomainResponse(domain *CustomDomain, environment string) *DomainResponse {
+	return &DomainResponse{
+		ID:          domain.ID,
+		Domain:      domain.Domain,
+		Environment: environment,
+		Port:        domain.Port,
+		Status:      domain.Status,
+		Verification: DomainVerification{
+			Type:  "TXT",
+			Name:  domainChallengePrefix + domain.Domain,
+			Value: domainChallengeValue + domain.Token,
+		},
+		LastError:  domain.LastError,
+		CheckedAt:  domain.CheckedAt,
+		VerifiedAt: domain.VerifiedAt,
+		CreatedAt:  domain.CreatedAt,
+	}
+}
@@ -0,0 +1,225 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/team-xquare/deployment-platform/internal/app/member"
+)
+
+// fakeRepository keeps applications, environments and custom domains in
+// memory. Methods the domain flow does not use are left to the embedded nil
+// interface and panic if called.
+type fakeRepository struct {
+	Repository
+	apps    map[uint]*Application
+	envs    map[uint][]*Environment
+	domains []*CustomDomain
+}
+
+func (r *fakeRepository) FindByID(ctx context.Context, id uint) (*Application, error) {
+	app, ok := r.apps[id]
+	if !ok {
+		return nil, fmt.Errorf("application %d not found", id)
+	}
+	return app, nil
+}
+
+func (r *fakeRepository) FindEnvironments(ctx context.Context, applicationID uint) ([]*Environment, error) {
+	return r.envs[applicationID], nil
+}
+
+func (r *fakeRepository) SaveDomain(ctx context.Context, domain *CustomDomain) error {
+	if domain.ID == 0 {
+		domain.ID = uint(len(r.domains) + 1)
+		r.domains = append(r.domains, domain)
+	}
+	return nil
+}
+
+func (r *fakeRepository) FindDomains(ctx context.Context, applicationID uint) ([]*CustomDomain, error) {
+	var domains []*CustomDomain
+	for _, domain := range r.domains {
+		if domain.ApplicationID == applicationID {
+			domains = append(domains, domain)
+		}
+	}
+	return domains, nil
+}
+
+func (r *fakeRepository) FindDomainsByName(ctx context.Context, name string) ([]*CustomDomain, error) {
+	var domains []*CustomDomain
+	for _, domain := range r.domains {
+		if domain.Domain == name {
+			domains = append(domains, domain)
+		}
+	}
+	return domains, nil
+}
+
+func (r *fakeRepository) FindVerifiedDomains(ctx context.Context, environmentID uint) ([]*CustomDomain, error) {
+	var domains []*CustomDomain
+	for _, domain := range r.domains {
+		if domain.EnvironmentID == environmentID && domain.Status == DomainVerified {
+			domains = append(domains, domain)
+		}
+	}
+	return domains, nil
+}
+
+// fakeMembers makes every user an owner of every project.
+type fakeMembers struct {
+	member.Repository
+}
+
+func (fakeMembers) FindByProjectAndUser(ctx context.Context, projectID, userID uint) (*member.Member, error) {
+	return &member.Member{ProjectID: projectID, UserID: userID, Role: member.RoleOwner}, nil
+}
+
+type fakeTransactor struct{}
+
+func (fakeTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+// fakeResolver answers TXT lookups from a map; names it does not hold fail.
+type fakeResolver map[string][]string
+
+func (r fakeResolver) LookupTXT(ctx context.Context, name string) ([]string, error) {
+	records, ok := r[name]
+	if !ok {
+		return nil, fmt.Errorf("no such host %s", name)
+	}
+	return records, nil
+}
+
+// newDomainTestService returns a service with one application per ID, each
+// with a default environment listening on port 8080. The applications have no
+// GitHub repository, so verification changes deploy nothing.
+func newDomainTestService(resolver fakeResolver, appIDs ...uint) *Service {
+	repo := &fakeRepository{apps: map[uint]*Application{}, envs: map[uint][]*Environment{}}
+	for _, id := range appIDs {
+		repo.apps[id] = &Application{ID: id, ProjectID: 1, Name: fmt.Sprintf("app-%d", id)}
+		repo.envs[id] = []*Environment{{
+			ID:            id,
+			ApplicationID: id,
+			Name:          DefaultEnvironmentName,
+			Endpoints:     []EndpointConfig{{Port: 8080}},
+			IsDefault:     true,
+		}}
+	}
+
+	s := NewService(repo, nil, fakeTransactor{}, nil, nil, nil, member.NewService(fakeMembers{}, nil, fakeTransactor{}), nil, nil, nil, nil)
+	s.SetResolver(resolver)
+	return s
+}
+
+func TestVerifyDomainTransitions(t *testing.T) {
+	ctx := context.Background()
+	resolver := fakeResolver{}
+	s := newDomainTestService(resolver, 1)
+
+	created, err := s.CreateDomain(ctx, 1, 1, CreateDomainRequest{Domain: "API.Example.com.", Port: 8080})
+	if err != nil {
+		t.Fatalf("CreateDomain: %v", err)
+	}
+	if created.Domain != "api.example.com" {
+		t.Errorf("domain = %q, want it normalized to api.example.com", created.Domain)
+	}
+	if created.Status != DomainPending {
+		t.Fatalf("status after create = %q, want %q", created.Status, DomainPending)
+	}
+
+	// Without the TXT record the check fails and can be retried
+	failed, err := s.VerifyDomain(ctx, 1, 1, created.ID)
+	if err != nil {
+		t.Fatalf("VerifyDomain: %v", err)
+	}
+	if failed.Status != DomainFailed || failed.LastError == "" || failed.VerifiedAt != nil {
+		t.Fatalf("without a record got status %q, error %q, verified at %v; want a failed check", failed.Status, failed.LastError, failed.VerifiedAt)
+	}
+
+	resolver[created.Verification.Name] = []string{"unrelated", created.Verification.Value}
+	verified, err := s.VerifyDomain(ctx, 1, 1, created.ID)
+	if err != nil {
+		t.Fatalf("VerifyDomain: %v", err)
+	}
+	if verified.Status != DomainVerified || verified.LastError != "" || verified.VerifiedAt == nil {
+		t.Fatalf("with the record got status %q, error %q, verified at %v; want verified", verified.Status, verified.LastError, verified.VerifiedAt)
+	}
+
+	// A verified domain whose record disappears stops being served
+	delete(resolver, created.Verification.Name)
+	lost, err := s.VerifyDomain(ctx, 1, 1, created.ID)
+	if err != nil {
+		t.Fatalf("VerifyDomain: %v", err)
+	}
+	if lost.Status != DomainFailed || lost.VerifiedAt != nil {
+		t.Fatalf("after the record was removed got status %q, verified at %v; want failed", lost.Status, lost.VerifiedAt)
+	}
+}
+
+func TestVerifyDomainRejectsWrongToken(t *testing.T) {
+	ctx := context.Background()
+	resolver := fakeResolver{}
+	s := newDomainTestService(resolver, 1)
+
+	created, err := s.CreateDomain(ctx, 1, 1, CreateDomainRequest{Domain: "api.example.com", Port: 8080})
+	if err != nil {
+		t.Fatalf("CreateDomain: %v", err)
+	}
+
+	resolver[created.Verification.Name] = []string{domainChallengeValue + "not-the-token"}
+	got, err := s.VerifyDomain(ctx, 1, 1, created.ID)
+	if err != nil {
+		t.Fatalf("VerifyDomain: %v", err)
+	}
+	if got.Status != DomainFailed {
+		t.Fatalf("status = %q, want %q", got.Status, DomainFailed)
+	}
+}
+
+func TestDomainHasSingleVerifiedAttachment(t *testing.T) {
+	ctx := context.Background()
+	resolver := fakeResolver{}
+	s := newDomainTestService(resolver, 1, 2, 3)
+
+	// Several applications may hold the same pending domain
+	first, err := s.CreateDomain(ctx, 1, 1, CreateDomainRequest{Domain: "shared.example.com", Port: 8080})
+	if err != nil {
+		t.Fatalf("CreateDomain for the first application: %v", err)
+	}
+	second, err := s.CreateDomain(ctx, 1, 2, CreateDomainRequest{Domain: "shared.example.com", Port: 8080})
+	if err != nil {
+		t.Fatalf("CreateDomain for the second application: %v", err)
+	}
+
+	resolver[first.Verification.Name] = []string{first.Verification.Value, second.Verification.Value}
+
+	got, err := s.VerifyDomain(ctx, 1, 1, first.ID)
+	if err != nil {
+		t.Fatalf("VerifyDomain for the first application: %v", err)
+	}
+	if got.Status != DomainVerified {
+		t.Fatalf("first attachment status = %q, want %q", got.Status, DomainVerified)
+	}
+
+	// Even with its own token published, the second attachment cannot be verified
+	got, err = s.VerifyDomain(ctx, 1, 2, second.ID)
+	if err != nil {
+		t.Fatalf("VerifyDomain for the second application: %v", err)
+	}
+	if got.Status != DomainFailed {
+		t.Fatalf("second attachment status = %q, want %q", got.Status, DomainFailed)
+	}
+
+	if _, err := s.CreateDomain(ctx, 1, 3, CreateDomainRequest{Domain: "shared.example.com", Port: 8080}); err == nil {
+		t.Fatal("CreateDomain of a verified domain for another application succeeded, want an error")
+	}
+
+	// Attaching the same domain twice to one environment is refused too
+	if _, err := s.CreateDomain(ctx, 1, 1, CreateDomainRequest{Domain: "shared.example.com", Port: 8080}); err == nil {
+		t.Fatal("CreateDomain of a domain already attached to the environment succeeded, want an error")
+	}
+}
@@ -1,12 +1,18 @@
 package application
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/team-xquare/deployment-platform/internal/pkg/utils/diff"
+	"github.com/team-xquare/deployment-platform/internal/pkg/utils/pagination"
+)
 
 type CreateApplicationRequest struct {
 	Name      string           `json:"name" binding:"required"`
 	Tier      string           `json:"tier" binding:"required"`
 	GitHub    *GitHubConfig    `json:"github"`
-	Build     *BuildConfig     `json:"build"`
+	Build     BuildConfig      `json:"build"`
 	Endpoints []EndpointConfig `json:"endpoints"`
 }
 
@@ -14,7 +20,7 @@ type UpdateApplicationRequest struct {
 	Name      string           `json:"name" binding:"required"`
 	Tier      string           `json:"tier" binding:"required"`
 	GitHub    *GitHubConfig    `json:"github"`
-	Build     *BuildConfig     `json:"build"`
+	Build     BuildConfig      `json:"build"`
 	Endpoints []EndpointConfig `json:"endpoints"`
 }
 
@@ -26,19 +32,26 @@ type GitHubConfig struct {
 	TriggerPaths   []string `json:"triggerPaths,omitempty"`
 }
 
-type BuildConfig struct {
-	Gradle *GradleBuild `json:"gradle,omitempty"`
-	NodeJS *NodeJSBuild `json:"nodejs,omitempty"`
-	React  *ReactBuild  `json:"react,omitempty"`
-	Vite   *ViteBuild   `json:"vite,omitempty"`
-	Vue    *VueBuild    `json:"vue,omitempty"`
-	NextJS *NextJSBuild `json:"nextjs,omitempty"`
-	Go     *GoBuild     `json:"go,omitempty"`
-	Rust   *RustBuild   `json:"rust,omitempty"`
-	Maven  *MavenBuild  `json:"maven,omitempty"`
-	Django *DjangoBuild `json:"django,omitempty"`
-	Flask  *FlaskBuild  `json:"flask,omitempty"`
-	Docker *DockerBuild `json:"docker,omitempty"`
+// BuildConfig holds exactly one build block keyed by its build type, e.g.
+// {"gradle": {"javaVersion": "21"}}. Blocks are decoded and validated by the
+// build type registry.
+type BuildConfig map[string]json.RawMessage
+
+// BuildTypeResponse describes a registered build type; Schema is a JSON Schema
+// for its build block.
+type BuildTypeResponse struct {
+	Name           string                 `json:"name"`
+	Versions       []string               `json:"versions,omitempty"`
+	DefaultVersion string                 `json:"default_version,omitempty"`
+	Schema         map[string]interface{} `json:"schema"`
+}
+
+// DetectBuildResponse is a build block suggested for a repository, with the
+// defaults of its build type applied.
+type DetectBuildResponse struct {
+	BuildType string      `json:"build_type"`
+	Build     BuildConfig `json:"build"`
+	Reason    string      `json:"reason"`
 }
 
 type ApplicationResponse struct {
@@ -47,8 +60,111 @@ type ApplicationResponse struct {
 	Name      string           `json:"name"`
 	Tier      string           `json:"tier"`
 	GitHub    *GitHubConfig    `json:"github,omitempty"`
-	Build     *BuildConfig     `json:"build,omitempty"`
+	Build     BuildConfig      `json:"build,omitempty"`
+	Endpoints []EndpointConfig `json:"endpoints,omitempty"`
+	Hostname  string           `json:"hostname,omitempty"`
+	CreatedAt time.Time        `json:"created_at"`
+	UpdatedAt time.Time        `json:"updated_at"`
+}
+
+type ApplicationListResponse struct {
+	Applications []*ApplicationResponse `json:"applications"`
+	pagination.Page
+}
+type CreateEnvironmentRequest struct {
+	Name      string           `json:"name" binding:"required"`
+	Branch    string           `json:"branch"`
+	Tier      string           `json:"tier" binding:"required"`
+	Endpoints []EndpointConfig `json:"endpoints"`
+}
+
+type UpdateEnvironmentRequest struct {
+	Branch    string           `json:"branch"`
+	Tier      string           `json:"tier" binding:"required"`
+	Endpoints []EndpointConfig `json:"endpoints"`
+}
+
+type EnvironmentResponse struct {
+	ID        uint             `json:"id"`
+	Name      string           `json:"name"`
+	Branch    string           `json:"branch,omitempty"`
+	Tier      string           `json:"tier"`
 	Endpoints []EndpointConfig `json:"endpoints,omitempty"`
+	Hostname  string           `json:"hostname,omitempty"`
+	IsDefault bool             `json:"is_default"`
 	CreatedAt time.Time        `json:"created_at"`
 	UpdatedAt time.Time        `json:"updated_at"`
-}
\ No newline at end of file
+}
+
+// CreateDomainRequest attaches Domain to the endpoint listening on Port of an
+// environment, the default one when Environment is empty.
+type CreateDomainRequest struct {
+	Domain      string `json:"domain" binding:"required"`
+	Port        int    `json:"port" binding:"required"`
+	Environment string `json:"environment"`
+}
+
+type DomainResponse struct {
+	ID           uint               `json:"id"`
+	Domain       string             `json:"domain"`
+	Environment  string             `json:"environment"`
+	Port         int                `json:"port"`
+	Status       string             `json:"status"`
+	Verification DomainVerification `json:"verification"`
+	LastError    string             `json:"last_error,omitempty"`
+	CheckedAt    *time.Time         `json:"checked_at,omitempty"`
+	VerifiedAt   *time.Time         `json:"verified_at,omitempty"`
+	CreatedAt    time.Time          `json:"created_at"`
+}
+
+// DomainVerification is the DNS record that proves control of a domain.
+type DomainVerification struct {
+	Type  string `json:"type"`
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type CreateEnvVarRequest struct {
+	Name   string `json:"name" binding:"required"`
+	Value  string `json:"value"`
+	Secret bool   `json:"secret"`
+}
+
+type UpdateEnvVarRequest struct {
+	Value  string `json:"value"`
+	Secret bool   `json:"secret"`
+}
+
+// EnvVarResponse masks the value of secrets.
+type EnvVarResponse struct {
+	Name      string    `json:"name"`
+	Value     string    `json:"value"`
+	Secret    bool      `json:"secret"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type RollbackRequest struct {
+	Revision int `json:"revision" binding:"required"`
+}
+
+type RevisionResponse struct {
+	Number     int            `json:"number"`
+	Config     RevisionConfig `json:"config"`
+	RollbackOf *int           `json:"rollback_of,omitempty"`
+	CreatedBy  *uint          `json:"created_by"`
+	CreatedAt  time.Time      `json:"created_at"`
+}
+
+type RevisionListResponse struct {
+	Revisions []*RevisionResponse `json:"revisions"`
+	pagination.Page
+}
+
+// RevisionDiffResponse lists the changes from one revision to another; an
+// empty list means they hold the same configuration.
+type RevisionDiffResponse struct {
+	From    int           `json:"from"`
+	To      int           `json:"to"`
+	Changes []diff.Change `json:"changes"`
+}
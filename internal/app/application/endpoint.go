@@ -0,0 +1,237 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/team-xquare/deployment-platform/internal/pkg/config"
+	"github.com/team-xquare/deployment-platform/internal/pkg/utils/errors"
+)
+
+var (
+	hostPattern         = regexp.MustCompile(`^([a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?\.)+[a-z]([a-z0-9-]{0,61}[a-z0-9])?$`)
+	labelInvalidPattern = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+// maxHostnameAttempts bounds the numbered suffixes tried when a platform
+// hostname is already taken.
+const maxHostnameAttempts = 100
+
+// normalizeEndpoints checks ports and routes and returns the endpoints with
+// their routes normalized. A route is either a path, served on the
+// environment's platform hostname, or a host followed by an optional path.
+func normalizeEndpoints(endpoints []EndpointConfig) ([]EndpointConfig, error) {
+	var fieldErrors []errors.FieldError
+	ports := map[int]bool{}
+	seen := map[string]bool{}
+
+	normalized := make([]EndpointConfig, len(endpoints))
+	for i, endpoint := range endpoints {
+		field := fmt.Sprintf("endpoints[%d]", i)
+
+		switch {
+		case endpoint.Port < 1 || endpoint.Port > 65535:
+			fieldErrors = append(fieldErrors, errors.FieldError{Field: field + ".port", Message: "must be between 1 and 65535"})
+		case ports[endpoint.Port]:
+			fieldErrors = append(fieldErrors, errors.FieldError{Field: field + ".port", Message: "is used by another endpoint"})
+		}
+		ports[endpoint.Port] = true
+
+		routes := make([]string, 0, len(endpoint.Routes))
+		for j, route := range endpoint.Routes {
+			routeField := field + ".routes[" + strconv.Itoa(j) + "]"
+
+			host, routePath, message := parseRoute(route)
+			switch {
+			case message != "":
+				fieldErrors = append(fieldErrors, errors.FieldError{Field: routeField, Message: message})
+				continue
+			case seen[host+routePath]:
+				fieldErrors = append(fieldErrors, errors.FieldError{Field: routeField, Message: "is listed more than once"})
+				continue
+			}
+			seen[host+routePath] = true
+			routes = append(routes, host+routePath)
+		}
+
+		normalized[i] = EndpointConfig{Port: endpoint.Port, Routes: routes}
+	}
+
+	if len(fieldErrors) > 0 {
+		return nil, errors.Validation(fieldErrors)
+	}
+	return normalized, nil
+}
+
+// parseRoute splits a route into a lower-case host, which is empty for path
+// routes, and a clean absolute path. It returns a message when the route is
+// invalid.
+func parseRoute(route string) (host, routePath, message string) {
+	route = strings.TrimSpace(route)
+	for _, scheme := range []string{"https://", "http://"} {
+		if len(route) >= len(scheme) && strings.EqualFold(route[:len(scheme)], scheme) {
+			route = route[len(scheme):]
+		}
+	}
+	if route == "" {
+		return "", "", "must not be empty"
+	}
+	if strings.ContainsAny(route, "?# \t") {
+		return "", "", "must be a host and path without query, fragment or spaces"
+	}
+
+	routePath = route
+	if !strings.HasPrefix(route, "/") {
+		var rest string
+		host, rest, _ = strings.Cut(route, "/")
+		host = strings.ToLower(host)
+		routePath = "/" + rest
+
+		if len(host) > 253 || !hostPattern.MatchString(host) {
+			return "", "", "must start with / or a valid host name"
+		}
+		if domain := config.PlatformBaseDomain(); host == domain || strings.HasSuffix(host, "."+domain) {
+			return "", "", "must not use the platform domain; use a path to route on the application's own hostname"
+		}
+	}
+
+	routePath = path.Clean(routePath)
+	if len(routePath) > 255 {
+		return "", "", "must have a path of at most 255 characters"
+	}
+
+	return host, routePath, ""
+}
+
+// saveEnvironment saves the environment, allocating its platform hostname on
+// first save, and reserves its routes. It must run inside a transaction.
+func (s *Service) saveEnvironment(ctx context.Context, app *Application, env *Environment) error {
+	if env.Hostname == "" {
+		proj, err := s.projectRepo.FindByID(ctx, app.ProjectID)
+		if err != nil {
+			return err
+		}
+		hostname, err := s.allocateHostname(ctx, hostnameLabel(app.Name, env, proj.Slug))
+		if err != nil {
+			return err
+		}
+		env.Hostname = hostname
+	}
+
+	if err := s.repo.SaveEnvironment(ctx, env); err != nil {
+		return err
+	}
+	if env.IsDefault {
+		app.Hostname = env.Hostname
+	}
+
+	return s.reserveRoutes(ctx, env)
+}
+
+// hostnameLabel is the first DNS label of an environment's hostname:
+// <app>-<project slug> for the default environment and <app>-<env>-<project
+// slug> for the others.
+func hostnameLabel(appName string, env *Environment, projectSlug string) string {
+	parts := []string{appName}
+	if !env.IsDefault {
+		parts = append(parts, env.Name)
+	}
+	parts = append(parts, projectSlug)
+
+	label := labelInvalidPattern.ReplaceAllString(strings.ToLower(strings.Join(parts, "-")), "-")
+	return strings.Trim(label, "-")
+}
+
+// allocateHostname returns the first free hostname for label under the
+// platform domain, numbering the label when it is taken. Hostnames are kept
+// when an application is renamed, so links to it keep working.
+func (s *Service) allocateHostname(ctx context.Context, label string) (string, error) {
+	for attempt := 1; attempt <= maxHostnameAttempts; attempt++ {
+		candidate := strings.TrimRight(truncate(label, 63), "-")
+		if attempt > 1 {
+			suffix := "-" + strconv.Itoa(attempt)
+			candidate = strings.TrimRight(truncate(label, 63-len(suffix)), "-") + suffix
+		}
+		hostname := candidate + "." + config.PlatformBaseDomain()
+
+		existing, err := s.repo.FindEnvironmentByHostname(ctx, hostname)
+		if err != nil {
+			return "", err
+		}
+		if existing == nil {
+			return hostname, nil
+		}
+	}
+
+	return "", errors.Internal("Failed to allocate a hostname for " + label)
+}
+
+// reserveRoutes claims the environment's routes, failing when another
+// environment already serves the same host and path or when a new route's host
+// is not one of the environment's verified custom domains. Routes the
+// environment already holds are kept, so host routes reserved before custom
+// domains were verified do not block later deployments.
+func (s *Service) reserveRoutes(ctx context.Context, env *Environment) error {
+	var routes []*Route
+	var fieldErrors []errors.FieldError
+
+	for i, endpoint := range env.Endpoints {
+		for j, route := range endpoint.Routes {
+			// Routes saved before endpoints were validated may not parse; they are not reserved
+			host, routePath, message := parseRoute(route)
+			if message != "" {
+				continue
+			}
+			field := fmt.Sprintf("endpoints[%d].routes[%d]", i, j)
+			customHost := host != ""
+			if !customHost {
+				host = env.Hostname
+			}
+
+			existing, err := s.repo.FindRoute(ctx, host, routePath)
+			if err != nil {
+				return err
+			}
+			if existing != nil && existing.EnvironmentID != env.ID {
+				fieldErrors = append(fieldErrors, errors.FieldError{
+					Field:   field,
+					Message: "is already used by another application or environment",
+				})
+				continue
+			}
+
+			if customHost && existing == nil {
+				verified, err := s.isVerifiedDomain(ctx, env, host)
+				if err != nil {
+					return err
+				}
+				if !verified {
+					fieldErrors = append(fieldErrors, errors.FieldError{
+						Field:   field,
+						Message: "must use a verified custom domain of the environment",
+					})
+					continue
+				}
+			}
+
+			routes = append(routes, &Route{EnvironmentID: env.ID, Host: host, Path: routePath})
+		}
+	}
+
+	if len(fieldErrors) > 0 {
+		return errors.Validation(fieldErrors)
+	}
+
+	return s.repo.ReplaceRoutes(ctx, env.ID, routes)
+}
+
+func truncate(s string, n int) string {
+	if len(s) > n {
+		return s[:n]
+	}
+	return s
+}
@@ -0,0 +1,342 @@
+package application
+
+import (
+	"context"
+	"regexp"
+
+	"github.com/team-xquare/deployment-platform/internal/app/audit"
+	"github.com/team-xquare/deployment-platform/internal/app/member"
+	"github.com/team-xquare/deployment-platform/internal/pkg/utils/crypto"
+	"github.com/team-xquare/deployment-platform/internal/pkg/utils/errors"
+)
+
+const maskedValue = "********"
+
+var envNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+func validateEnvName(name string) error {
+	if len(name) > 255 || !envNamePattern.MatchString(name) {
+		return errors.Validation([]errors.FieldError{
+			{Field: "name", Message: "must be letters, digits or underscores and not start with a digit"},
+		})
+	}
+	return nil
+}
+
+// GetEnvVars lists the variables of one environment. Like the other variable
+// methods it takes an environment name, where an empty name addresses the
+// variables shared by every environment of the application.
+func (s *Service) GetEnvVars(ctx context.Context, userID, id uint, environment string) ([]*EnvVarResponse, error) {
+	app, err := s.findAuthorized(ctx, userID, id, member.RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	env, err := s.envScope(ctx, app, environment)
+	if err != nil {
+		return nil, err
+	}
+
+	envVars, err := s.repo.FindEnvVars(ctx, app.ID, environmentID(env))
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*EnvVarResponse, len(envVars))
+	for i, envVar := range envVars {
+		responses[i] = toEnvVarResponse(envVar)
+	}
+
+	return responses, nil
+}
+
+func (s *Service) CreateEnvVar(ctx context.Context, userID, id uint, environment string, req CreateEnvVarRequest) (*EnvVarResponse, error) {
+	app, err := s.findAuthorized(ctx, userID, id, member.RoleDeveloper)
+	if err != nil {
+		return nil, err
+	}
+
+	env, err := s.envScope(ctx, app, environment)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateEnvName(req.Name); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.repo.FindEnvVar(ctx, app.ID, environmentID(env), req.Name)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, errors.BadRequest("Environment variable " + req.Name + " already exists")
+	}
+
+	envVar := &EnvVar{ApplicationID: app.ID, EnvironmentID: environmentID(env), Name: req.Name}
+	if err := s.setEnvValue(envVar, req.Value, req.Secret); err != nil {
+		return nil, err
+	}
+
+	if err := s.saveEnvVar(ctx, app, env, envVar, userID); err != nil {
+		return nil, err
+	}
+
+	response := toEnvVarResponse(envVar)
+	audit.Record(ctx, audit.Event{
+		Action:       "env_var.create",
+		ResourceType: "env_var",
+		ResourceID:   audit.ID(envVar.ID),
+		ProjectID:    &app.ProjectID,
+		After:        response,
+	})
+
+	return response, nil
+}
+
+func (s *Service) UpdateEnvVar(ctx context.Context, userID, id uint, environment, name string, req UpdateEnvVarRequest) (*EnvVarResponse, error) {
+	app, err := s.findAuthorized(ctx, userID, id, member.RoleDeveloper)
+	if err != nil {
+		return nil, err
+	}
+
+	env, err := s.envScope(ctx, app, environment)
+	if err != nil {
+		return nil, err
+	}
+
+	envVar, err := s.findEnvVar(ctx, app.ID, environmentID(env), name)
+	if err != nil {
+		return nil, err
+	}
+	before := toEnvVarResponse(envVar)
+	if err := s.setEnvValue(envVar, req.Value, req.Secret); err != nil {
+		return nil, err
+	}
+
+	if err := s.saveEnvVar(ctx, app, env, envVar, userID); err != nil {
+		return nil, err
+	}
+
+	response := toEnvVarResponse(envVar)
+	audit.Record(ctx, audit.Event{
+		Action:       "env_var.update",
+		ResourceType: "env_var",
+		ResourceID:   audit.ID(envVar.ID),
+		ProjectID:    &app.ProjectID,
+		Before:       before,
+		After:        response,
+	})
+
+	return response, nil
+}
+
+func (s *Service) DeleteEnvVar(ctx context.Context, userID, id uint, environment, name string) error {
+	app, err := s.findAuthorized(ctx, userID, id, member.RoleDeveloper)
+	if err != nil {
+		return err
+	}
+
+	env, err := s.envScope(ctx, app, environment)
+	if err != nil {
+		return err
+	}
+
+	envVar, err := s.findEnvVar(ctx, app.ID, environmentID(env), name)
+	if err != nil {
+		return err
+	}
+
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.DeleteEnvVar(ctx, envVar.ID); err != nil {
+			return err
+		}
+
+		return s.redeployScope(ctx, app, env, userID)
+	})
+	if err != nil {
+		return err
+	}
+
+	audit.Record(ctx, audit.Event{
+		Action:       "env_var.delete",
+		ResourceType: "env_var",
+		ResourceID:   audit.ID(envVar.ID),
+		ProjectID:    &app.ProjectID,
+		Before:       toEnvVarResponse(envVar),
+	})
+
+	return nil
+}
+
+// ReEncryptSecrets seals every application secret and addon binding password
+// that is not yet encrypted with the primary key, so that retired keys can be
+// removed from SECRETS_ENCRYPTION_KEYS. It returns how many values changed.
+func (s *Service) ReEncryptSecrets(ctx context.Context) (int, error) {
+	envVars, err := s.repo.FindSecretEnvVars(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	updated := 0
+	for _, envVar := range envVars {
+		if !s.keyring.NeedsReEncryption(envVar.Value) {
+			continue
+		}
+
+		value, err := s.secretValue(envVar)
+		if err != nil {
+			return updated, err
+		}
+		if err := s.setEnvValue(envVar, value, true); err != nil {
+			return updated, err
+		}
+		if err := s.repo.SaveEnvVar(ctx, envVar); err != nil {
+			return updated, err
+		}
+		updated++
+	}
+
+	bindings, err := s.addonSvc.ReEncryptBindings(ctx)
+	if err != nil {
+		return updated + bindings, err
+	}
+
+	audit.Record(ctx, audit.Event{
+		Action:       "secrets.re_encrypt",
+		ResourceType: "secrets",
+		After:        map[string]int{"updated": updated + bindings},
+	})
+
+	return updated + bindings, nil
+}
+
+// runtimeEnv returns the variables an environment is deployed with, split into
+// plain values and decrypted secrets. Variables of the environment override
+// the application-wide ones, which override the connection variables of the
+// application's bound addons.
+func (s *Service) runtimeEnv(ctx context.Context, app *Application, environment *Environment) (env, secrets map[string]string, err error) {
+	env, secrets, err = s.addonSvc.ConnectionEnv(ctx, app.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	shared, err := s.repo.FindEnvVars(ctx, app.ID, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	own, err := s.repo.FindEnvVars(ctx, app.ID, &environment.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, envVar := range append(shared, own...) {
+		delete(env, envVar.Name)
+		delete(secrets, envVar.Name)
+
+		if !envVar.Secret {
+			env[envVar.Name] = envVar.Value
+			continue
+		}
+
+		value, err := s.secretValue(envVar)
+		if err != nil {
+			return nil, nil, err
+		}
+		secrets[envVar.Name] = value
+	}
+
+	return env, secrets, nil
+}
+
+// secretValue decrypts a secret variable's value. Secrets saved before they
+// were encrypted at rest hold it in plain text until re-encrypted.
+func (s *Service) secretValue(envVar *EnvVar) (string, error) {
+	if !crypto.IsEncrypted(envVar.Value) {
+		return envVar.Value, nil
+	}
+
+	value, err := s.keyring.Decrypt(envVar.Value)
+	if err != nil {
+		return "", errors.Internal("Failed to decrypt secret " + envVar.Name)
+	}
+	return value, nil
+}
+
+// envScope resolves the environment named by the request, or nil for the
+// application-wide scope.
+func (s *Service) envScope(ctx context.Context, app *Application, environment string) (*Environment, error) {
+	if environment == "" {
+		return nil, nil
+	}
+	return s.findEnvironment(ctx, app.ID, environment)
+}
+
+func environmentID(env *Environment) *uint {
+	if env == nil {
+		return nil
+	}
+	return &env.ID
+}
+
+// redeployScope redeploys the environment whose variables changed, or every
+// environment after an application-wide change.
+func (s *Service) redeployScope(ctx context.Context, app *Application, env *Environment, userID uint) error {
+	if env == nil {
+		return s.deployEnvironments(ctx, app, "apply", &userID)
+	}
+	return s.triggerDeployment(ctx, app, env, "apply", &userID, "")
+}
+
+func (s *Service) findEnvVar(ctx context.Context, applicationID uint, environmentID *uint, name string) (*EnvVar, error) {
+	envVar, err := s.repo.FindEnvVar(ctx, applicationID, environmentID, name)
+	if err != nil {
+		return nil, err
+	}
+	if envVar == nil {
+		return nil, errors.NotFound("Environment variable not found")
+	}
+	return envVar, nil
+}
+
+// setEnvValue stores value on envVar, encrypting it when it is a secret.
+func (s *Service) setEnvValue(envVar *EnvVar, value string, secret bool) error {
+	envVar.Secret = secret
+	if !secret {
+		envVar.Value = value
+		return nil
+	}
+
+	encrypted, err := s.keyring.Encrypt(value)
+	if err != nil {
+		return errors.Internal("Failed to encrypt secret")
+	}
+	envVar.Value = encrypted
+	return nil
+}
+
+// saveEnvVar saves the variable and redeploys the environments it applies to.
+func (s *Service) saveEnvVar(ctx context.Context, app *Application, env *Environment, envVar *EnvVar, userID uint) error {
+	return s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.SaveEnvVar(ctx, envVar); err != nil {
+			return err
+		}
+
+		return s.redeployScope(ctx, app, env, userID)
+	})
+}
+
+func toEnvVarResponse(envVar *EnvVar) *EnvVarResponse {
+	value := envVar.Value
+	if envVar.Secret {
+		value = maskedValue
+	}
+
+	return &EnvVarResponse{
+		Name:      envVar.Name,
+		Value:     value,
+		Secret:    envVar.Secret,
+		CreatedAt: envVar.CreatedAt,
+		UpdatedAt: envVar.UpdatedAt,
+	}
+}
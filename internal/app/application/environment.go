@@ -0,0 +1,272 @@
+package application
+
+import (
+	"context"
+	"regexp"
+
+	"github.com/team-xquare/deployment-platform/internal/app/audit"
+	"github.com/team-xquare/deployment-platform/internal/app/deployment"
+	"github.com/team-xquare/deployment-platform/internal/app/member"
+	"github.com/team-xquare/deployment-platform/internal/pkg/utils/errors"
+	"github.com/team-xquare/deployment-platform/internal/pkg/utils/pagination"
+)
+
+// Application and environment names are used as directories in the
+// infrastructure config repository and in hostnames, so they follow the same
+// DNS label rules as project slugs.
+var namePattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]*[a-z0-9])?$`)
+
+func validateName(name string) error {
+	if len(name) > 63 || !namePattern.MatchString(name) {
+		return errors.Validation([]errors.FieldError{
+			{Field: "name", Message: "must be at most 63 lowercase letters, digits or hyphens and start and end with a letter or digit"},
+		})
+	}
+	return nil
+}
+
+func (s *Service) GetEnvironments(ctx context.Context, userID, id uint) ([]*EnvironmentResponse, error) {
+	app, err := s.findAuthorized(ctx, userID, id, member.RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	envs, err := s.repo.FindEnvironments(ctx, app.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*EnvironmentResponse, len(envs))
+	for i, env := range envs {
+		responses[i] = toEnvironmentResponse(env)
+	}
+
+	return responses, nil
+}
+
+func (s *Service) GetEnvironment(ctx context.Context, userID, id uint, name string) (*EnvironmentResponse, error) {
+	app, err := s.findAuthorized(ctx, userID, id, member.RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	env, err := s.findEnvironment(ctx, app.ID, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return toEnvironmentResponse(env), nil
+}
+
+func (s *Service) CreateEnvironment(ctx context.Context, userID, id uint, req CreateEnvironmentRequest) (*EnvironmentResponse, error) {
+	app, err := s.findAuthorized(ctx, userID, id, member.RoleDeveloper)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateName(req.Name); err != nil {
+		return nil, err
+	}
+	endpoints, err := normalizeEndpoints(req.Endpoints)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.repo.FindEnvironment(ctx, app.ID, req.Name)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, errors.BadRequest("Environment " + req.Name + " already exists")
+	}
+
+	// Without a branch of its own the environment follows the application's
+	if req.Branch == "" {
+		req.Branch = app.GitHubBranch
+	}
+
+	env := &Environment{
+		ApplicationID: app.ID,
+		Name:          req.Name,
+		Branch:        req.Branch,
+		Tier:          req.Tier,
+		Endpoints:     endpoints,
+	}
+
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.tierSvc.CheckQuota(ctx, app.ProjectID, "", env.Tier); err != nil {
+			return err
+		}
+		if err := s.saveEnvironment(ctx, app, env); err != nil {
+			return err
+		}
+
+		return s.triggerDeployment(ctx, app, env, "apply", &userID, "")
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response := toEnvironmentResponse(env)
+	audit.Record(ctx, audit.Event{
+		Action:       "environment.create",
+		ResourceType: "environment",
+		ResourceID:   audit.ID(env.ID),
+		ProjectID:    &app.ProjectID,
+		After:        response,
+	})
+
+	return response, nil
+}
+
+func (s *Service) UpdateEnvironment(ctx context.Context, userID, id uint, name string, req UpdateEnvironmentRequest) (*EnvironmentResponse, error) {
+	app, err := s.findAuthorized(ctx, userID, id, member.RoleDeveloper)
+	if err != nil {
+		return nil, err
+	}
+
+	env, err := s.findEnvironment(ctx, app.ID, name)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoints, err := normalizeEndpoints(req.Endpoints)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Branch == "" {
+		req.Branch = app.GitHubBranch
+	}
+	before := toEnvironmentResponse(env)
+	fromTier := env.Tier
+	env.Branch = req.Branch
+	env.Tier = req.Tier
+	env.Endpoints = endpoints
+
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.tierSvc.CheckQuota(ctx, app.ProjectID, fromTier, env.Tier); err != nil {
+			return err
+		}
+		if err := s.saveEnvironment(ctx, app, env); err != nil {
+			return err
+		}
+
+		// Keep the application's own settings in step with its default environment
+		if env.IsDefault {
+			app.GitHubBranch = env.Branch
+			app.Tier = env.Tier
+			app.Endpoints = env.Endpoints
+			if err := s.repo.Save(ctx, app); err != nil {
+				return err
+			}
+		}
+
+		return s.triggerDeployment(ctx, app, env, "apply", &userID, "")
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response := toEnvironmentResponse(env)
+	audit.Record(ctx, audit.Event{
+		Action:       "environment.update",
+		ResourceType: "environment",
+		ResourceID:   audit.ID(env.ID),
+		ProjectID:    &app.ProjectID,
+		Before:       before,
+		After:        response,
+	})
+
+	return response, nil
+}
+
+func (s *Service) DeleteEnvironment(ctx context.Context, userID, id uint, name string) error {
+	app, err := s.findAuthorized(ctx, userID, id, member.RoleMaintainer)
+	if err != nil {
+		return err
+	}
+
+	env, err := s.findEnvironment(ctx, app.ID, name)
+	if err != nil {
+		return err
+	}
+	if env.IsDefault {
+		return errors.BadRequest("The default environment can only be removed by deleting the application")
+	}
+
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.triggerDeployment(ctx, app, env, "remove", &userID, ""); err != nil {
+			return err
+		}
+
+		return s.repo.DeleteEnvironment(ctx, env.ID)
+	})
+	if err != nil {
+		return err
+	}
+
+	audit.Record(ctx, audit.Event{
+		Action:       "environment.delete",
+		ResourceType: "environment",
+		ResourceID:   audit.ID(env.ID),
+		ProjectID:    &app.ProjectID,
+		Before:       toEnvironmentResponse(env),
+	})
+
+	return nil
+}
+
+func (s *Service) GetEnvironmentDeployments(ctx context.Context, userID, id uint, name string, page pagination.Params) (*deployment.DeploymentListResponse, error) {
+	app, err := s.findAuthorized(ctx, userID, id, member.RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	env, err := s.findEnvironment(ctx, app.ID, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.deploymentSvc.GetEnvironmentDeployments(ctx, env.ID, page)
+}
+
+func (s *Service) findEnvironment(ctx context.Context, applicationID uint, name string) (*Environment, error) {
+	env, err := s.repo.FindEnvironment(ctx, applicationID, name)
+	if err != nil {
+		return nil, err
+	}
+	if env == nil {
+		return nil, errors.NotFound("Environment not found")
+	}
+	return env, nil
+}
+
+func (s *Service) defaultEnvironment(ctx context.Context, applicationID uint) (*Environment, error) {
+	envs, err := s.repo.FindEnvironments(ctx, applicationID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, env := range envs {
+		if env.IsDefault {
+			return env, nil
+		}
+	}
+
+	return nil, errors.Internal("Application has no default environment")
+}
+
+func toEnvironmentResponse(env *Environment) *EnvironmentResponse {
+	return &EnvironmentResponse{
+		ID:        env.ID,
+		Name:      env.Name,
+		Branch:    env.Branch,
+		Tier:      env.Tier,
+		Endpoints: env.Endpoints,
+		Hostname:  env.Hostname,
+		IsDefault: env.IsDefault,
+		CreatedAt: env.CreatedAt,
+		UpdatedAt: env.UpdatedAt,
+	}
+}
@@ -5,8 +5,10 @@ import (
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/team-xquare/deployment-platform/internal/app/addon"
 	"github.com/team-xquare/deployment-platform/internal/pkg/middleware"
 	"github.com/team-xquare/deployment-platform/internal/pkg/utils/errors"
+	"github.com/team-xquare/deployment-platform/internal/pkg/utils/pagination"
 )
 
 type Handler struct {
@@ -24,6 +26,52 @@ func (h *Handler) RegisterRoutes(r *gin.RouterGroup) {
 		applications.GET("/:id", h.GetApplication)
 		applications.PUT("/:id", h.UpdateApplication)
 		applications.DELETE("/:id", h.DeleteApplication)
+		applications.GET("/:id/deployments", h.GetDeployments)
+		applications.GET("/:id/revisions", h.GetRevisions)
+		applications.GET("/:id/revisions/diff", h.DiffRevisions)
+		applications.GET("/:id/revisions/:number", h.GetRevision)
+		applications.POST("/:id/rollback", h.Rollback)
+		applications.GET("/:id/bindings", h.GetBindings)
+		applications.POST("/:id/bindings", h.CreateBinding)
+		applications.DELETE("/:id/bindings/:bindingId", h.DeleteBinding)
+		applications.GET("/:id/domains", h.GetDomains)
+		applications.POST("/:id/domains", h.CreateDomain)
+		applications.DELETE("/:id/domains/:domainId", h.DeleteDomain)
+		applications.POST("/:id/domains/:domainId/verify", h.VerifyDomain)
+		applications.GET("/:id/env", h.GetEnvVars)
+		applications.POST("/:id/env", h.CreateEnvVar)
+		applications.PUT("/:id/env/:name", h.UpdateEnvVar)
+		applications.DELETE("/:id/env/:name", h.DeleteEnvVar)
+	}
+
+	// Environment routes; the env var handlers read the :env parameter and fall
+	// back to the application-wide variables without it
+	environments := r.Group("/applications/:id/environments")
+	environments.Use(middleware.Auth())
+	{
+		environments.GET("", h.GetEnvironments)
+		environments.POST("", h.CreateEnvironment)
+		environments.GET("/:env", h.GetEnvironment)
+		environments.PUT("/:env", h.UpdateEnvironment)
+		environments.DELETE("/:env", h.DeleteEnvironment)
+		environments.GET("/:env/deployments", h.GetEnvironmentDeployments)
+		environments.GET("/:env/env", h.GetEnvVars)
+		environments.POST("/:env/env", h.CreateEnvVar)
+		environments.PUT("/:env/env/:name", h.UpdateEnvVar)
+		environments.DELETE("/:env/env/:name", h.DeleteEnvVar)
+	}
+
+	admin := r.Group("/admin/secrets")
+	admin.Use(middleware.Auth(), middleware.Admin())
+	{
+		admin.POST("/re-encrypt", h.ReEncryptSecrets)
+	}
+
+	buildTypes := r.Group("/build-types")
+	buildTypes.Use(middleware.Auth())
+	{
+		buildTypes.GET("", h.GetBuildTypes)
+		buildTypes.POST("/detect", h.DetectBuild)
 	}
 
 	// Project-specific application routes
@@ -49,9 +97,9 @@ func (h *Handler) CreateApplication(c *gin.Context) {
 		return
 	}
 
-	_ = c.GetUint("user_id")
+	userID := c.GetUint("user_id")
 
-	app, err := h.service.CreateApplication(c.Request.Context(), uint(projectID), req)
+	app, err := h.service.CreateApplication(c.Request.Context(), userID, uint(projectID), req)
 	if err != nil {
 		c.Error(err)
 		return
@@ -68,9 +116,9 @@ func (h *Handler) GetApplication(c *gin.Context) {
 		return
 	}
 
-	_ = c.GetUint("user_id")
+	userID := c.GetUint("user_id")
 
-	app, err := h.service.GetApplication(c.Request.Context(), uint(id))
+	app, err := h.service.GetApplication(c.Request.Context(), userID, uint(id))
 	if err != nil {
 		c.Error(err)
 		return
@@ -87,9 +135,21 @@ func (h *Handler) GetApplicationsByProject(c *gin.Context) {
 		return
 	}
 
-	_ = c.GetUint("user_id")
+	page, err := pagination.Parse(c, "-"+pagination.SortCreated, pagination.SortName, pagination.SortCreated)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	filter := ListFilter{
+		Name:      c.Query("name"),
+		Tier:      c.Query("tier"),
+		BuildType: c.Query("build_type"),
+		Page:      page,
+	}
+
+	userID := c.GetUint("user_id")
 
-	apps, err := h.service.GetApplicationsByProject(c.Request.Context(), uint(projectID))
+	apps, err := h.service.GetApplicationsByProject(c.Request.Context(), userID, uint(projectID), filter)
 	if err != nil {
 		c.Error(err)
 		return
@@ -112,9 +172,9 @@ func (h *Handler) UpdateApplication(c *gin.Context) {
 		return
 	}
 
-	_ = c.GetUint("user_id")
+	userID := c.GetUint("user_id")
 
-	app, err := h.service.UpdateApplication(c.Request.Context(), uint(id), req)
+	app, err := h.service.UpdateApplication(c.Request.Context(), userID, uint(id), req)
 	if err != nil {
 		c.Error(err)
 		return
@@ -131,13 +191,561 @@ func (h *Handler) DeleteApplication(c *gin.Context) {
 		return
 	}
 
-	_ = c.GetUint("user_id")
+	userID := c.GetUint("user_id")
 
-	err = h.service.DeleteApplication(c.Request.Context(), uint(id))
+	err = h.service.DeleteApplication(c.Request.Context(), userID, uint(id))
 	if err != nil {
 		c.Error(err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "Application deleted successfully"})
-}
\ No newline at end of file
+}
+
+func (h *Handler) GetDeployments(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.Error(errors.BadRequest("Invalid application ID"))
+		return
+	}
+
+	page, err := pagination.Parse(c, "-"+pagination.SortCreated, pagination.SortCreated)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	userID := c.GetUint("user_id")
+
+	deployments, err := h.service.GetDeployments(c.Request.Context(), userID, uint(id), page)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, deployments)
+}
+
+func (h *Handler) GetRevisions(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.Error(errors.BadRequest("Invalid application ID"))
+		return
+	}
+
+	page, err := pagination.Parse(c, "-"+pagination.SortCreated, pagination.SortCreated)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	userID := c.GetUint("user_id")
+
+	revisions, err := h.service.GetRevisions(c.Request.Context(), userID, uint(id), page)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, revisions)
+}
+
+func (h *Handler) GetRevision(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.Error(errors.BadRequest("Invalid application ID"))
+		return
+	}
+
+	number, err := strconv.Atoi(c.Param("number"))
+	if err != nil {
+		c.Error(errors.BadRequest("Invalid revision number"))
+		return
+	}
+
+	userID := c.GetUint("user_id")
+
+	revision, err := h.service.GetRevision(c.Request.Context(), userID, uint(id), number)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, revision)
+}
+
+func (h *Handler) DiffRevisions(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.Error(errors.BadRequest("Invalid application ID"))
+		return
+	}
+
+	from, err := strconv.Atoi(c.Query("from"))
+	if err != nil {
+		c.Error(errors.BadRequest("Invalid from revision number"))
+		return
+	}
+	to, err := strconv.Atoi(c.Query("to"))
+	if err != nil {
+		c.Error(errors.BadRequest("Invalid to revision number"))
+		return
+	}
+
+	userID := c.GetUint("user_id")
+
+	revisionDiff, err := h.service.DiffRevisions(c.Request.Context(), userID, uint(id), from, to)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, revisionDiff)
+}
+
+func (h *Handler) Rollback(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.Error(errors.BadRequest("Invalid application ID"))
+		return
+	}
+
+	var req RollbackRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(errors.BadRequest("Invalid request format"))
+		return
+	}
+
+	userID := c.GetUint("user_id")
+
+	app, err := h.service.Rollback(c.Request.Context(), userID, uint(id), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, app)
+}
+
+func (h *Handler) CreateBinding(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.Error(errors.BadRequest("Invalid application ID"))
+		return
+	}
+
+	var req addon.CreateBindingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(errors.BadRequest("Invalid request format"))
+		return
+	}
+
+	userID := c.GetUint("user_id")
+
+	binding, err := h.service.CreateBinding(c.Request.Context(), userID, uint(id), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, binding)
+}
+
+func (h *Handler) GetBindings(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.Error(errors.BadRequest("Invalid application ID"))
+		return
+	}
+
+	userID := c.GetUint("user_id")
+
+	bindings, err := h.service.GetBindings(c.Request.Context(), userID, uint(id))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, bindings)
+}
+
+func (h *Handler) DeleteBinding(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.Error(errors.BadRequest("Invalid application ID"))
+		return
+	}
+
+	bindingIDStr := c.Param("bindingId")
+	bindingID, err := strconv.ParseUint(bindingIDStr, 10, 32)
+	if err != nil {
+		c.Error(errors.BadRequest("Invalid binding ID"))
+		return
+	}
+
+	userID := c.GetUint("user_id")
+
+	err = h.service.DeleteBinding(c.Request.Context(), userID, uint(id), uint(bindingID))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Binding deleted successfully"})
+}
+
+func (h *Handler) GetDomains(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.Error(errors.BadRequest("Invalid application ID"))
+		return
+	}
+
+	userID := c.GetUint("user_id")
+
+	domains, err := h.service.GetDomains(c.Request.Context(), userID, uint(id))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, domains)
+}
+
+func (h *Handler) CreateDomain(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.Error(errors.BadRequest("Invalid application ID"))
+		return
+	}
+
+	var req CreateDomainRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(errors.BadRequest("Invalid request format"))
+		return
+	}
+
+	userID := c.GetUint("user_id")
+
+	domain, err := h.service.CreateDomain(c.Request.Context(), userID, uint(id), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, domain)
+}
+
+func (h *Handler) VerifyDomain(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.Error(errors.BadRequest("Invalid application ID"))
+		return
+	}
+
+	domainIDStr := c.Param("domainId")
+	domainID, err := strconv.ParseUint(domainIDStr, 10, 32)
+	if err != nil {
+		c.Error(errors.BadRequest("Invalid domain ID"))
+		return
+	}
+
+	userID := c.GetUint("user_id")
+
+	domain, err := h.service.VerifyDomain(c.Request.Context(), userID, uint(id), uint(domainID))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, domain)
+}
+
+func (h *Handler) DeleteDomain(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.Error(errors.BadRequest("Invalid application ID"))
+		return
+	}
+
+	domainIDStr := c.Param("domainId")
+	domainID, err := strconv.ParseUint(domainIDStr, 10, 32)
+	if err != nil {
+		c.Error(errors.BadRequest("Invalid domain ID"))
+		return
+	}
+
+	userID := c.GetUint("user_id")
+
+	err = h.service.DeleteDomain(c.Request.Context(), userID, uint(id), uint(domainID))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Custom domain deleted successfully"})
+}
+
+func (h *Handler) GetEnvVars(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.Error(errors.BadRequest("Invalid application ID"))
+		return
+	}
+
+	userID := c.GetUint("user_id")
+
+	envVars, err := h.service.GetEnvVars(c.Request.Context(), userID, uint(id), c.Param("env"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, envVars)
+}
+
+func (h *Handler) CreateEnvVar(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.Error(errors.BadRequest("Invalid application ID"))
+		return
+	}
+
+	var req CreateEnvVarRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(errors.BadRequest("Invalid request format"))
+		return
+	}
+
+	userID := c.GetUint("user_id")
+
+	envVar, err := h.service.CreateEnvVar(c.Request.Context(), userID, uint(id), c.Param("env"), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, envVar)
+}
+
+func (h *Handler) UpdateEnvVar(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.Error(errors.BadRequest("Invalid application ID"))
+		return
+	}
+
+	var req UpdateEnvVarRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(errors.BadRequest("Invalid request format"))
+		return
+	}
+
+	userID := c.GetUint("user_id")
+
+	envVar, err := h.service.UpdateEnvVar(c.Request.Context(), userID, uint(id), c.Param("env"), c.Param("name"), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, envVar)
+}
+
+func (h *Handler) DeleteEnvVar(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.Error(errors.BadRequest("Invalid application ID"))
+		return
+	}
+
+	userID := c.GetUint("user_id")
+
+	err = h.service.DeleteEnvVar(c.Request.Context(), userID, uint(id), c.Param("env"), c.Param("name"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Environment variable deleted successfully"})
+}
+
+func (h *Handler) ReEncryptSecrets(c *gin.Context) {
+	updated, err := h.service.ReEncryptSecrets(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"re_encrypted": updated})
+}
+
+func (h *Handler) GetEnvironments(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.Error(errors.BadRequest("Invalid application ID"))
+		return
+	}
+
+	userID := c.GetUint("user_id")
+
+	envs, err := h.service.GetEnvironments(c.Request.Context(), userID, uint(id))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, envs)
+}
+
+func (h *Handler) CreateEnvironment(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.Error(errors.BadRequest("Invalid application ID"))
+		return
+	}
+
+	var req CreateEnvironmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(errors.BadRequest("Invalid request format"))
+		return
+	}
+
+	userID := c.GetUint("user_id")
+
+	env, err := h.service.CreateEnvironment(c.Request.Context(), userID, uint(id), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, env)
+}
+
+func (h *Handler) GetEnvironment(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.Error(errors.BadRequest("Invalid application ID"))
+		return
+	}
+
+	userID := c.GetUint("user_id")
+
+	env, err := h.service.GetEnvironment(c.Request.Context(), userID, uint(id), c.Param("env"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, env)
+}
+
+func (h *Handler) UpdateEnvironment(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.Error(errors.BadRequest("Invalid application ID"))
+		return
+	}
+
+	var req UpdateEnvironmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(errors.BadRequest("Invalid request format"))
+		return
+	}
+
+	userID := c.GetUint("user_id")
+
+	env, err := h.service.UpdateEnvironment(c.Request.Context(), userID, uint(id), c.Param("env"), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, env)
+}
+
+func (h *Handler) DeleteEnvironment(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.Error(errors.BadRequest("Invalid application ID"))
+		return
+	}
+
+	userID := c.GetUint("user_id")
+
+	err = h.service.DeleteEnvironment(c.Request.Context(), userID, uint(id), c.Param("env"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Environment deleted successfully"})
+}
+
+func (h *Handler) GetEnvironmentDeployments(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.Error(errors.BadRequest("Invalid application ID"))
+		return
+	}
+
+	page, err := pagination.Parse(c, "-"+pagination.SortCreated, pagination.SortCreated)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	userID := c.GetUint("user_id")
+
+	deployments, err := h.service.GetEnvironmentDeployments(c.Request.Context(), userID, uint(id), c.Param("env"), page)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, deployments)
+}
+
+func (h *Handler) GetBuildTypes(c *gin.Context) {
+	c.JSON(http.StatusOK, h.service.GetBuildTypes())
+}
+
+func (h *Handler) DetectBuild(c *gin.Context) {
+	var req GitHubConfig
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(errors.BadRequest("Invalid request format"))
+		return
+	}
+
+	userID := c.GetUint("user_id")
+
+	detected, err := h.service.DetectBuild(c.Request.Context(), userID, req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, detected)
+}
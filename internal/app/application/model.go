@@ -1,6 +1,10 @@
 package application
 
-import "time"
+import (
+	"time"
+
+	"github.com/team-xquare/deployment-platform/internal/pkg/utils/pagination"
+)
 
 type Application struct {
 	ID        uint      `json:"id" db:"id"`
@@ -17,10 +21,13 @@ type Application struct {
 	
 	// Build Configuration
 	BuildType   string      `json:"build_type" db:"build_type"`
-	BuildConfig interface{} `json:"build_config" db:"build_config"`
+	BuildConfig BuildConfig `json:"build_config" db:"build_config"`
 	
 	// Endpoints
 	Endpoints []EndpointConfig `json:"endpoints" db:"endpoints"`
+	// Hostname is the platform hostname of the default environment; it is
+	// read with the application but saved with the environment
+	Hostname string `json:"hostname" db:"-"`
 	
 	CreatedAt time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
@@ -101,4 +108,102 @@ type FlaskBuild struct {
 type DockerBuild struct {
 	DockerfilePath string `json:"dockerfilePath"`
 	ContextPath    string `json:"contextPath"`
-}
\ No newline at end of file
+}
+
+// DefaultEnvironmentName names the environment every application is created
+// with. It mirrors the application's own branch, tier and endpoints.
+const DefaultEnvironmentName = "production"
+
+// Environment is a named deployment of an application, such as staging, with
+// its own branch, tier and endpoints.
+type Environment struct {
+	ID            uint             `json:"id" db:"id"`
+	ApplicationID uint             `json:"application_id" db:"application_id"`
+	Name          string           `json:"name" db:"name"`
+	Branch        string           `json:"branch" db:"branch"`
+	Tier          string           `json:"tier" db:"tier"`
+	Endpoints     []EndpointConfig `json:"endpoints" db:"endpoints"`
+	Hostname      string           `json:"hostname" db:"hostname"`
+	IsDefault     bool             `json:"is_default" db:"is_default"`
+	CreatedAt     time.Time        `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time        `json:"updated_at" db:"updated_at"`
+}
+
+// Route is a host and path reserved by an environment. Routes given as a path
+// alone are reserved on the environment's platform hostname.
+type Route struct {
+	EnvironmentID uint   `json:"environment_id" db:"environment_id"`
+	Host          string `json:"host" db:"host"`
+	Path          string `json:"path" db:"path"`
+}
+
+// Verification states of a custom domain.
+const (
+	DomainPending  = "pending"
+	DomainVerified = "verified"
+	DomainFailed   = "failed"
+)
+
+// CustomDomain is a domain of the team's own attached to an endpoint of an
+// environment. It is served once a TXT record proves the team controls it.
+type CustomDomain struct {
+	ID            uint       `json:"id" db:"id"`
+	ApplicationID uint       `json:"application_id" db:"application_id"`
+	EnvironmentID uint       `json:"environment_id" db:"environment_id"`
+	Domain        string     `json:"domain" db:"domain"`
+	Port          int        `json:"port" db:"port"`
+	Token         string     `json:"-" db:"token"`
+	Status        string     `json:"status" db:"status"`
+	LastError     string     `json:"last_error" db:"last_error"`
+	CheckedAt     *time.Time `json:"checked_at" db:"checked_at"`
+	VerifiedAt    *time.Time `json:"verified_at" db:"verified_at"`
+	CreatedAt     time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// Revision is an immutable snapshot of an application as it was saved,
+// numbered from 1 per application. A rollback is recorded as a new revision
+// naming the one it restored in RollbackOf.
+type Revision struct {
+	ID            uint           `json:"id" db:"id"`
+	ApplicationID uint           `json:"application_id" db:"application_id"`
+	Number        int            `json:"number" db:"number"`
+	Config        RevisionConfig `json:"config" db:"config"`
+	RollbackOf    *int           `json:"rollback_of" db:"rollback_of"`
+	CreatedBy     *uint          `json:"created_by" db:"created_by"`
+	CreatedAt     time.Time      `json:"created_at" db:"created_at"`
+}
+
+// RevisionConfig is the saved configuration of an application. A rollback
+// restores everything but the name, which is part of the application's
+// identity and config path.
+type RevisionConfig struct {
+	Name      string           `json:"name"`
+	Tier      string           `json:"tier"`
+	GitHub    *GitHubConfig    `json:"github,omitempty"`
+	Build     BuildConfig      `json:"build,omitempty"`
+	Endpoints []EndpointConfig `json:"endpoints,omitempty"`
+}
+
+// EnvVar is a runtime environment variable of an application. Variables
+// without an EnvironmentID apply to every environment. Secret values are
+// stored encrypted with the secrets keyring.
+type EnvVar struct {
+	ID            uint      `json:"id" db:"id"`
+	ApplicationID uint      `json:"application_id" db:"application_id"`
+	EnvironmentID *uint     `json:"environment_id" db:"environment_id"`
+	Name          string    `json:"name" db:"name"`
+	Value         string    `json:"-" db:"value"`
+	Secret        bool      `json:"secret" db:"secret"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// ListFilter narrows and pages the applications of a project. Name matches
+// applications whose name contains it; the other fields match exactly.
+type ListFilter struct {
+	Name      string
+	Tier      string
+	BuildType string
+	Page      pagination.Params
+}
@@ -1,10 +1,48 @@
 package application
 
-import "context"
+import (
+	"context"
+
+	"github.com/team-xquare/deployment-platform/internal/pkg/utils/pagination"
+)
 
 type Repository interface {
 	Save(ctx context.Context, app *Application) error
 	FindByID(ctx context.Context, id uint) (*Application, error)
-	FindByProjectID(ctx context.Context, projectID uint) ([]*Application, error)
+	FindByProjectID(ctx context.Context, projectID uint, filter ListFilter) ([]*Application, int, error)
 	Delete(ctx context.Context, id uint) error
-}
\ No newline at end of file
+	// FindByGitHubRepository returns the applications of owner/repo with an environment tracking branch
+	FindByGitHubRepository(ctx context.Context, owner, repo, branch string) ([]*Application, error)
+	UpdateGitHubRepository(ctx context.Context, previousOwner, previousRepo, owner, repo string) error
+	ClearGitHubRepository(ctx context.Context, owner, repo string) error
+	SaveEnvironment(ctx context.Context, environment *Environment) error
+	FindEnvironments(ctx context.Context, applicationID uint) ([]*Environment, error)
+	// FindEnvironment returns nil when the application has no environment called name
+	FindEnvironment(ctx context.Context, applicationID uint, name string) (*Environment, error)
+	DeleteEnvironment(ctx context.Context, id uint) error
+	// FindEnvironmentByHostname and FindRoute return nil when the hostname or
+	// route is not reserved
+	FindEnvironmentByHostname(ctx context.Context, hostname string) (*Environment, error)
+	FindRoute(ctx context.Context, host, path string) (*Route, error)
+	// ReplaceRoutes reserves exactly the given routes for the environment
+	ReplaceRoutes(ctx context.Context, environmentID uint, routes []*Route) error
+	SaveDomain(ctx context.Context, domain *CustomDomain) error
+	FindDomains(ctx context.Context, applicationID uint) ([]*CustomDomain, error)
+	// FindDomainsByName returns every attachment of the domain, across applications
+	FindDomainsByName(ctx context.Context, domain string) ([]*CustomDomain, error)
+	FindVerifiedDomains(ctx context.Context, environmentID uint) ([]*CustomDomain, error)
+	DeleteDomain(ctx context.Context, id uint) error
+	// SaveRevision assigns the revision the application's next number
+	SaveRevision(ctx context.Context, revision *Revision) error
+	FindRevisions(ctx context.Context, applicationID uint, page pagination.Params) ([]*Revision, int, error)
+	// FindRevision returns nil when the application has no revision with that number
+	FindRevision(ctx context.Context, applicationID uint, number int) (*Revision, error)
+	SaveEnvVar(ctx context.Context, envVar *EnvVar) error
+	// FindEnvVars returns the variables of one environment, or the application-wide
+	// ones when environmentID is nil
+	FindEnvVars(ctx context.Context, applicationID uint, environmentID *uint) ([]*EnvVar, error)
+	// FindEnvVar returns nil when the scope has no variable called name
+	FindEnvVar(ctx context.Context, applicationID uint, environmentID *uint, name string) (*EnvVar, error)
+	DeleteEnvVar(ctx context.Context, id uint) error
+	FindSecretEnvVars(ctx context.Context) ([]*EnvVar, error)
+}
@@ -0,0 +1,214 @@
+package application
+
+import (
+	"context"
+
+	"github.com/team-xquare/deployment-platform/internal/app/audit"
+	"github.com/team-xquare/deployment-platform/internal/app/member"
+	"github.com/team-xquare/deployment-platform/internal/pkg/utils/diff"
+	"github.com/team-xquare/deployment-platform/internal/pkg/utils/errors"
+	"github.com/team-xquare/deployment-platform/internal/pkg/utils/pagination"
+)
+
+func (s *Service) GetRevisions(ctx context.Context, userID, id uint, page pagination.Params) (*RevisionListResponse, error) {
+	app, err := s.findAuthorized(ctx, userID, id, member.RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	revisions, total, err := s.repo.FindRevisions(ctx, app.ID, page)
+	if err != nil {
+		return nil, err
+	}
+
+	revisions, meta := pagination.Paginate(revisions, page, total, func(rev *Revision) (uint, string) {
+		return rev.ID, ""
+	})
+
+	responses := make([]*RevisionResponse, len(revisions))
+	for i, rev := range revisions {
+		responses[i] = toRevisionResponse(rev)
+	}
+
+	return &RevisionListResponse{Revisions: responses, Page: meta}, nil
+}
+
+func (s *Service) GetRevision(ctx context.Context, userID, id uint, number int) (*RevisionResponse, error) {
+	app, err := s.findAuthorized(ctx, userID, id, member.RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	rev, err := s.findRevision(ctx, app.ID, number)
+	if err != nil {
+		return nil, err
+	}
+
+	return toRevisionResponse(rev), nil
+}
+
+// DiffRevisions compares the configuration of two revisions field by field.
+func (s *Service) DiffRevisions(ctx context.Context, userID, id uint, from, to int) (*RevisionDiffResponse, error) {
+	app, err := s.findAuthorized(ctx, userID, id, member.RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	fromRev, err := s.findRevision(ctx, app.ID, from)
+	if err != nil {
+		return nil, err
+	}
+	toRev, err := s.findRevision(ctx, app.ID, to)
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := diff.Compare(fromRev.Config, toRev.Config)
+	if err != nil {
+		return nil, errors.Internal("Failed to compare revisions")
+	}
+
+	return &RevisionDiffResponse{From: from, To: to, Changes: changes}, nil
+}
+
+// Rollback restores the configuration of a previous revision, keeping the
+// application's name, and applies it to every environment like an update. The
+// restored configuration is validated again, since tiers, build types and
+// routes may have changed since it was recorded.
+func (s *Service) Rollback(ctx context.Context, userID, id uint, req RollbackRequest) (*ApplicationResponse, error) {
+	app, err := s.findAuthorized(ctx, userID, id, member.RoleDeveloper)
+	if err != nil {
+		return nil, err
+	}
+
+	rev, err := s.findRevision(ctx, app.ID, req.Revision)
+	if err != nil {
+		return nil, err
+	}
+	config := rev.Config
+	before := s.toResponse(app)
+	previous := *app
+
+	if err := s.authorizeGitHub(ctx, userID, app, config.GitHub); err != nil {
+		return nil, err
+	}
+
+	endpoints, err := normalizeEndpoints(config.Endpoints)
+	if err != nil {
+		return nil, err
+	}
+
+	fromTier := app.Tier
+	app.Tier = config.Tier
+	app.Endpoints = endpoints
+
+	if config.GitHub != nil {
+		app.GitHubOwner = config.GitHub.Owner
+		app.GitHubRepo = config.GitHub.Repo
+		app.GitHubBranch = config.GitHub.Branch
+		app.GitHubInstallationID = config.GitHub.InstallationID
+		app.GitHubTriggerPaths = config.GitHub.TriggerPaths
+	} else {
+		app.GitHubOwner = ""
+		app.GitHubRepo = ""
+		app.GitHubBranch = ""
+		app.GitHubInstallationID = ""
+		app.GitHubTriggerPaths = nil
+	}
+
+	if config.Build != nil {
+		buildType, build, err := normalizeBuild(config.Build)
+		if err != nil {
+			return nil, err
+		}
+		app.BuildType = buildType
+		app.BuildConfig = build
+	} else {
+		app.BuildType = ""
+		app.BuildConfig = nil
+	}
+
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.tierSvc.CheckQuota(ctx, app.ProjectID, fromTier, app.Tier); err != nil {
+			return err
+		}
+		if err := s.removeMoved(ctx, &previous, app, userID); err != nil {
+			return err
+		}
+		if err := s.repo.Save(ctx, app); err != nil {
+			return err
+		}
+
+		env, err := s.defaultEnvironment(ctx, app.ID)
+		if err != nil {
+			return err
+		}
+		env.Branch = app.GitHubBranch
+		env.Tier = app.Tier
+		env.Endpoints = app.Endpoints
+		if err := s.saveEnvironment(ctx, app, env); err != nil {
+			return err
+		}
+
+		if err := s.deployEnvironments(ctx, app, "apply", &userID); err != nil {
+			return err
+		}
+		return s.recordRevision(ctx, app, &userID, &rev.Number)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response := s.toResponse(app)
+	audit.Record(ctx, audit.Event{
+		Action:       "application.rollback",
+		ResourceType: "application",
+		ResourceID:   audit.ID(app.ID),
+		ProjectID:    &app.ProjectID,
+		Before:       before,
+		After:        response,
+	})
+
+	return response, nil
+}
+
+// recordRevision snapshots the application as it is being saved. It must run
+// inside the transaction that saves the application.
+func (s *Service) recordRevision(ctx context.Context, app *Application, createdBy *uint, rollbackOf *int) error {
+	response := s.toResponse(app)
+	rev := &Revision{
+		ApplicationID: app.ID,
+		Config: RevisionConfig{
+			Name:      response.Name,
+			Tier:      response.Tier,
+			GitHub:    response.GitHub,
+			Build:     response.Build,
+			Endpoints: response.Endpoints,
+		},
+		RollbackOf: rollbackOf,
+		CreatedBy:  createdBy,
+	}
+
+	return s.repo.SaveRevision(ctx, rev)
+}
+
+func (s *Service) findRevision(ctx context.Context, applicationID uint, number int) (*Revision, error) {
+	rev, err := s.repo.FindRevision(ctx, applicationID, number)
+	if err != nil {
+		return nil, err
+	}
+	if rev == nil {
+		return nil, errors.NotFound("Revision not found")
+	}
+	return rev, nil
+}
+
+func toRevisionResponse(rev *Revision) *RevisionResponse {
+	return &RevisionResponse{
+		Number:     rev.Number,
+		Config:     rev.Config,
+		RollbackOf: rev.RollbackOf,
+		CreatedBy:  rev.CreatedBy,
+		CreatedAt:  rev.CreatedAt,
+	}
+}
@@ -2,30 +2,77 @@ package application
 
 import (
 	"context"
-	"encoding/json"
+	"net"
 
+	"github.com/team-xquare/deployment-platform/internal/app/addon"
+	"github.com/team-xquare/deployment-platform/internal/app/audit"
+	"github.com/team-xquare/deployment-platform/internal/app/deployment"
 	"github.com/team-xquare/deployment-platform/internal/app/github"
+	"github.com/team-xquare/deployment-platform/internal/app/gitops"
+	"github.com/team-xquare/deployment-platform/internal/app/member"
+	"github.com/team-xquare/deployment-platform/internal/app/outbox"
+	"github.com/team-xquare/deployment-platform/internal/app/project"
+	"github.com/team-xquare/deployment-platform/internal/app/tier"
+	"github.com/team-xquare/deployment-platform/internal/pkg/utils/crypto"
+	"github.com/team-xquare/deployment-platform/internal/pkg/utils/errors"
+	"github.com/team-xquare/deployment-platform/internal/pkg/utils/pagination"
 )
 
 type Service struct {
-	repo        Repository
-	githubSvc   *github.Service
+	repo          Repository
+	projectRepo   project.Repository
+	tx            outbox.Transactor
+	keyring       *crypto.Keyring
+	githubSvc     *github.Service
+	gitopsSvc     *gitops.Service
+	memberSvc     *member.Service
+	deploymentSvc *deployment.Service
+	addonSvc      *addon.Service
+	tierSvc       *tier.Service
+	outboxSvc     *outbox.Service
+	resolver      TXTResolver
 }
 
-func NewService(repo Repository, githubSvc *github.Service) *Service {
+func NewService(repo Repository, projectRepo project.Repository, tx outbox.Transactor, keyring *crypto.Keyring, githubSvc *github.Service, gitopsSvc *gitops.Service, memberSvc *member.Service, deploymentSvc *deployment.Service, addonSvc *addon.Service, tierSvc *tier.Service, outboxSvc *outbox.Service) *Service {
 	return &Service{
-		repo:      repo,
-		githubSvc: githubSvc,
+		repo:          repo,
+		projectRepo:   projectRepo,
+		tx:            tx,
+		keyring:       keyring,
+		githubSvc:     githubSvc,
+		gitopsSvc:     gitopsSvc,
+		memberSvc:     memberSvc,
+		deploymentSvc: deploymentSvc,
+		addonSvc:      addonSvc,
+		tierSvc:       tierSvc,
+		outboxSvc:     outboxSvc,
+		resolver:      net.DefaultResolver,
 	}
 }
 
-func (s *Service) CreateApplication(ctx context.Context, projectID uint, req CreateApplicationRequest) (*ApplicationResponse, error) {
+func (s *Service) CreateApplication(ctx context.Context, userID, projectID uint, req CreateApplicationRequest) (*ApplicationResponse, error) {
+	if _, err := s.memberSvc.Authorize(ctx, userID, projectID, member.RoleDeveloper); err != nil {
+		return nil, err
+	}
+
+	if err := validateName(req.Name); err != nil {
+		return nil, err
+	}
+	if err := s.authorizeGitHub(ctx, userID, nil, req.GitHub); err != nil {
+		return nil, err
+	}
+
+	endpoints, err := normalizeEndpoints(req.Endpoints)
+	if err != nil {
+		return nil, err
+	}
+
 	// Convert request to application model
 	app := &Application{
 		ProjectID: projectID,
 		Name:      req.Name,
 		Tier:      req.Tier,
-		Endpoints: req.Endpoints,
+		Endpoints: endpoints,
 	}
 
 	// Set GitHub configuration if provided
@@ -39,24 +86,60 @@ func (s *Service) CreateApplication(ctx context.Context, projectID uint, req Cre
 
 	// Set build configuration if provided
 	if req.Build != nil {
-		app.BuildConfig = req.Build
-		app.BuildType = s.determineBuildType(req.Build)
+		buildType, build, err := normalizeBuild(req.Build)
+		if err != nil {
+			return nil, err
+		}
+		app.BuildType = buildType
+		app.BuildConfig = build
 	}
 
-	if err := s.repo.Save(ctx, app); err != nil {
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.tierSvc.CheckQuota(ctx, projectID, "", app.Tier); err != nil {
+			return err
+		}
+		if err := s.repo.Save(ctx, app); err != nil {
+			return err
+		}
+
+		env := &Environment{
+			ApplicationID: app.ID,
+			Name:          DefaultEnvironmentName,
+			Branch:        app.GitHubBranch,
+			Tier:          app.Tier,
+			Endpoints:     app.Endpoints,
+			IsDefault:     true,
+		}
+		if err := s.saveEnvironment(ctx, app, env); err != nil {
+			return err
+		}
+
+		// Trigger GitHub Actions workflow for deployment
+		if req.GitHub != nil {
+			if err := s.triggerDeployment(ctx, app, env, "apply", &userID, ""); err != nil {
+				return err
+			}
+		}
+		return s.recordRevision(ctx, app, &userID, nil)
+	})
+	if err != nil {
 		return nil, err
 	}
 
-	// Trigger GitHub Actions workflow for deployment
-	if req.GitHub != nil {
-		go s.triggerDeployment(app, "apply")
-	}
+	response := s.toResponse(app)
+	audit.Record(ctx, audit.Event{
+		Action:       "application.create",
+		ResourceType: "application",
+		ResourceID:   audit.ID(app.ID),
+		ProjectID:    &app.ProjectID,
+		After:        response,
+	})
 
-	return s.toResponse(app), nil
+	return response, nil
 }
 
-func (s *Service) GetApplication(ctx context.Context, id uint) (*ApplicationResponse, error) {
-	app, err := s.repo.FindByID(ctx, id)
+func (s *Service) GetApplication(ctx context.Context, userID, id uint) (*ApplicationResponse, error) {
+	app, err := s.findAuthorized(ctx, userID, id, member.RoleViewer)
 	if err != nil {
 		return nil, err
 	}
@@ -64,30 +147,53 @@ func (s *Service) GetApplication(ctx context.Context, id uint) (*ApplicationResp
 	return s.toResponse(app), nil
 }
 
-func (s *Service) GetApplicationsByProject(ctx context.Context, projectID uint) ([]*ApplicationResponse, error) {
-	apps, err := s.repo.FindByProjectID(ctx, projectID)
+func (s *Service) GetApplicationsByProject(ctx context.Context, userID, projectID uint, filter ListFilter) (*ApplicationListResponse, error) {
+	if _, err := s.memberSvc.Authorize(ctx, userID, projectID, member.RoleViewer); err != nil {
+		return nil, err
+	}
+
+	apps, total, err := s.repo.FindByProjectID(ctx, projectID, filter)
 	if err != nil {
 		return nil, err
 	}
 
+	apps, page := pagination.Paginate(apps, filter.Page, total, func(app *Application) (uint, string) {
+		return app.ID, app.Name
+	})
+
 	responses := make([]*ApplicationResponse, len(apps))
 	for i, app := range apps {
 		responses[i] = s.toResponse(app)
 	}
 
-	return responses, nil
+	return &ApplicationListResponse{Applications: responses, Page: page}, nil
 }
 
-func (s *Service) UpdateApplication(ctx context.Context, id uint, req UpdateApplicationRequest) (*ApplicationResponse, error) {
-	app, err := s.repo.FindByID(ctx, id)
+func (s *Service) UpdateApplication(ctx context.Context, userID, id uint, req UpdateApplicationRequest) (*ApplicationResponse, error) {
+	app, err := s.findAuthorized(ctx, userID, id, member.RoleDeveloper)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateName(req.Name); err != nil {
+		return nil, err
+	}
+	if err := s.authorizeGitHub(ctx, userID, app, req.GitHub); err != nil {
+		return nil, err
+	}
+
+	endpoints, err := normalizeEndpoints(req.Endpoints)
 	if err != nil {
 		return nil, err
 	}
+	before := s.toResponse(app)
+	previous := *app
+	fromTier := app.Tier
 
 	// Update fields
 	app.Name = req.Name
 	app.Tier = req.Tier
-	app.Endpoints = req.Endpoints
+	app.Endpoints = endpoints
 
 	// Update GitHub configuration
 	if req.GitHub != nil {
@@ -106,41 +212,263 @@ func (s *Service) UpdateApplication(ctx context.Context, id uint, req UpdateAppl
 
 	// Update build configuration
 	if req.Build != nil {
-		app.BuildConfig = req.Build
-		app.BuildType = s.determineBuildType(req.Build)
+		buildType, build, err := normalizeBuild(req.Build)
+		if err != nil {
+			return nil, err
+		}
+		app.BuildType = buildType
+		app.BuildConfig = build
 	} else {
 		app.BuildType = ""
 		app.BuildConfig = nil
 	}
 
-	if err := s.repo.Save(ctx, app); err != nil {
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.tierSvc.CheckQuota(ctx, app.ProjectID, fromTier, app.Tier); err != nil {
+			return err
+		}
+		if err := s.removeMoved(ctx, &previous, app, userID); err != nil {
+			return err
+		}
+		if err := s.repo.Save(ctx, app); err != nil {
+			return err
+		}
+
+		// The application's own settings are those of its default environment
+		env, err := s.defaultEnvironment(ctx, app.ID)
+		if err != nil {
+			return err
+		}
+		env.Branch = app.GitHubBranch
+		env.Tier = app.Tier
+		env.Endpoints = app.Endpoints
+		if err := s.saveEnvironment(ctx, app, env); err != nil {
+			return err
+		}
+
+		// Trigger GitHub Actions workflow for deployment update
+		if err := s.deployEnvironments(ctx, app, "apply", &userID); err != nil {
+			return err
+		}
+		return s.recordRevision(ctx, app, &userID, nil)
+	})
+	if err != nil {
 		return nil, err
 	}
 
-	// Trigger GitHub Actions workflow for deployment update
-	if req.GitHub != nil || app.GitHubOwner != "" {
-		go s.triggerDeployment(app, "apply")
+	response := s.toResponse(app)
+	audit.Record(ctx, audit.Event{
+		Action:       "application.update",
+		ResourceType: "application",
+		ResourceID:   audit.ID(app.ID),
+		ProjectID:    &app.ProjectID,
+		Before:       before,
+		After:        response,
+	})
+
+	return response, nil
+}
+
+func (s *Service) DeleteApplication(ctx context.Context, userID, id uint) error {
+	app, err := s.findAuthorized(ctx, userID, id, member.RoleMaintainer)
+	if err != nil {
+		return err
 	}
 
-	return s.toResponse(app), nil
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		// Drop the application's users from its bound addons
+		if err := s.addonSvc.UnbindApplication(ctx, userID, app.ID); err != nil {
+			return err
+		}
+
+		// Trigger GitHub Actions workflow for removal before deleting
+		if err := s.deployEnvironments(ctx, app, "remove", &userID); err != nil {
+			return err
+		}
+
+		return s.repo.Delete(ctx, id)
+	})
+	if err != nil {
+		return err
+	}
+
+	audit.Record(ctx, audit.Event{
+		Action:       "application.delete",
+		ResourceType: "application",
+		ResourceID:   audit.ID(app.ID),
+		ProjectID:    &app.ProjectID,
+		Before:       s.toResponse(app),
+	})
+
+	return nil
 }
 
-func (s *Service) DeleteApplication(ctx context.Context, id uint) error {
-	app, err := s.repo.FindByID(ctx, id)
+func (s *Service) GetDeployments(ctx context.Context, userID, id uint, page pagination.Params) (*deployment.DeploymentListResponse, error) {
+	app, err := s.findAuthorized(ctx, userID, id, member.RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.deploymentSvc.GetApplicationDeployments(ctx, app.ID, page)
+}
+
+// CreateBinding binds an addon to the application and redeploys both so the
+// application receives the addon's connection variables.
+func (s *Service) CreateBinding(ctx context.Context, userID, id uint, req addon.CreateBindingRequest) (*addon.BindingResponse, error) {
+	app, err := s.findAuthorized(ctx, userID, id, member.RoleDeveloper)
+	if err != nil {
+		return nil, err
+	}
+
+	var binding *addon.BindingResponse
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		binding, err = s.addonSvc.Bind(ctx, userID, app.ProjectID, app.ID, req)
+		if err != nil {
+			return err
+		}
+
+		return s.deployEnvironments(ctx, app, "apply", &userID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	audit.Record(ctx, audit.Event{
+		Action:       "binding.create",
+		ResourceType: "binding",
+		ResourceID:   audit.ID(binding.ID),
+		ProjectID:    &app.ProjectID,
+		After:        binding,
+	})
+
+	return binding, nil
+}
+
+func (s *Service) GetBindings(ctx context.Context, userID, id uint) ([]*addon.BindingResponse, error) {
+	app, err := s.findAuthorized(ctx, userID, id, member.RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.addonSvc.GetBindings(ctx, app.ID)
+}
+
+func (s *Service) DeleteBinding(ctx context.Context, userID, id, bindingID uint) error {
+	app, err := s.findAuthorized(ctx, userID, id, member.RoleDeveloper)
 	if err != nil {
 		return err
 	}
 
-	// Trigger GitHub Actions workflow for removal before deleting
-	if app.GitHubOwner != "" {
-		go s.triggerDeployment(app, "remove")
+	var before *addon.BindingResponse
+	bindings, err := s.addonSvc.GetBindings(ctx, app.ID)
+	if err != nil {
+		return err
+	}
+	for _, binding := range bindings {
+		if binding.ID == bindingID {
+			before = binding
+			break
+		}
 	}
 
-	return s.repo.Delete(ctx, id)
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.addonSvc.Unbind(ctx, userID, app.ID, bindingID); err != nil {
+			return err
+		}
+
+		return s.deployEnvironments(ctx, app, "apply", &userID)
+	})
+	if err != nil {
+		return err
+	}
+
+	audit.Record(ctx, audit.Event{
+		Action:       "binding.delete",
+		ResourceType: "binding",
+		ResourceID:   audit.ID(bindingID),
+		ProjectID:    &app.ProjectID,
+		Before:       before,
+	})
+
+	return nil
+}
+
+// HandlePush redeploys the application environments tracking the pushed branch
+// whose trigger paths match the changed files. When the changed files of the
+// push are not all known, every application tracking the branch is redeployed.
+func (s *Service) HandlePush(ctx context.Context, event *github.PushEvent) error {
+	apps, err := s.repo.FindByGitHubRepository(ctx, event.Owner, event.Repo, event.Branch)
+	if err != nil {
+		return err
+	}
+
+	for _, app := range apps {
+		if !event.ChangedFilesIncomplete && !matchesTriggerPaths(app.GitHubTriggerPaths, event.ChangedFiles) {
+			continue
+		}
+
+		envs, err := s.repo.FindEnvironments(ctx, app.ID)
+		if err != nil {
+			return err
+		}
+
+		for _, env := range envs {
+			if env.Branch != event.Branch {
+				continue
+			}
+
+			err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+				return s.triggerDeployment(ctx, app, env, "apply", nil, event.CommitSHA)
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
 }
 
-func (s *Service) DeleteApplicationOld(ctx context.Context, id uint) error {
-	return s.repo.Delete(ctx, id)
+// HandleRepositoryEvent keeps applications pointing at their GitHub repository
+// when it is renamed or transferred, and disconnects them when it is deleted.
+func (s *Service) HandleRepositoryEvent(ctx context.Context, event *github.RepositoryEvent) error {
+	switch event.Action {
+	case "renamed", "transferred":
+		return s.repo.UpdateGitHubRepository(ctx, event.PreviousOwner, event.PreviousRepo, event.Owner, event.Repo)
+	case "deleted":
+		return s.repo.ClearGitHubRepository(ctx, event.Owner, event.Repo)
+	default:
+		return nil
+	}
+}
+
+// findAuthorized loads an application and checks the user's role in its project.
+func (s *Service) findAuthorized(ctx context.Context, userID, id uint, required member.Role) (*Application, error) {
+	app, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.memberSvc.Authorize(ctx, userID, app.ProjectID, required); err != nil {
+		return nil, err
+	}
+
+	return app, nil
+}
+
+// authorizeGitHub checks that the user may deploy from the repository of a
+// GitHub configuration through its installation. A configuration that keeps
+// the application's current installation and repository is not checked again.
+func (s *Service) authorizeGitHub(ctx context.Context, userID uint, app *Application, config *GitHubConfig) error {
+	if config == nil {
+		return nil
+	}
+	if app != nil && app.GitHubInstallationID == config.InstallationID &&
+		app.GitHubOwner == config.Owner && app.GitHubRepo == config.Repo {
+		return nil
+	}
+
+	return s.githubSvc.AuthorizeRepository(ctx, userID, config.InstallationID, config.Owner, config.Repo)
 }
 
 func (s *Service) toResponse(app *Application) *ApplicationResponse {
@@ -150,6 +478,7 @@ func (s *Service) toResponse(app *Application) *ApplicationResponse {
 		Name:      app.Name,
 		Tier:      app.Tier,
 		Endpoints: app.Endpoints,
+		Hostname:  app.Hostname,
 		CreatedAt: app.CreatedAt,
 		UpdatedAt: app.UpdatedAt,
 	}
@@ -165,116 +494,186 @@ func (s *Service) toResponse(app *Application) *ApplicationResponse {
 		}
 	}
 
-	// Add build config if present
-	if app.BuildConfig != nil && app.BuildType != "" {
-		buildConfig := &BuildConfig{}
-		
-		// Parse build config based on type
-		configData, _ := json.Marshal(app.BuildConfig)
-		switch app.BuildType {
-		case "gradle":
-			var gradle GradleBuild
-			json.Unmarshal(configData, &gradle)
-			buildConfig.Gradle = &gradle
-		case "nodejs":
-			var nodejs NodeJSBuild
-			json.Unmarshal(configData, &nodejs)
-			buildConfig.NodeJS = &nodejs
-		case "react":
-			var react ReactBuild
-			json.Unmarshal(configData, &react)
-			buildConfig.React = &react
-		case "vite":
-			var vite ViteBuild
-			json.Unmarshal(configData, &vite)
-			buildConfig.Vite = &vite
-		case "vue":
-			var vue VueBuild
-			json.Unmarshal(configData, &vue)
-			buildConfig.Vue = &vue
-		case "nextjs":
-			var nextjs NextJSBuild
-			json.Unmarshal(configData, &nextjs)
-			buildConfig.NextJS = &nextjs
-		case "go":
-			var goBuild GoBuild
-			json.Unmarshal(configData, &goBuild)
-			buildConfig.Go = &goBuild
-		case "rust":
-			var rust RustBuild
-			json.Unmarshal(configData, &rust)
-			buildConfig.Rust = &rust
-		case "maven":
-			var maven MavenBuild
-			json.Unmarshal(configData, &maven)
-			buildConfig.Maven = &maven
-		case "django":
-			var django DjangoBuild
-			json.Unmarshal(configData, &django)
-			buildConfig.Django = &django
-		case "flask":
-			var flask FlaskBuild
-			json.Unmarshal(configData, &flask)
-			buildConfig.Flask = &flask
-		case "docker":
-			var docker DockerBuild
-			json.Unmarshal(configData, &docker)
-			buildConfig.Docker = &docker
+	// Add build config if present; applications saved before build blocks were
+	// validated may also hold blocks of other types
+	if block, ok := app.BuildConfig[app.BuildType]; ok && app.BuildType != "" {
+		response.Build = BuildConfig{app.BuildType: block}
+	}
+
+	return response
+}
+
+func (s *Service) GetBuildTypes() []*BuildTypeResponse {
+	types := BuildTypes()
+
+	responses := make([]*BuildTypeResponse, len(types))
+	for i, buildType := range types {
+		responses[i] = &BuildTypeResponse{
+			Name:           buildType.Name,
+			Versions:       buildType.Versions,
+			DefaultVersion: buildType.defaultVersion(),
+			Schema:         buildType.Schema(),
 		}
+	}
 
-		response.Build = buildConfig
+	return responses
+}
+
+// deployEnvironments triggers a deployment of every environment of the
+// application.
+func (s *Service) deployEnvironments(ctx context.Context, app *Application, action string, requestedBy *uint) error {
+	envs, err := s.repo.FindEnvironments(ctx, app.ID)
+	if err != nil {
+		return err
 	}
 
-	return response
+	for _, env := range envs {
+		if err := s.triggerDeployment(ctx, app, env, action, requestedBy, ""); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
-func (s *Service) determineBuildType(build *BuildConfig) string {
-	buildTypeMap := map[string]interface{}{
-		"gradle": build.Gradle,
-		"nodejs": build.NodeJS,
-		"react":  build.React,
-		"vite":   build.Vite,
-		"vue":    build.Vue,
-		"nextjs": build.NextJS,
-		"go":     build.Go,
-		"rust":   build.Rust,
-		"maven":  build.Maven,
-		"django": build.Django,
-		"flask":  build.Flask,
-		"docker": build.Docker,
-	}
-
-	for buildType, config := range buildTypeMap {
-		if config != nil {
-			return buildType
+// removeMoved dispatches a remove of every environment from where it was
+// deployed before an update, when the application was renamed, moved to
+// another repository without a GitOps target, or disconnected from GitHub.
+// The following apply only reaches the new location, so the old config would
+// otherwise be left behind. It must run before the application is saved.
+func (s *Service) removeMoved(ctx context.Context, previous, app *Application, userID uint) error {
+	if previous.GitHubOwner == "" {
+		return nil
+	}
+
+	moved := app.GitHubOwner == "" || previous.Name != app.Name
+	if !moved && (previous.GitHubOwner != app.GitHubOwner || previous.GitHubRepo != app.GitHubRepo) {
+		target, err := s.gitopsSvc.Resolve(ctx, app.ProjectID, gitops.ResourceApplication)
+		if err != nil {
+			return err
 		}
+		moved = target == nil
+	}
+	if !moved {
+		return nil
 	}
-	return ""
+
+	return s.deployEnvironments(ctx, previous, "remove", &userID)
 }
 
-func (s *Service) triggerDeployment(app *Application, action string) {
-	if s.githubSvc == nil || app.GitHubOwner == "" {
-		return
+// triggerDeployment records a deployment for an environment of the application
+// and queues its GitHub Actions dispatch in the outbox. It must run inside the
+// transaction that saves the application; the outbox worker reports the
+// dispatch outcome back to the deployment record. requestedBy is nil for
+// deployments triggered by a push.
+func (s *Service) triggerDeployment(ctx context.Context, app *Application, environment *Environment, action string, requestedBy *uint, commitSHA string) error {
+	if app.GitHubOwner == "" {
+		return nil
+	}
+
+	proj, err := s.projectRepo.FindByID(ctx, app.ProjectID)
+	if err != nil {
+		return err
+	}
+	path := project.ApplicationPath(proj.Slug, app.Name)
+	if !environment.IsDefault {
+		path = project.EnvironmentPath(proj.Slug, app.Name, environment.Name)
+	}
+
+	// Environments saved before hostnames were allocated get one on their next deployment
+	if action == "apply" && environment.Hostname == "" {
+		if err := s.saveEnvironment(ctx, app, environment); err != nil {
+			return err
+		}
 	}
 
-	// Get project name (this would need to be passed or retrieved)
-	projectName := "project-" + string(rune(app.ProjectID))
-	path := "projects/" + projectName + "/applications/" + app.Name
-	
 	spec := map[string]interface{}{
-		"tier": app.Tier,
+		"environment": environment.Name,
+		"tier":        environment.Tier,
+	}
+
+	if environment.Hostname != "" {
+		spec["hostname"] = environment.Hostname
+	}
+
+	resources, err := s.tierSvc.Spec(ctx, environment.Tier)
+	if err != nil {
+		return err
+	}
+	if resources != nil {
+		spec["resources"] = resources
 	}
-	
-	if len(app.Endpoints) > 0 {
-		spec["endpoints"] = app.Endpoints
+
+	if environment.Branch != "" {
+		spec["branch"] = environment.Branch
+	}
+
+	if len(environment.Endpoints) > 0 {
+		spec["endpoints"] = environment.Endpoints
 	}
-	
+
+	domains, err := s.domainSpec(ctx, environment)
+	if err != nil {
+		return err
+	}
+	if len(domains) > 0 {
+		spec["domains"] = domains
+	}
+
+	// Secret values are dispatched but stay out of the recorded spec
+	var secrets map[string]string
+	if action == "apply" {
+		env, runtimeSecrets, err := s.runtimeEnv(ctx, app, environment)
+		if err != nil {
+			return err
+		}
+		if len(env) > 0 {
+			spec["env"] = env
+		}
+		// Sealed until the outbox worker dispatches them
+		if len(runtimeSecrets) > 0 {
+			secrets, err = s.keyring.EncryptValues(runtimeSecrets)
+			if err != nil {
+				return errors.Internal("Failed to encrypt secrets")
+			}
+		}
+	}
+
+	// Without a GitOps target the spec is dispatched to the application's own repository
+	installationID, owner, repo, eventType := app.GitHubInstallationID, app.GitHubOwner, app.GitHubRepo, github.ConfigAPIEventType
+	target, err := s.gitopsSvc.Resolve(ctx, app.ProjectID, gitops.ResourceApplication)
+	if err != nil {
+		return err
+	}
+	if target != nil {
+		installationID, owner, repo, eventType = target.InstallationID, target.Owner, target.Repo, target.EventType
+	}
+
+	d := &deployment.Deployment{
+		ProjectID:       app.ProjectID,
+		ApplicationID:   &app.ID,
+		ApplicationName: app.Name,
+		EnvironmentID:   &environment.ID,
+		EnvironmentName: environment.Name,
+		Action:          action,
+		Spec:            spec,
+		CommitSHA:       commitSHA,
+		TargetOwner:     owner,
+		TargetRepo:      repo,
+		RequestedBy:     requestedBy,
+	}
+	if err := s.deploymentSvc.Record(ctx, d); err != nil {
+		return err
+	}
+
 	payload := github.ConfigAPIPayload{
-		Path:   path,
-		Action: action,
-		Spec:   spec,
-	}
-	
-	ctx := context.Background()
-	s.githubSvc.TriggerGitHubAction(ctx, app.GitHubOwner, app.GitHubRepo, payload)
-}
\ No newline at end of file
+		Path:          path,
+		Action:        action,
+		Spec:          spec,
+		Secrets:       secrets,
+		CorrelationID: d.CorrelationID,
+	}
+
+	return s.outboxSvc.Enqueue(ctx, outbox.AggregateDeployment, d.ID,
+		installationID, owner, repo, eventType, path, payload)
+}
@@ -0,0 +1,68 @@
+package application
+
+import (
+	"path"
+	"strings"
+)
+
+// matchesTriggerPaths reports whether any changed file matches one of the
+// trigger path patterns. An application without trigger paths is redeployed
+// on every push to its branch.
+func matchesTriggerPaths(patterns, changedFiles []string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+
+	for _, file := range changedFiles {
+		for _, pattern := range patterns {
+			if matchGlob(pattern, file) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// matchGlob matches a slash-separated file path against a glob pattern. Each
+// segment follows path.Match, "**" matches any number of segments and a
+// trailing slash matches everything below a directory.
+func matchGlob(pattern, name string) bool {
+	pattern = strings.TrimPrefix(pattern, "/")
+	if strings.HasSuffix(pattern, "/") {
+		pattern += "**"
+	}
+
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+func matchSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			// Collapse consecutive "**" and try every possible split
+			for len(pattern) > 0 && pattern[0] == "**" {
+				pattern = pattern[1:]
+			}
+			if len(pattern) == 0 {
+				return true
+			}
+			for i := range name {
+				if matchSegments(pattern, name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+
+		if len(name) == 0 {
+			return false
+		}
+		if ok, err := path.Match(pattern[0], name[0]); err != nil || !ok {
+			return false
+		}
+		pattern = pattern[1:]
+		name = name[1:]
+	}
+
+	return len(name) == 0
+}
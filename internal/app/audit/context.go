@@ -0,0 +1,41 @@
+package audit
+
+import (
+	"context"
+	"sync"
+)
+
+type recorderKey struct{}
+
+// recorder collects the events recorded while a request is served; the audit
+// middleware saves them once the handler returns.
+type recorder struct {
+	mu     sync.Mutex
+	events []*Event
+}
+
+func withRecorder(ctx context.Context) (context.Context, *recorder) {
+	r := &recorder{}
+	return context.WithValue(ctx, recorderKey{}, r), r
+}
+
+// Record adds an event to the audit log of the current request. The actor,
+// request ID and client IP are filled in from the request unless set. Outside
+// an API request, such as in webhook processing or the outbox worker, it does
+// nothing. Services record after their change has been committed.
+func Record(ctx context.Context, event Event) {
+	r, ok := ctx.Value(recorderKey{}).(*recorder)
+	if !ok {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, &event)
+}
+
+func (r *recorder) recorded() []*Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.events
+}
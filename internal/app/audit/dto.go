@@ -0,0 +1,8 @@
+package audit
+
+import "github.com/team-xquare/deployment-platform/internal/pkg/utils/pagination"
+
+type EventListResponse struct {
+	Events []*Event `json:"events"`
+	pagination.Page
+}
@@ -0,0 +1,145 @@
+package audit
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/team-xquare/deployment-platform/internal/pkg/middleware"
+	"github.com/team-xquare/deployment-platform/internal/pkg/utils/errors"
+	"github.com/team-xquare/deployment-platform/internal/pkg/utils/pagination"
+)
+
+type Handler struct {
+	service *Service
+}
+
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+func (h *Handler) RegisterRoutes(r *gin.RouterGroup) {
+	projects := r.Group("/projects/:id/audit-events")
+	projects.Use(middleware.Auth())
+	{
+		projects.GET("", h.GetProjectEvents)
+	}
+
+	users := r.Group("/users/me/audit-events")
+	users.Use(middleware.Auth())
+	{
+		users.GET("", h.GetMyEvents)
+	}
+
+	admin := r.Group("/admin/audit-events")
+	admin.Use(middleware.Auth(), middleware.Admin())
+	{
+		admin.GET("", h.GetEvents)
+	}
+}
+
+func (h *Handler) GetProjectEvents(c *gin.Context) {
+	projectIDStr := c.Param("id")
+	projectID, err := strconv.ParseUint(projectIDStr, 10, 32)
+	if err != nil {
+		c.Error(errors.BadRequest("Invalid project ID"))
+		return
+	}
+
+	filter, err := parseFilter(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	userID := c.GetUint("user_id")
+
+	events, err := h.service.GetProjectEvents(c.Request.Context(), userID, uint(projectID), filter)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, events)
+}
+
+func (h *Handler) GetMyEvents(c *gin.Context) {
+	filter, err := parseFilter(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	userID := c.GetUint("user_id")
+
+	events, err := h.service.GetUserEvents(c.Request.Context(), userID, filter)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, events)
+}
+
+func (h *Handler) GetEvents(c *gin.Context) {
+	filter, err := parseFilter(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	if projectIDStr := c.Query("project_id"); projectIDStr != "" {
+		projectID, err := strconv.ParseUint(projectIDStr, 10, 32)
+		if err != nil {
+			c.Error(errors.BadRequest("Invalid project ID"))
+			return
+		}
+		id := uint(projectID)
+		filter.ProjectID = &id
+	}
+	if userIDStr := c.Query("user_id"); userIDStr != "" {
+		userID, err := strconv.ParseUint(userIDStr, 10, 32)
+		if err != nil {
+			c.Error(errors.BadRequest("Invalid user ID"))
+			return
+		}
+		id := uint(userID)
+		filter.ActorID = &id
+	}
+
+	events, err := h.service.GetEvents(c.Request.Context(), filter)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, events)
+}
+
+// parseFilter reads the query parameters shared by every audit listing.
+func parseFilter(c *gin.Context) (Filter, error) {
+	page, err := pagination.Parse(c, "-"+pagination.SortCreated, pagination.SortCreated)
+	if err != nil {
+		return Filter{}, err
+	}
+
+	filter := Filter{
+		Action:       c.Query("action"),
+		ResourceType: c.Query("resource_type"),
+		ResourceID:   c.Query("resource_id"),
+		Page:         page,
+	}
+
+	for param, target := range map[string]**time.Time{"since": &filter.Since, "until": &filter.Until} {
+		if value := c.Query(param); value != "" {
+			t, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				return Filter{}, errors.BadRequest("Invalid " + param + "; use RFC 3339, e.g. 2024-01-02T15:04:05Z")
+			}
+			*target = &t
+		}
+	}
+
+	return filter, nil
+}
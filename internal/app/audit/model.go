@@ -0,0 +1,44 @@
+package audit
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/team-xquare/deployment-platform/internal/pkg/utils/pagination"
+)
+
+// Event records one change made through the API, or an authentication
+// attempt. Before and After are snapshots of the resource as returned by the
+// API, so secrets never reach the log; Before is nil for creations and After
+// for deletions. Events outlive the users and projects they name.
+type Event struct {
+	ID           uint        `json:"id" db:"id"`
+	ActorID      *uint       `json:"actor_id" db:"actor_id"`
+	ActorEmail   string      `json:"actor_email" db:"actor_email"`
+	Action       string      `json:"action" db:"action"`
+	ResourceType string      `json:"resource_type" db:"resource_type"`
+	ResourceID   string      `json:"resource_id" db:"resource_id"`
+	ProjectID    *uint       `json:"project_id" db:"project_id"`
+	RequestID    string      `json:"request_id" db:"request_id"`
+	ClientIP     string      `json:"client_ip" db:"client_ip"`
+	Before       interface{} `json:"before" db:"before_snapshot"`
+	After        interface{} `json:"after" db:"after_snapshot"`
+	CreatedAt    time.Time   `json:"created_at" db:"created_at"`
+}
+
+// Filter narrows and pages a query of the log. Zero fields match everything.
+type Filter struct {
+	ProjectID    *uint
+	ActorID      *uint
+	Action       string
+	ResourceType string
+	ResourceID   string
+	Since        *time.Time
+	Until        *time.Time
+	Page         pagination.Params
+}
+
+// ID formats a numeric resource ID.
+func ID(id uint) string {
+	return strconv.FormatUint(uint64(id), 10)
+}
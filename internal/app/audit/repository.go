@@ -0,0 +1,9 @@
+package audit
+
+import "context"
+
+type Repository interface {
+	Save(ctx context.Context, event *Event) error
+	// Find returns one page of the events matching the filter and how many match in all
+	Find(ctx context.Context, filter Filter) ([]*Event, int, error)
+}
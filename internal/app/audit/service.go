@@ -0,0 +1,125 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"expvar"
+	"log"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/team-xquare/deployment-platform/internal/pkg/utils/pagination"
+)
+
+// saveAttempts bounds how often an event is written before it is given up.
+const saveAttempts = 3
+
+// FailedSaves counts the events that could not be written to the log. It is
+// published with expvar so the loss can be monitored and alerted on.
+var FailedSaves = expvar.NewInt("audit_events_failed")
+
+// ProjectAuthorizer checks that a user may read a project's audit log. It is
+// satisfied by the member service; taking an interface keeps this package
+// free of dependencies so every service can record events.
+type ProjectAuthorizer interface {
+	AuthorizeAuditLog(ctx context.Context, userID, projectID uint) error
+}
+
+type Service struct {
+	repo       Repository
+	authorizer ProjectAuthorizer
+}
+
+func NewService(repo Repository, authorizer ProjectAuthorizer) *Service {
+	return &Service{repo: repo, authorizer: authorizer}
+}
+
+// Middleware lets the handlers of a request record audit events and saves
+// them after the handler returns, whatever the response. It must run after
+// middleware.RequestID.
+func (s *Service) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, r := withRecorder(c.Request.Context())
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+
+		events := r.recorded()
+		if len(events) == 0 {
+			return
+		}
+
+		// The log is written even when the client has already gone away
+		saveCtx := context.WithoutCancel(ctx)
+		for _, event := range events {
+			if event.ActorID == nil {
+				if userID := c.GetUint("user_id"); userID != 0 {
+					event.ActorID = &userID
+				}
+			}
+			if event.ActorEmail == "" {
+				event.ActorEmail = c.GetString("email")
+			}
+			event.RequestID = c.GetString("request_id")
+			event.ClientIP = c.ClientIP()
+
+			s.save(saveCtx, event)
+		}
+	}
+}
+
+// save writes an event to the log, retrying briefly. The response has already
+// been sent, so an event that still cannot be written is counted in
+// FailedSaves and logged in full, from where it can be restored.
+func (s *Service) save(ctx context.Context, event *Event) {
+	var err error
+	for attempt := 1; attempt <= saveAttempts; attempt++ {
+		if err = s.repo.Save(ctx, event); err == nil {
+			return
+		}
+		if attempt < saveAttempts {
+			time.Sleep(time.Duration(attempt) * 100 * time.Millisecond)
+		}
+	}
+
+	FailedSaves.Add(1)
+	data, _ := json.Marshal(event)
+	log.Printf("audit: failed to save %s event for request %s: %v; event: %s", event.Action, event.RequestID, err, data)
+}
+
+// GetProjectEvents lists the events of a project to its maintainers.
+func (s *Service) GetProjectEvents(ctx context.Context, userID, projectID uint, filter Filter) (*EventListResponse, error) {
+	if err := s.authorizer.AuthorizeAuditLog(ctx, userID, projectID); err != nil {
+		return nil, err
+	}
+
+	filter.ProjectID = &projectID
+	return s.find(ctx, filter)
+}
+
+// GetUserEvents lists the events the user is the actor of.
+func (s *Service) GetUserEvents(ctx context.Context, userID uint, filter Filter) (*EventListResponse, error) {
+	filter.ActorID = &userID
+	return s.find(ctx, filter)
+}
+
+// GetEvents lists events across the platform for administrators.
+func (s *Service) GetEvents(ctx context.Context, filter Filter) (*EventListResponse, error) {
+	return s.find(ctx, filter)
+}
+
+func (s *Service) find(ctx context.Context, filter Filter) (*EventListResponse, error) {
+	events, total, err := s.repo.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	events, page := pagination.Paginate(events, filter.Page, total, func(e *Event) (uint, string) {
+		return e.ID, ""
+	})
+	if events == nil {
+		events = []*Event{}
+	}
+
+	return &EventListResponse{Events: events, Page: page}, nil
+}
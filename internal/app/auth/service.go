@@ -4,6 +4,7 @@ import (
 	"context"
 	"time"
 
+	"github.com/team-xquare/deployment-platform/internal/app/audit"
 	"github.com/team-xquare/deployment-platform/internal/app/user"
 	"github.com/team-xquare/deployment-platform/internal/pkg/utils/errors"
 	"github.com/team-xquare/deployment-platform/internal/pkg/utils/jwt"
@@ -22,6 +23,13 @@ func (s *Service) Login(ctx context.Context, req user.LoginRequest) (*LoginRespo
 	userSvc := user.NewService(s.userRepo)
 	authenticatedUser, err := userSvc.Login(ctx, req)
 	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok && appErr.StatusCode == 401 {
+			audit.Record(ctx, audit.Event{
+				ActorEmail:   req.Email,
+				Action:       "auth.login_failed",
+				ResourceType: "user",
+			})
+		}
 		return nil, err
 	}
 
@@ -35,6 +43,14 @@ func (s *Service) Login(ctx context.Context, req user.LoginRequest) (*LoginRespo
 		return nil, err
 	}
 
+	audit.Record(ctx, audit.Event{
+		ActorID:      &authenticatedUser.ID,
+		ActorEmail:   authenticatedUser.Email,
+		Action:       "auth.login",
+		ResourceType: "user",
+		ResourceID:   audit.ID(authenticatedUser.ID),
+	})
+
 	return &LoginResponse{
 		AccessToken:  accessToken,
 		RefreshToken: refreshToken,
@@ -77,6 +93,14 @@ func (s *Service) RefreshToken(ctx context.Context, req RefreshTokenRequest) (*L
 		return nil, err
 	}
 
+	audit.Record(ctx, audit.Event{
+		ActorID:      &user.ID,
+		ActorEmail:   user.Email,
+		Action:       "auth.refresh",
+		ResourceType: "user",
+		ResourceID:   audit.ID(user.ID),
+	})
+
 	return &LoginResponse{
 		AccessToken:  accessToken,
 		RefreshToken: newRefreshToken,
@@ -89,5 +113,21 @@ func (s *Service) RefreshToken(ctx context.Context, req RefreshTokenRequest) (*L
 }
 
 func (s *Service) Logout(ctx context.Context, refreshToken string) error {
-	return s.repo.DeleteRefreshToken(ctx, refreshToken)
+	// Logout is unauthenticated, so the token is the only clue to the actor
+	userID, lookupErr := s.repo.GetRefreshToken(ctx, refreshToken)
+
+	if err := s.repo.DeleteRefreshToken(ctx, refreshToken); err != nil {
+		return err
+	}
+
+	if lookupErr == nil {
+		audit.Record(ctx, audit.Event{
+			ActorID:      &userID,
+			Action:       "auth.logout",
+			ResourceType: "user",
+			ResourceID:   audit.ID(userID),
+		})
+	}
+
+	return nil
 }
@@ -0,0 +1,35 @@
+package deployment
+
+import (
+	"time"
+
+	"github.com/team-xquare/deployment-platform/internal/pkg/utils/pagination"
+)
+
+type DeploymentResponse struct {
+	ID              uint        `json:"id"`
+	ProjectID       uint        `json:"project_id"`
+	ApplicationID   *uint       `json:"application_id,omitempty"`
+	ApplicationName string      `json:"application_name,omitempty"`
+	EnvironmentID   *uint       `json:"environment_id,omitempty"`
+	EnvironmentName string      `json:"environment_name,omitempty"`
+	AddonID         *uint       `json:"addon_id,omitempty"`
+	AddonName       string      `json:"addon_name,omitempty"`
+	Action          string      `json:"action"`
+	Spec            interface{} `json:"spec,omitempty"`
+	CommitSHA       string      `json:"commit_sha,omitempty"`
+	CorrelationID   string      `json:"correlation_id,omitempty"`
+	RunURL          string      `json:"run_url,omitempty"`
+	RequestedBy     *uint       `json:"requested_by,omitempty"`
+	Status          string      `json:"status"`
+	ErrorMessage    string      `json:"error_message,omitempty"`
+	DispatchedAt    *time.Time  `json:"dispatched_at,omitempty"`
+	CompletedAt     *time.Time  `json:"completed_at,omitempty"`
+	CreatedAt       time.Time   `json:"created_at"`
+	UpdatedAt       time.Time   `json:"updated_at"`
+}
+
+type DeploymentListResponse struct {
+	Deployments []*DeploymentResponse `json:"deployments"`
+	pagination.Page
+}
@@ -0,0 +1,44 @@
+package deployment
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/team-xquare/deployment-platform/internal/pkg/middleware"
+	"github.com/team-xquare/deployment-platform/internal/pkg/utils/errors"
+)
+
+type Handler struct {
+	service *Service
+}
+
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+func (h *Handler) RegisterRoutes(r *gin.RouterGroup) {
+	deployments := r.Group("/deployments")
+	deployments.Use(middleware.Auth())
+	{
+		deployments.GET("/:id", h.GetDeployment)
+	}
+}
+
+func (h *Handler) GetDeployment(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.Error(errors.BadRequest("Invalid deployment ID"))
+		return
+	}
+
+	userID := c.GetUint("user_id")
+	deployment, err := h.service.GetDeployment(c.Request.Context(), userID, uint(id))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, deployment)
+}
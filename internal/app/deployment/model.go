@@ -0,0 +1,40 @@
+package deployment
+
+import "time"
+
+const (
+	StatusPending    = "pending"
+	StatusDispatched = "dispatched"
+	StatusFailed     = "failed"
+	StatusSucceeded  = "succeeded"
+)
+
+// Deployment records one apply or remove dispatch for either an application or
+// an addon; exactly one of ApplicationID and AddonID is set, and application
+// deployments also name the environment they target. CorrelationID is
+// sent with the dispatch and echoed back by the infrastructure workflow so its
+// run can be matched to the deployment.
+type Deployment struct {
+	ID              uint        `json:"id" db:"id"`
+	ProjectID       uint        `json:"project_id" db:"project_id"`
+	ApplicationID   *uint       `json:"application_id" db:"application_id"`
+	ApplicationName string      `json:"application_name" db:"application_name"`
+	EnvironmentID   *uint       `json:"environment_id" db:"environment_id"`
+	EnvironmentName string      `json:"environment_name" db:"environment_name"`
+	AddonID         *uint       `json:"addon_id" db:"addon_id"`
+	AddonName       string      `json:"addon_name" db:"addon_name"`
+	Action          string      `json:"action" db:"action"`
+	Spec            interface{} `json:"spec" db:"spec"`
+	CommitSHA       string      `json:"commit_sha" db:"commit_sha"`
+	CorrelationID   string      `json:"correlation_id" db:"correlation_id"`
+	TargetOwner     string      `json:"target_owner" db:"target_owner"`
+	TargetRepo      string      `json:"target_repo" db:"target_repo"`
+	RunURL          string      `json:"run_url" db:"run_url"`
+	RequestedBy     *uint       `json:"requested_by" db:"requested_by"`
+	Status          string      `json:"status" db:"status"`
+	ErrorMessage    string      `json:"error_message" db:"error_message"`
+	DispatchedAt    *time.Time  `json:"dispatched_at" db:"dispatched_at"`
+	CompletedAt     *time.Time  `json:"completed_at" db:"completed_at"`
+	CreatedAt       time.Time   `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time   `json:"updated_at" db:"updated_at"`
+}
@@ -0,0 +1,19 @@
+package deployment
+
+import (
+	"context"
+
+	"github.com/team-xquare/deployment-platform/internal/pkg/utils/pagination"
+)
+
+type Repository interface {
+	Save(ctx context.Context, deployment *Deployment) error
+	FindByID(ctx context.Context, id uint) (*Deployment, error)
+	FindByApplicationID(ctx context.Context, applicationID uint, page pagination.Params) ([]*Deployment, int, error)
+	FindByEnvironmentID(ctx context.Context, environmentID uint, page pagination.Params) ([]*Deployment, int, error)
+	FindByAddonID(ctx context.Context, addonID uint, page pagination.Params) ([]*Deployment, int, error)
+	// FindByCorrelationID returns nil, nil when no deployment has the ID
+	FindByCorrelationID(ctx context.Context, correlationID string) (*Deployment, error)
+	UpdateStatus(ctx context.Context, id uint, status, errorMessage string) error
+	UpdateRunURL(ctx context.Context, id uint, runURL string) error
+}
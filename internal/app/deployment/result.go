@@ -0,0 +1,83 @@
+package deployment
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"regexp"
+	"strings"
+
+	"github.com/team-xquare/deployment-platform/internal/app/github"
+)
+
+// The infrastructure workflow is expected to put the correlation_id from the
+// dispatch payload in its run name (or a check run's external ID), which is
+// how workflow_run and check_run events are matched back to a deployment.
+var correlationIDPattern = regexp.MustCompile(`deploy-[0-9a-f]{24}`)
+
+func newCorrelationID() (string, error) {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "deploy-" + hex.EncodeToString(b), nil
+}
+
+func findCorrelationID(texts ...string) string {
+	for _, text := range texts {
+		if id := correlationIDPattern.FindString(text); id != "" {
+			return id
+		}
+	}
+	return ""
+}
+
+// HandleWorkflowRun implements github.WorkflowRunListener.
+func (s *Service) HandleWorkflowRun(ctx context.Context, event *github.WorkflowRunEvent) error {
+	correlationID := findCorrelationID(event.DisplayTitle, event.Name)
+	return s.recordRunResult(ctx, event.Owner, event.Repo, correlationID, event.Status, event.Conclusion, event.HTMLURL)
+}
+
+// HandleCheckRun implements github.CheckRunListener.
+func (s *Service) HandleCheckRun(ctx context.Context, event *github.CheckRunEvent) error {
+	correlationID := findCorrelationID(event.ExternalID, event.Name, event.OutputTitle, event.OutputSummary)
+	return s.recordRunResult(ctx, event.Owner, event.Repo, correlationID, event.Status, event.Conclusion, event.HTMLURL)
+}
+
+// recordRunResult links the run to the deployment and, once the run has
+// completed, moves the deployment to its final state. Runs that carry no
+// known correlation ID, or that belong to another repository than the one
+// the deployment was dispatched to, are ignored.
+func (s *Service) recordRunResult(ctx context.Context, owner, repo, correlationID, status, conclusion, runURL string) error {
+	if correlationID == "" {
+		return nil
+	}
+
+	deployment, err := s.repo.FindByCorrelationID(ctx, correlationID)
+	if err != nil {
+		return err
+	}
+	if deployment == nil {
+		return nil
+	}
+	// Deployments recorded before their target was stored accept any repository
+	if deployment.TargetOwner != "" &&
+		(!strings.EqualFold(owner, deployment.TargetOwner) || !strings.EqualFold(repo, deployment.TargetRepo)) {
+		return nil
+	}
+
+	if runURL != "" && runURL != deployment.RunURL {
+		if err := s.repo.UpdateRunURL(ctx, deployment.ID, runURL); err != nil {
+			return err
+		}
+	}
+
+	if status != "completed" {
+		return nil
+	}
+
+	if conclusion == "success" {
+		return s.MarkSucceeded(ctx, deployment.ID)
+	}
+	return s.MarkFailed(ctx, deployment.ID, "Workflow run concluded with "+conclusion)
+}
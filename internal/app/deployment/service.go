@@ -0,0 +1,140 @@
+package deployment
+
+import (
+	"context"
+
+	"github.com/team-xquare/deployment-platform/internal/app/member"
+	"github.com/team-xquare/deployment-platform/internal/app/outbox"
+	"github.com/team-xquare/deployment-platform/internal/pkg/utils/errors"
+	"github.com/team-xquare/deployment-platform/internal/pkg/utils/pagination"
+)
+
+type Service struct {
+	repo      Repository
+	memberSvc *member.Service
+}
+
+func NewService(repo Repository, memberSvc *member.Service) *Service {
+	return &Service{repo: repo, memberSvc: memberSvc}
+}
+
+// Record stores a new pending deployment and assigns its correlation ID. It
+// joins the caller's transaction so the deployment is written together with
+// its outbox message.
+func (s *Service) Record(ctx context.Context, deployment *Deployment) error {
+	correlationID, err := newCorrelationID()
+	if err != nil {
+		return errors.Internal("Failed to generate correlation ID")
+	}
+
+	deployment.Status = StatusPending
+	deployment.CorrelationID = correlationID
+	return s.repo.Save(ctx, deployment)
+}
+
+func (s *Service) MarkDispatched(ctx context.Context, id uint) error {
+	return s.repo.UpdateStatus(ctx, id, StatusDispatched, "")
+}
+
+func (s *Service) MarkFailed(ctx context.Context, id uint, errorMessage string) error {
+	return s.repo.UpdateStatus(ctx, id, StatusFailed, errorMessage)
+}
+
+func (s *Service) MarkSucceeded(ctx context.Context, id uint) error {
+	return s.repo.UpdateStatus(ctx, id, StatusSucceeded, "")
+}
+
+// HandleDelivered implements outbox.ResultHandler.
+func (s *Service) HandleDelivered(ctx context.Context, message *outbox.Message) error {
+	return s.MarkDispatched(ctx, message.AggregateID)
+}
+
+// HandleDeadLettered implements outbox.ResultHandler.
+func (s *Service) HandleDeadLettered(ctx context.Context, message *outbox.Message) error {
+	return s.MarkFailed(ctx, message.AggregateID, message.LastError)
+}
+
+func (s *Service) GetDeployment(ctx context.Context, userID, id uint) (*DeploymentResponse, error) {
+	deployment, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.memberSvc.Authorize(ctx, userID, deployment.ProjectID, member.RoleViewer); err != nil {
+		return nil, err
+	}
+
+	return s.toResponse(deployment), nil
+}
+
+// GetApplicationDeployments lists an application's deployments, newest first.
+// Callers are expected to have authorized access to the application already.
+func (s *Service) GetApplicationDeployments(ctx context.Context, applicationID uint, page pagination.Params) (*DeploymentListResponse, error) {
+	deployments, total, err := s.repo.FindByApplicationID(ctx, applicationID, page)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.toListResponse(deployments, page, total), nil
+}
+
+// GetEnvironmentDeployments lists the deployments of one application
+// environment, newest first. Callers are expected to have authorized access to
+// the application already.
+func (s *Service) GetEnvironmentDeployments(ctx context.Context, environmentID uint, page pagination.Params) (*DeploymentListResponse, error) {
+	deployments, total, err := s.repo.FindByEnvironmentID(ctx, environmentID, page)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.toListResponse(deployments, page, total), nil
+}
+
+// GetAddonDeployments lists an addon's deployments, newest first. Callers are
+// expected to have authorized access to the addon already.
+func (s *Service) GetAddonDeployments(ctx context.Context, addonID uint, page pagination.Params) (*DeploymentListResponse, error) {
+	deployments, total, err := s.repo.FindByAddonID(ctx, addonID, page)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.toListResponse(deployments, page, total), nil
+}
+
+func (s *Service) toListResponse(deployments []*Deployment, page pagination.Params, total int) *DeploymentListResponse {
+	deployments, meta := pagination.Paginate(deployments, page, total, func(d *Deployment) (uint, string) {
+		return d.ID, ""
+	})
+
+	responses := make([]*DeploymentResponse, len(deployments))
+	for i, deployment := range deployments {
+		responses[i] = s.toResponse(deployment)
+	}
+
+	return &DeploymentListResponse{Deployments: responses, Page: meta}
+}
+
+func (s *Service) toResponse(deployment *Deployment) *DeploymentResponse {
+	return &DeploymentResponse{
+		ID:              deployment.ID,
+		ProjectID:       deployment.ProjectID,
+		ApplicationID:   deployment.ApplicationID,
+		ApplicationName: deployment.ApplicationName,
+		EnvironmentID:   deployment.EnvironmentID,
+		EnvironmentName: deployment.EnvironmentName,
+		AddonID:         deployment.AddonID,
+		AddonName:       deployment.AddonName,
+		Action:          deployment.Action,
+		Spec:            deployment.Spec,
+		CommitSHA:       deployment.CommitSHA,
+		CorrelationID:   deployment.CorrelationID,
+		RunURL:          deployment.RunURL,
+		RequestedBy:     deployment.RequestedBy,
+		Status:          deployment.Status,
+		ErrorMessage:    deployment.ErrorMessage,
+		DispatchedAt:    deployment.DispatchedAt,
+		CompletedAt:     deployment.CompletedAt,
+		CreatedAt:       deployment.CreatedAt,
+		UpdatedAt:       deployment.UpdatedAt,
+	}
+}
@@ -0,0 +1,136 @@
+package github
+
+import (
+	"context"
+	"crypto/rsa"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	jwt "github.com/golang-jwt/jwt/v4"
+	"github.com/google/go-github/v66/github"
+	"github.com/team-xquare/deployment-platform/internal/pkg/utils/errors"
+)
+
+const (
+	// GitHub rejects app JWTs that live longer than 10 minutes
+	appJWTLifetime = 9 * time.Minute
+	// Tokens are refreshed this long before GitHub expires them
+	tokenRefreshMargin = time.Minute
+)
+
+type installationToken struct {
+	token     string
+	expiresAt time.Time
+}
+
+// appAuth authenticates as the GitHub App: it signs app JWTs with the private
+// key and exchanges them for installation access tokens, which are cached
+// until shortly before they expire.
+type appAuth struct {
+	appID      int64
+	privateKey *rsa.PrivateKey
+	baseClient *github.Client
+
+	mu           sync.Mutex
+	appJWT       string
+	appJWTExpiry time.Time
+	tokens       map[int64]installationToken
+}
+
+func newAppAuth(appID, privateKeyPEM string, baseClient *github.Client) (*appAuth, error) {
+	id, err := strconv.ParseInt(appID, 10, 64)
+	if err != nil {
+		return nil, errors.Internal("Invalid GitHub App ID")
+	}
+
+	// Keys passed through environment variables often have escaped newlines
+	privateKeyPEM = strings.ReplaceAll(privateKeyPEM, `\n`, "\n")
+	key, err := jwt.ParseRSAPrivateKeyFromPEM([]byte(privateKeyPEM))
+	if err != nil {
+		return nil, errors.Internal("Invalid GitHub App private key")
+	}
+
+	return &appAuth{
+		appID:      id,
+		privateKey: key,
+		baseClient: baseClient,
+		tokens:     make(map[int64]installationToken),
+	}, nil
+}
+
+// appClient returns a client authenticated as the app itself, for the /app endpoints.
+func (a *appAuth) appClient() (*github.Client, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	now := time.Now()
+	if a.appJWT == "" || now.Add(tokenRefreshMargin).After(a.appJWTExpiry) {
+		expiresAt := now.Add(appJWTLifetime)
+		claims := jwt.RegisteredClaims{
+			// Backdated to tolerate clock drift between us and GitHub
+			IssuedAt:  jwt.NewNumericDate(now.Add(-time.Minute)),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			Issuer:    strconv.FormatInt(a.appID, 10),
+		}
+
+		signed, err := jwt.NewWithClaims(jwt.SigningMethodRS256, claims).SignedString(a.privateKey)
+		if err != nil {
+			return nil, errors.Internal("Failed to sign GitHub App JWT")
+		}
+
+		a.appJWT = signed
+		a.appJWTExpiry = expiresAt
+	}
+
+	return a.baseClient.WithAuthToken(a.appJWT), nil
+}
+
+// installationClient returns a client authenticated as the given installation.
+func (a *appAuth) installationClient(ctx context.Context, installationID int64) (*github.Client, error) {
+	token, err := a.installationToken(ctx, installationID)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.baseClient.WithAuthToken(token), nil
+}
+
+func (a *appAuth) installationToken(ctx context.Context, installationID int64) (string, error) {
+	a.mu.Lock()
+	cached, ok := a.tokens[installationID]
+	a.mu.Unlock()
+
+	if ok && time.Now().Add(tokenRefreshMargin).Before(cached.expiresAt) {
+		return cached.token, nil
+	}
+
+	client, err := a.appClient()
+	if err != nil {
+		return "", err
+	}
+
+	token, _, err := client.Apps.CreateInstallationToken(ctx, installationID, nil)
+	if err != nil {
+		return "", errors.Internal("Failed to create installation access token: " + err.Error())
+	}
+
+	cached = installationToken{
+		token:     token.GetToken(),
+		expiresAt: token.GetExpiresAt().Time,
+	}
+
+	a.mu.Lock()
+	a.tokens[installationID] = cached
+	a.mu.Unlock()
+
+	return cached.token, nil
+}
+
+// forget drops a cached token, e.g. after the installation was removed.
+func (a *appAuth) forget(installationID int64) {
+	a.mu.Lock()
+	delete(a.tokens, installationID)
+	a.mu.Unlock()
+}
@@ -0,0 +1,88 @@
+package github
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/go-github/v66/github"
+	"github.com/team-xquare/deployment-platform/internal/pkg/utils/errors"
+)
+
+// maxRepositoryFileSize bounds the files read through the contents API; the
+// manifests looked at by build detection are far smaller.
+const maxRepositoryFileSize = 1 << 20
+
+// RepositoryFiles reads the files of a repository at one ref through the
+// contents API.
+type RepositoryFiles struct {
+	client *github.Client
+	owner  string
+	repo   string
+	ref    string
+	root   map[string]bool
+}
+
+// OpenRepository lists the root of owner/repo at ref on behalf of a user, who
+// must be linked to the installation. An empty ref reads the default branch.
+func (s *Service) OpenRepository(ctx context.Context, userID uint, installationID, owner, repo, ref string) (*RepositoryFiles, error) {
+	if installationID == "" {
+		return nil, errors.BadRequest("Installation ID is required")
+	}
+
+	linked, err := s.repo.IsUserLinkedToInstallation(ctx, userID, installationID)
+	if err != nil {
+		return nil, err
+	}
+	if !linked {
+		return nil, errors.Forbidden("Installation is not linked to the user")
+	}
+
+	client, err := s.clientForInstallation(ctx, installationID)
+	if err != nil {
+		return nil, err
+	}
+
+	_, entries, resp, err := client.Repositories.GetContents(ctx, owner, repo, "", &github.RepositoryContentGetOptions{Ref: ref})
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return nil, errors.NotFound("Repository or branch not found")
+		}
+		return nil, errors.Internal("Failed to read repository contents: " + err.Error())
+	}
+
+	files := &RepositoryFiles{client: client, owner: owner, repo: repo, ref: ref, root: map[string]bool{}}
+	for _, entry := range entries {
+		if entry.GetType() == "file" {
+			files.root[entry.GetName()] = true
+		}
+	}
+
+	return files, nil
+}
+
+// Has reports whether the repository root contains a file with the given name.
+func (f *RepositoryFiles) Has(name string) bool {
+	return f.root[name]
+}
+
+// Read returns the content of the file at path, or nil when there is no such
+// file or it is too large to be a manifest.
+func (f *RepositoryFiles) Read(ctx context.Context, path string) ([]byte, error) {
+	file, _, resp, err := f.client.Repositories.GetContents(ctx, f.owner, f.repo, path, &github.RepositoryContentGetOptions{Ref: f.ref})
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return nil, nil
+		}
+		return nil, errors.Internal("Failed to read " + path + ": " + err.Error())
+	}
+	if file == nil || file.GetSize() > maxRepositoryFileSize {
+		return nil, nil
+	}
+
+	content, err := file.GetContent()
+	if err != nil {
+		return nil, errors.Internal("Failed to decode " + path + ": " + err.Error())
+	}
+
+	return []byte(content), nil
+}
@@ -1,16 +1,22 @@
 package github
 
+import (
+	"encoding/json"
+	"time"
+)
+
+// LinkInstallationRequest carries the user's own GitHub user access token,
+// with which the platform checks that the user can access the installation.
+type LinkInstallationRequest struct {
+	GitHubToken string `json:"github_token"`
+}
+
 type InstallationResponse struct {
 	ID             uint   `json:"id"`
 	InstallationID string `json:"installation_id"`
 	AccountLogin   string `json:"account_login"`
 	AccountType    string `json:"account_type"`
-}
-
-type WebhookEvent struct {
-	Action       string                 `json:"action"`
-	Installation map[string]interface{} `json:"installation"`
-	Sender       map[string]interface{} `json:"sender"`
+	Suspended      bool   `json:"suspended"`
 }
 
 type RepositoryDispatchPayload struct {
@@ -18,10 +24,20 @@ type RepositoryDispatchPayload struct {
 	ClientPayload interface{} `json:"client_payload"`
 }
 
+// ConfigAPIEventType is the repository_dispatch event type the infrastructure
+// workflow listens for.
+const ConfigAPIEventType = "config-api"
+
+// ConfigAPIPayload is the client payload of a config-api dispatch. The workflow
+// echoes CorrelationID back in its run name so the run can be traced to the
+// deployment that requested it. Secrets are dispatched alongside the spec but
+// never stored in the deployment's spec snapshot.
 type ConfigAPIPayload struct {
-	Path   string      `json:"path"`
-	Action string      `json:"action"`
-	Spec   interface{} `json:"spec"`
+	Path          string            `json:"path"`
+	Action        string            `json:"action"`
+	Spec          interface{}       `json:"spec"`
+	Secrets       map[string]string `json:"secrets,omitempty"`
+	CorrelationID string            `json:"correlation_id,omitempty"`
 }
 
 type GitHubRepo struct {
@@ -35,3 +51,24 @@ type GitHubRepo struct {
 type Owner struct {
 	Login string `json:"login"`
 }
+
+// WebhookSignature carries the X-Hub-Signature-256 header and the legacy
+// SHA-1 X-Hub-Signature header of a delivery.
+type WebhookSignature struct {
+	SHA256 string
+	SHA1   string
+}
+
+type WebhookDeliveryResponse struct {
+	ID             uint            `json:"id"`
+	DeliveryID     string          `json:"delivery_id"`
+	EventType      string          `json:"event_type"`
+	Payload        json.RawMessage `json:"payload,omitempty"`
+	SignatureValid bool            `json:"signature_valid"`
+	Status         string          `json:"status"`
+	ErrorMessage   string          `json:"error_message,omitempty"`
+	DurationMs     *int64          `json:"duration_ms,omitempty"`
+	Attempts       int             `json:"attempts"`
+	ProcessedAt    *time.Time      `json:"processed_at,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+}
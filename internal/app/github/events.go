@@ -0,0 +1,96 @@
+package github
+
+import "context"
+
+// Webhook events are translated into these platform events and handed to the
+// listeners registered on the Service, so that packages which depend on this
+// one (e.g. application) can react without creating an import cycle.
+
+type PushEvent struct {
+	InstallationID string
+	Owner          string
+	Repo           string
+	Branch         string
+	// CommitSHA is the head commit after the push
+	CommitSHA string
+	// ChangedFiles lists every path added, modified or removed by the pushed commits
+	ChangedFiles []string
+	// ChangedFilesIncomplete is set when the changed files of a large push
+	// could not all be listed; listeners must not filter by ChangedFiles then
+	ChangedFilesIncomplete bool
+}
+
+type WorkflowRunEvent struct {
+	InstallationID string
+	Owner          string
+	Repo           string
+	Action         string
+	RunID          int64
+	Name           string
+	DisplayTitle   string
+	Status         string
+	Conclusion     string
+	HTMLURL        string
+}
+
+type CheckRunEvent struct {
+	InstallationID string
+	Owner          string
+	Repo           string
+	Action         string
+	CheckRunID     int64
+	Name           string
+	ExternalID     string
+	OutputTitle    string
+	OutputSummary  string
+	Status         string
+	Conclusion     string
+	HTMLURL        string
+}
+
+type RepositoryEvent struct {
+	// Action is one of renamed, transferred or deleted
+	Action        string
+	RepositoryID  int64
+	Owner         string
+	Repo          string
+	PreviousOwner string
+	PreviousRepo  string
+}
+
+type PushListener interface {
+	HandlePush(ctx context.Context, event *PushEvent) error
+}
+
+type WorkflowRunListener interface {
+	HandleWorkflowRun(ctx context.Context, event *WorkflowRunEvent) error
+}
+
+type CheckRunListener interface {
+	HandleCheckRun(ctx context.Context, event *CheckRunEvent) error
+}
+
+type RepositoryListener interface {
+	HandleRepositoryEvent(ctx context.Context, event *RepositoryEvent) error
+}
+
+// OnPush registers a listener for push events. Listeners must be registered
+// before the server starts handling webhooks.
+func (s *Service) OnPush(listener PushListener) {
+	s.pushListeners = append(s.pushListeners, listener)
+}
+
+// OnWorkflowRun registers a listener for workflow_run events.
+func (s *Service) OnWorkflowRun(listener WorkflowRunListener) {
+	s.workflowRunListeners = append(s.workflowRunListeners, listener)
+}
+
+// OnCheckRun registers a listener for check_run events.
+func (s *Service) OnCheckRun(listener CheckRunListener) {
+	s.checkRunListeners = append(s.checkRunListeners, listener)
+}
+
+// OnRepositoryChange registers a listener for repository renames, transfers and deletions.
+func (s *Service) OnRepositoryChange(listener RepositoryListener) {
+	s.repositoryListeners = append(s.repositoryListeners, listener)
+}
@@ -3,6 +3,7 @@ package github
 import (
 	"io"
 	"net/http"
+	"strconv"
 
 	"github.com/team-xquare/deployment-platform/internal/pkg/middleware"
 	"github.com/team-xquare/deployment-platform/internal/pkg/utils/errors"
@@ -10,6 +11,9 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+// maxWebhookPayloadSize is the largest payload GitHub sends in a webhook.
+const maxWebhookPayloadSize = 25 << 20
+
 type Handler struct {
 	service *Service
 }
@@ -31,22 +35,43 @@ func (h *Handler) RegisterRoutes(r *gin.RouterGroup) {
 			authenticated.POST("/installations/:id/link", h.LinkInstallation)
 		}
 	}
+
+	admin := r.Group("/admin/github")
+	admin.Use(middleware.Auth(), middleware.Admin())
+	{
+		admin.GET("/webhook-deliveries", h.GetDeliveries)
+		admin.GET("/webhook-deliveries/:id", h.GetDelivery)
+		admin.POST("/webhook-deliveries/:id/replay", h.ReplayDelivery)
+	}
 }
 
 func (h *Handler) HandleWebhook(c *gin.Context) {
-	signature := c.GetHeader("X-Hub-Signature-256")
-	if signature == "" {
-		c.Error(errors.BadRequest("Missing signature"))
+	// Unsigned deliveries are recorded and rejected by the service unless
+	// insecure development mode is enabled
+	signature := WebhookSignature{
+		SHA256: c.GetHeader("X-Hub-Signature-256"),
+		SHA1:   c.GetHeader("X-Hub-Signature"),
+	}
+
+	deliveryID := c.GetHeader("X-GitHub-Delivery")
+	if deliveryID == "" {
+		c.Error(errors.BadRequest("Missing delivery ID"))
 		return
 	}
 
-	payload, err := io.ReadAll(c.Request.Body)
+	eventType := c.GetHeader("X-GitHub-Event")
+	if eventType == "" {
+		c.Error(errors.BadRequest("Missing event type"))
+		return
+	}
+
+	payload, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxWebhookPayloadSize))
 	if err != nil {
 		c.Error(errors.BadRequest("Failed to read payload"))
 		return
 	}
 
-	if err := h.service.HandleInstallationWebhook(c.Request.Context(), payload, signature); err != nil {
+	if err := h.service.HandleWebhook(c.Request.Context(), deliveryID, eventType, payload, signature); err != nil {
 		c.Error(err)
 		return
 	}
@@ -68,8 +93,9 @@ func (h *Handler) GetInstallations(c *gin.Context) {
 
 func (h *Handler) GetRepositories(c *gin.Context) {
 	installationID := c.Param("id")
+	userID := c.GetUint("user_id")
 
-	repositories, err := h.service.GetRepositories(c.Request.Context(), installationID)
+	repositories, err := h.service.GetRepositories(c.Request.Context(), userID, installationID)
 	if err != nil {
 		c.Error(err)
 		return
@@ -82,7 +108,13 @@ func (h *Handler) LinkInstallation(c *gin.Context) {
 	installationID := c.Param("id")
 	userID := c.GetUint("user_id")
 
-	err := h.service.LinkInstallationToUser(c.Request.Context(), userID, installationID)
+	var req LinkInstallationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(errors.BadRequest("Invalid request format"))
+		return
+	}
+
+	err := h.service.LinkInstallationToUser(c.Request.Context(), userID, installationID, req.GitHubToken)
 	if err != nil {
 		c.Error(err)
 		return
@@ -90,3 +122,57 @@ func (h *Handler) LinkInstallation(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"message": "Installation linked successfully"})
 }
+
+func (h *Handler) GetDeliveries(c *gin.Context) {
+	limit := 50
+	if limitStr := c.Query("limit"); limitStr != "" {
+		parsed, err := strconv.Atoi(limitStr)
+		if err != nil || parsed < 1 || parsed > 200 {
+			c.Error(errors.BadRequest("Invalid limit"))
+			return
+		}
+		limit = parsed
+	}
+
+	deliveries, err := h.service.GetDeliveries(c.Request.Context(), c.Query("status"), limit)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, deliveries)
+}
+
+func (h *Handler) GetDelivery(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.Error(errors.BadRequest("Invalid delivery ID"))
+		return
+	}
+
+	delivery, err := h.service.GetDelivery(c.Request.Context(), uint(id))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, delivery)
+}
+
+func (h *Handler) ReplayDelivery(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.Error(errors.BadRequest("Invalid delivery ID"))
+		return
+	}
+
+	delivery, err := h.service.ReplayDelivery(c.Request.Context(), uint(id))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, delivery)
+}
@@ -3,12 +3,48 @@ package github
 import "time"
 
 type Installation struct {
+	ID             uint       `json:"id" db:"id"`
+	InstallationID string     `json:"installation_id" db:"installation_id"`
+	AccountLogin   string     `json:"account_login" db:"account_login"`
+	AccountType    string     `json:"account_type" db:"account_type"`
+	Permissions    string     `json:"permissions" db:"permissions"`
+	SuspendedAt    *time.Time `json:"suspended_at" db:"suspended_at"`
+	CreatedAt      time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// InstallationRepository is a repository the installation has been granted access to.
+type InstallationRepository struct {
 	ID             uint      `json:"id" db:"id"`
 	InstallationID string    `json:"installation_id" db:"installation_id"`
-	AccountLogin   string    `json:"account_login" db:"account_login"`
-	AccountType    string    `json:"account_type" db:"account_type"`
-	Permissions    string    `json:"permissions" db:"permissions"`
+	RepositoryID   int64     `json:"repository_id" db:"repository_id"`
+	FullName       string    `json:"full_name" db:"full_name"`
+	Private        bool      `json:"private" db:"private"`
 	CreatedAt      time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt      time.Time `json:"updated_at" db:"updated_at"`
 }
 
+const (
+	DeliveryStatusProcessing = "processing"
+	DeliveryStatusProcessed  = "processed"
+	DeliveryStatusIgnored    = "ignored"
+	DeliveryStatusFailed     = "failed"
+	DeliveryStatusRejected   = "rejected"
+)
+
+// WebhookDelivery is a webhook request as received from GitHub, kept so that
+// redeliveries can be de-duplicated and failed deliveries replayed.
+type WebhookDelivery struct {
+	ID             uint       `json:"id" db:"id"`
+	DeliveryID     string     `json:"delivery_id" db:"delivery_id"`
+	EventType      string     `json:"event_type" db:"event_type"`
+	Payload        []byte     `json:"payload" db:"payload"`
+	SignatureValid bool       `json:"signature_valid" db:"signature_valid"`
+	Status         string     `json:"status" db:"status"`
+	ErrorMessage   string     `json:"error_message" db:"error_message"`
+	DurationMs     *int64     `json:"duration_ms" db:"duration_ms"`
+	Attempts       int        `json:"attempts" db:"attempts"`
+	ProcessedAt    *time.Time `json:"processed_at" db:"processed_at"`
+	CreatedAt      time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at" db:"updated_at"`
+}
@@ -1,6 +1,9 @@
 package github
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 type Repository interface {
 	SaveInstallation(ctx context.Context, installation *Installation) error
@@ -9,4 +12,21 @@ type Repository interface {
 	DeleteByInstallationID(ctx context.Context, installationID string) error
 	LinkUserToInstallation(ctx context.Context, userID uint, installationID string) error
 	IsUserLinkedToInstallation(ctx context.Context, userID uint, installationID string) (bool, error)
+	SetInstallationSuspended(ctx context.Context, installationID string, suspended bool) error
+	UpdateInstallationPermissions(ctx context.Context, installationID, permissions string) error
+	SaveInstallationRepositories(ctx context.Context, repositories []*InstallationRepository) error
+	HasInstallationRepository(ctx context.Context, installationID, fullName string) (bool, error)
+	DeleteInstallationRepositories(ctx context.Context, installationID string, repositoryIDs []int64) error
+	RenameRepository(ctx context.Context, repositoryID int64, fullName string) error
+	DeleteRepository(ctx context.Context, repositoryID int64) error
+	// CreateDelivery returns false when a delivery with the same delivery ID already exists
+	CreateDelivery(ctx context.Context, delivery *WebhookDelivery) (bool, error)
+	FindDeliveryByID(ctx context.Context, id uint) (*WebhookDelivery, error)
+	FindDeliveryByDeliveryID(ctx context.Context, deliveryID string) (*WebhookDelivery, error)
+	FindDeliveries(ctx context.Context, status string, limit int) ([]*WebhookDelivery, error)
+	// RetryDelivery marks a failed or rejected delivery, or one left processing
+	// for longer than staleAfter, as processing again. It returns false when the
+	// delivery is not retryable, e.g. because another request just took it.
+	RetryDelivery(ctx context.Context, id uint, staleAfter time.Duration) (bool, error)
+	UpdateDeliveryResult(ctx context.Context, delivery *WebhookDelivery) error
 }
@@ -3,12 +3,18 @@ package github
 import (
 	"context"
 	"crypto/hmac"
+	"crypto/sha1"
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"hash"
+	"log"
+	"net/url"
 	"strconv"
+	"strings"
 
 	"github.com/google/go-github/v66/github"
+	"github.com/team-xquare/deployment-platform/internal/app/audit"
 	"github.com/team-xquare/deployment-platform/internal/pkg/config"
 	"github.com/team-xquare/deployment-platform/internal/pkg/utils/errors"
 	"golang.org/x/oauth2"
@@ -17,6 +23,14 @@ import (
 type Service struct {
 	repo   Repository
 	client *github.Client
+	// app is nil when GITHUB_APP_ID / GITHUB_PRIVATE_KEY are not configured,
+	// in which case the GITHUB_TOKEN client is used for everything.
+	app *appAuth
+
+	pushListeners        []PushListener
+	workflowRunListeners []WorkflowRunListener
+	checkRunListeners    []CheckRunListener
+	repositoryListeners  []RepositoryListener
 }
 
 func NewService(repo Repository) *Service {
@@ -32,30 +46,66 @@ func NewService(repo Repository) *Service {
 		client = github.NewClient(nil)
 	}
 
-	return &Service{
+	baseClient := github.NewClient(nil)
+
+	// Allows pointing the clients at a stand-in API, e.g. an httptest server
+	if config.AppConfig.GitHubAPIURL != "" {
+		if baseURL, err := url.Parse(strings.TrimSuffix(config.AppConfig.GitHubAPIURL, "/") + "/"); err == nil {
+			client.BaseURL = baseURL
+			baseClient.BaseURL = baseURL
+		}
+	}
+
+	service := &Service{
 		repo:   repo,
 		client: client,
 	}
+
+	if config.AppConfig.GitHubAppID != "" && config.AppConfig.GitHubPrivateKey != "" {
+		app, err := newAppAuth(config.AppConfig.GitHubAppID, config.AppConfig.GitHubPrivateKey, baseClient)
+		if err != nil {
+			log.Printf("GitHub App authentication disabled: %v", err)
+		} else {
+			service.app = app
+		}
+	}
+
+	return service
 }
 
-func (s *Service) HandleInstallationWebhook(ctx context.Context, payload []byte, signature string) error {
-	if !s.verifySignature(payload, signature) {
-		return errors.Forbidden("Invalid webhook signature")
+// clientForInstallation returns a client acting as the given installation, or
+// the token client when GitHub App authentication is not configured.
+func (s *Service) clientForInstallation(ctx context.Context, installationID string) (*github.Client, error) {
+	if s.app == nil {
+		return s.client, nil
 	}
 
-	var event WebhookEvent
-	if err := json.Unmarshal(payload, &event); err != nil {
-		return errors.BadRequest("Invalid webhook payload")
+	id, err := strconv.ParseInt(installationID, 10, 64)
+	if err != nil {
+		return nil, errors.BadRequest("Invalid installation ID")
 	}
 
-	switch event.Action {
-	case "created":
-		return s.handleInstallationCreated(ctx, event)
-	case "deleted":
-		return s.handleInstallationDeleted(ctx, event)
-	default:
-		return nil
+	return s.app.installationClient(ctx, id)
+}
+
+// clientForRepository returns a client that can act on owner/repo. When no
+// installation ID is known it is looked up from the repository.
+func (s *Service) clientForRepository(ctx context.Context, installationID, owner, repo string) (*github.Client, error) {
+	if s.app == nil || installationID != "" {
+		return s.clientForInstallation(ctx, installationID)
+	}
+
+	appClient, err := s.app.appClient()
+	if err != nil {
+		return nil, err
+	}
+
+	installation, _, err := appClient.Apps.FindRepositoryInstallation(ctx, owner, repo)
+	if err != nil {
+		return nil, errors.Internal("Failed to find GitHub App installation for " + owner + "/" + repo + ": " + err.Error())
 	}
+
+	return s.app.installationClient(ctx, installation.GetID())
 }
 
 func (s *Service) GetUserInstallations(ctx context.Context, userID uint) ([]*InstallationResponse, error) {
@@ -67,10 +117,10 @@ func (s *Service) GetUserInstallations(ctx context.Context, userID uint) ([]*Ins
 	responses := make([]*InstallationResponse, len(installations))
 	for i, installation := range installations {
 		accountLogin := installation.AccountLogin
-		
+
 		// "unknown"이거나 기본값인 경우 실제 GitHub owner name으로 업데이트 시도
-		if accountLogin == "unknown" || accountLogin == "GitHub App Installation" || 
-		   accountLogin == "GitHub Installation "+installation.InstallationID {
+		if accountLogin == "unknown" || accountLogin == "GitHub App Installation" ||
+			accountLogin == "GitHub Installation "+installation.InstallationID {
 			// 실제 GitHub owner name 추정
 			if realLogin, err := s.guessAccountLoginFromRepos(ctx); err == nil && realLogin != "" {
 				accountLogin = realLogin
@@ -80,93 +130,77 @@ func (s *Service) GetUserInstallations(ctx context.Context, userID uint) ([]*Ins
 				accountLogin = "installation-" + installation.InstallationID
 			}
 		}
-		
+
 		responses[i] = &InstallationResponse{
 			ID:             installation.ID,
 			InstallationID: installation.InstallationID,
 			AccountLogin:   accountLogin,
 			AccountType:    installation.AccountType,
+			Suspended:      installation.SuspendedAt != nil,
 		}
 	}
 
 	return responses, nil
 }
 
-func (s *Service) handleInstallationCreated(ctx context.Context, event WebhookEvent) error {
-	installation := event.Installation
-
-	installationIDFloat, ok := installation["id"].(float64)
-	if !ok {
-		return errors.BadRequest("Invalid installation ID")
-	}
-
-	account, ok := installation["account"].(map[string]interface{})
-	if !ok {
-		return errors.BadRequest("Invalid account data")
-	}
-
-	accountLogin, ok := account["login"].(string)
-	if !ok {
-		return errors.BadRequest("Invalid account login")
+// verifySignature checks the delivery against every configured secret. Without
+// any secret, deliveries are only accepted in insecure development mode.
+func (s *Service) verifySignature(payload []byte, signature WebhookSignature) bool {
+	secrets := config.WebhookSecrets()
+	if len(secrets) == 0 {
+		return config.WebhookInsecureDev()
 	}
 
-	accountType, ok := account["type"].(string)
-	if !ok {
-		return errors.BadRequest("Invalid account type")
-	}
-
-	permissions, _ := json.Marshal(installation["permissions"])
-
-	githubInstallation := &Installation{
-		InstallationID: strconv.FormatFloat(installationIDFloat, 'f', -1, 64),
-		AccountLogin:   accountLogin,
-		AccountType:    accountType,
-		Permissions:    string(permissions),
-	}
-
-	return s.repo.SaveInstallation(ctx, githubInstallation)
-}
-
-func (s *Service) handleInstallationDeleted(ctx context.Context, event WebhookEvent) error {
-	installation := event.Installation
-
-	installationIDFloat, ok := installation["id"].(float64)
-	if !ok {
-		return errors.BadRequest("Invalid installation ID")
-	}
-
-	installationID := strconv.FormatFloat(installationIDFloat, 'f', -1, 64)
-	return s.repo.DeleteByInstallationID(ctx, installationID)
-}
+	for _, secret := range secrets {
+		if signature.SHA256 != "" {
+			expectedSignature := "sha256=" + s.computeSignature(sha256.New, payload, secret)
+			if hmac.Equal([]byte(signature.SHA256), []byte(expectedSignature)) {
+				return true
+			}
+		}
 
-func (s *Service) verifySignature(payload []byte, signature string) bool {
-	if config.AppConfig.GitHubWebhookSecret == "" {
-		return true
+		if signature.SHA1 != "" && config.WebhookAllowSHA1() {
+			expectedSignature := "sha1=" + s.computeSignature(sha1.New, payload, secret)
+			if hmac.Equal([]byte(signature.SHA1), []byte(expectedSignature)) {
+				return true
+			}
+		}
 	}
 
-	expectedSignature := "sha256=" + s.computeSignature(payload, config.AppConfig.GitHubWebhookSecret)
-	return hmac.Equal([]byte(signature), []byte(expectedSignature))
+	return false
 }
 
-func (s *Service) computeSignature(payload []byte, secret string) string {
-	h := hmac.New(sha256.New, []byte(secret))
+func (s *Service) computeSignature(hashFunc func() hash.Hash, payload []byte, secret string) string {
+	h := hmac.New(hashFunc, []byte(secret))
 	h.Write(payload)
 	return hex.EncodeToString(h.Sum(nil))
 }
 
-func (s *Service) TriggerGitHubAction(ctx context.Context, owner, repo string, payload ConfigAPIPayload) error {
+func (s *Service) TriggerGitHubAction(ctx context.Context, installationID, owner, repo string, payload ConfigAPIPayload) error {
 	// Repository dispatch event로 GitHub Actions 트리거
 	payloadBytes, err := json.Marshal(payload)
 	if err != nil {
 		return errors.Internal("Failed to marshal payload: " + err.Error())
 	}
 
+	return s.DispatchRepositoryEvent(ctx, installationID, owner, repo, ConfigAPIEventType, payloadBytes)
+}
+
+// DispatchRepositoryEvent sends a repository_dispatch event with an already
+// encoded client payload. It is used by the outbox worker. An empty
+// installationID makes it look up the installation of owner/repo.
+func (s *Service) DispatchRepositoryEvent(ctx context.Context, installationID, owner, repo, eventType string, payload json.RawMessage) error {
+	client, err := s.clientForRepository(ctx, installationID, owner, repo)
+	if err != nil {
+		return err
+	}
+
 	dispatchEvent := github.DispatchRequestOptions{
-		EventType:     "config-api",
-		ClientPayload: (*json.RawMessage)(&payloadBytes),
+		EventType:     eventType,
+		ClientPayload: &payload,
 	}
 
-	_, _, err = s.client.Repositories.Dispatch(ctx, owner, repo, dispatchEvent)
+	_, _, err = client.Repositories.Dispatch(ctx, owner, repo, dispatchEvent)
 	if err != nil {
 		return errors.Internal("Failed to trigger GitHub Action: " + err.Error())
 	}
@@ -174,18 +208,30 @@ func (s *Service) TriggerGitHubAction(ctx context.Context, owner, repo string, p
 	return nil
 }
 
-func (s *Service) GetRepositories(ctx context.Context, installationID string) ([]*GitHubRepo, error) {
+func (s *Service) GetRepositories(ctx context.Context, userID uint, installationID string) ([]*GitHubRepo, error) {
 	// GitHub App installation을 통해 접근 가능한 repositories만 가져옴
 	// Installation ID를 사용해서 해당 installation에 속한 repo들만 반환
-	
+
 	// 먼저 installation이 존재하는지 확인
 	_, err := s.repo.FindByInstallationID(ctx, installationID)
 	if err != nil {
 		return nil, err
 	}
 
-	// GitHub App installation access token을 사용해야 하지만, 
-	// 현재는 사용자 token으로 자신이 접근 가능한 repo들 중에서 필터링
+	linked, err := s.repo.IsUserLinkedToInstallation(ctx, userID, installationID)
+	if err != nil {
+		return nil, err
+	}
+	if !linked {
+		return nil, errors.Forbidden("Installation is not linked to the user")
+	}
+
+	if s.app != nil {
+		return s.listInstallationRepositories(ctx, installationID)
+	}
+
+	// GitHub App이 설정되지 않은 경우
+	// 사용자 token으로 자신이 접근 가능한 repo들 중에서 필터링
 	opts := &github.RepositoryListOptions{
 		ListOptions: github.ListOptions{PerPage: 100},
 	}
@@ -222,8 +268,74 @@ func (s *Service) GetRepositories(ctx context.Context, installationID string) ([
 	return filteredRepos, nil
 }
 
-// LinkInstallationToUser links a GitHub installation to a specific user
-func (s *Service) LinkInstallationToUser(ctx context.Context, userID uint, installationID string) error {
+// listInstallationRepositories lists the repositories granted to the installation
+// using its own access token.
+func (s *Service) listInstallationRepositories(ctx context.Context, installationID string) ([]*GitHubRepo, error) {
+	client, err := s.clientForInstallation(ctx, installationID)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := &github.ListOptions{PerPage: 100}
+
+	var repositories []*GitHubRepo
+	for {
+		list, resp, err := client.Apps.ListRepos(ctx, opts)
+		if err != nil {
+			return nil, errors.Internal("Failed to fetch repositories from GitHub: " + err.Error())
+		}
+
+		for _, repo := range list.Repositories {
+			repositories = append(repositories, &GitHubRepo{
+				ID:       int(repo.GetID()),
+				Name:     repo.GetName(),
+				FullName: repo.GetFullName(),
+				Owner: Owner{
+					Login: repo.GetOwner().GetLogin(),
+				},
+				Private: repo.GetPrivate(),
+			})
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return repositories, nil
+}
+
+// AuthorizeRepository checks that the user is linked to the installation and
+// that owner/repo is one of the repositories the installation was granted.
+func (s *Service) AuthorizeRepository(ctx context.Context, userID uint, installationID, owner, repo string) error {
+	if installationID == "" {
+		return errors.BadRequest("Installation ID is required")
+	}
+
+	linked, err := s.repo.IsUserLinkedToInstallation(ctx, userID, installationID)
+	if err != nil {
+		return err
+	}
+	if !linked {
+		return errors.Forbidden("Installation is not linked to the user")
+	}
+
+	granted, err := s.repo.HasInstallationRepository(ctx, installationID, owner+"/"+repo)
+	if err != nil {
+		return err
+	}
+	if !granted {
+		return errors.Forbidden("Repository is not accessible to the installation")
+	}
+
+	return nil
+}
+
+// LinkInstallationToUser links a GitHub installation to a specific user. With
+// GitHub App authentication the installation must be one the user can access
+// on GitHub, checked with the user's own access token.
+func (s *Service) LinkInstallationToUser(ctx context.Context, userID uint, installationID, githubToken string) error {
 	// Check if user is already linked to this installation
 	isLinked, err := s.repo.IsUserLinkedToInstallation(ctx, userID, installationID)
 	if err != nil {
@@ -235,6 +347,19 @@ func (s *Service) LinkInstallationToUser(ctx context.Context, userID uint, insta
 		return nil
 	}
 
+	if s.app != nil {
+		if githubToken == "" {
+			return errors.BadRequest("GitHub token is required")
+		}
+		accessible, err := s.userCanAccessInstallation(ctx, githubToken, installationID)
+		if err != nil {
+			return err
+		}
+		if !accessible {
+			return errors.Forbidden("Installation is not accessible to the user")
+		}
+	}
+
 	// Check if installation exists, if not try to get real data from GitHub
 	_, err = s.repo.FindByInstallationID(ctx, installationID)
 	if err != nil {
@@ -242,6 +367,10 @@ func (s *Service) LinkInstallationToUser(ctx context.Context, userID uint, insta
 		if appErr, ok := err.(*errors.AppError); ok && appErr.StatusCode == 404 {
 			// Try to get installation info from GitHub API using user token
 			installationData, fetchErr := s.fetchInstallationInfo(ctx, installationID)
+			if fetchErr != nil && s.app != nil {
+				// With app authentication GitHub is the source of truth
+				return fetchErr
+			}
 			if fetchErr != nil {
 				// If we can't get real data, try to guess from user's repos
 				if realLogin, guessErr := s.guessAccountLoginFromRepos(ctx); guessErr == nil && realLogin != "" {
@@ -260,7 +389,7 @@ func (s *Service) LinkInstallationToUser(ctx context.Context, userID uint, insta
 					}
 				}
 			}
-			
+
 			// Save the installation
 			if saveErr := s.repo.SaveInstallation(ctx, installationData); saveErr != nil {
 				return saveErr
@@ -271,18 +400,58 @@ func (s *Service) LinkInstallationToUser(ctx context.Context, userID uint, insta
 	}
 
 	// Link user to installation
-	return s.repo.LinkUserToInstallation(ctx, userID, installationID)
+	if err := s.repo.LinkUserToInstallation(ctx, userID, installationID); err != nil {
+		return err
+	}
+
+	audit.Record(ctx, audit.Event{
+		Action:       "github.link_installation",
+		ResourceType: "github_installation",
+		ResourceID:   installationID,
+	})
+
+	return nil
+}
+
+// userCanAccessInstallation reports whether the installation is among those
+// the owner of a GitHub user access token can access.
+func (s *Service) userCanAccessInstallation(ctx context.Context, githubToken, installationID string) (bool, error) {
+	client := github.NewClient(nil).WithAuthToken(githubToken)
+	client.BaseURL = s.client.BaseURL
+
+	opts := &github.ListOptions{PerPage: 100}
+	for {
+		installations, resp, err := client.Apps.ListUserInstallations(ctx, opts)
+		if err != nil {
+			return false, errors.Unauthorized("Failed to list the user's GitHub installations: " + err.Error())
+		}
+
+		for _, installation := range installations {
+			if strconv.FormatInt(installation.GetID(), 10) == installationID {
+				return true, nil
+			}
+		}
+
+		if resp.NextPage == 0 {
+			return false, nil
+		}
+		opts.Page = resp.NextPage
+	}
 }
 
 // fetchInstallationInfo tries to get installation info from GitHub API
 func (s *Service) fetchInstallationInfo(ctx context.Context, installationID string) (*Installation, error) {
-	// GitHub API로부터 실제 계정 정보를 가져오려 시도
+	if s.app != nil {
+		return s.fetchInstallationFromApp(ctx, installationID)
+	}
+
+	// GitHub App이 설정되지 않은 경우
 	// 현재는 사용자의 첫 번째 repo owner name을 사용해서 추정
 	accountLogin, err := s.guessAccountLoginFromRepos(ctx)
 	if err != nil || accountLogin == "" {
 		accountLogin = "installation-" + installationID
 	}
-	
+
 	return &Installation{
 		InstallationID: installationID,
 		AccountLogin:   accountLogin,
@@ -291,18 +460,46 @@ func (s *Service) fetchInstallationInfo(ctx context.Context, installationID stri
 	}, nil
 }
 
+// fetchInstallationFromApp reads the installation's account and permissions
+// from the GitHub App API.
+func (s *Service) fetchInstallationFromApp(ctx context.Context, installationID string) (*Installation, error) {
+	id, err := strconv.ParseInt(installationID, 10, 64)
+	if err != nil {
+		return nil, errors.BadRequest("Invalid installation ID")
+	}
+
+	client, err := s.app.appClient()
+	if err != nil {
+		return nil, err
+	}
+
+	installation, _, err := client.Apps.GetInstallation(ctx, id)
+	if err != nil {
+		return nil, errors.NotFound("GitHub installation not found")
+	}
+
+	permissions, _ := json.Marshal(installation.GetPermissions())
+
+	return &Installation{
+		InstallationID: installationID,
+		AccountLogin:   installation.GetAccount().GetLogin(),
+		AccountType:    installation.GetAccount().GetType(),
+		Permissions:    string(permissions),
+	}, nil
+}
+
 // guessAccountLoginFromRepos tries to guess the account login from user's repositories
 func (s *Service) guessAccountLoginFromRepos(ctx context.Context) (string, error) {
 	// 사용자의 repository들을 가져와서 가장 많이 나오는 owner name 추정
 	opts := &github.RepositoryListOptions{
 		ListOptions: github.ListOptions{PerPage: 10},
 	}
-	
+
 	repos, _, err := s.client.Repositories.List(ctx, "", opts)
 	if err != nil {
 		return "", err
 	}
-	
+
 	// owner 이름들을 count해서 가장 많이 나오는 것 선택
 	ownerCount := make(map[string]int)
 	for _, repo := range repos {
@@ -311,7 +508,7 @@ func (s *Service) guessAccountLoginFromRepos(ctx context.Context) (string, error
 			ownerCount[ownerName]++
 		}
 	}
-	
+
 	// 가장 많이 나오는 owner name 반환
 	maxCount := 0
 	mostFrequentOwner := ""
@@ -321,7 +518,7 @@ func (s *Service) guessAccountLoginFromRepos(ctx context.Context) (string, error
 			mostFrequentOwner = owner
 		}
 	}
-	
+
 	return mostFrequentOwner, nil
 }
 
@@ -332,8 +529,7 @@ func (s *Service) updateInstallationLogin(ctx context.Context, installationID, a
 	if err != nil {
 		return // 에러 무시 (비동기 업데이트)
 	}
-	
+
 	installation.AccountLogin = accountLogin
 	s.repo.SaveInstallation(ctx, installation) // 에러 무시 (비동기)
 }
-
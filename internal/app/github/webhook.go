@@ -0,0 +1,493 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v66/github"
+	"github.com/team-xquare/deployment-platform/internal/app/audit"
+	"github.com/team-xquare/deployment-platform/internal/pkg/utils/errors"
+)
+
+// handledEvents are the X-GitHub-Event types the platform reacts to. Other
+// events are acknowledged and ignored.
+var handledEvents = map[string]bool{
+	"installation":              true,
+	"installation_repositories": true,
+	"push":                      true,
+	"workflow_run":              true,
+	"check_run":                 true,
+	"repository":                true,
+}
+
+// GitHub lists at most maxPushCommits commits in a push payload and at most
+// maxCompareFiles files in a comparison; longer lists are truncated.
+const (
+	maxPushCommits  = 20
+	maxCompareFiles = 300
+)
+
+// staleProcessingAfter is how long a delivery may stay processing before a
+// redelivery assumes the server handling it went away and processes it again.
+const staleProcessingAfter = 5 * time.Minute
+
+// HandleWebhook records a webhook delivery and processes it. Redeliveries of a
+// delivery that was already handled are acknowledged without processing it
+// again; only failed or rejected deliveries, and deliveries stuck processing
+// for longer than staleProcessingAfter, are retried. The delivery ID is not
+// covered by the signature, so a retry must carry the stored event type and
+// payload: an unsigned request cannot get its payload processed later by
+// reusing its delivery ID for a signed one.
+func (s *Service) HandleWebhook(ctx context.Context, deliveryID, eventType string, payload []byte, signature WebhookSignature) error {
+	delivery := &WebhookDelivery{
+		DeliveryID:     deliveryID,
+		EventType:      eventType,
+		Payload:        payload,
+		SignatureValid: s.verifySignature(payload, signature),
+		Status:         DeliveryStatusProcessing,
+	}
+
+	created, err := s.repo.CreateDelivery(ctx, delivery)
+	if err != nil {
+		return err
+	}
+
+	if !created {
+		existing, err := s.repo.FindDeliveryByDeliveryID(ctx, deliveryID)
+		if err != nil {
+			return err
+		}
+		if !delivery.SignatureValid {
+			return errors.Forbidden("Invalid webhook signature")
+		}
+		if existing.EventType != eventType || !bytes.Equal(existing.Payload, payload) {
+			return errors.BadRequest("Delivery ID was already used for another payload")
+		}
+		retry, err := s.repo.RetryDelivery(ctx, existing.ID, staleProcessingAfter)
+		if err != nil {
+			return err
+		}
+		if !retry {
+			return nil
+		}
+		existing.SignatureValid = true
+		delivery = existing
+	}
+
+	if !delivery.SignatureValid {
+		delivery.Status = DeliveryStatusRejected
+		delivery.ErrorMessage = "Invalid webhook signature"
+		if err := s.repo.UpdateDeliveryResult(ctx, delivery); err != nil {
+			return err
+		}
+		return errors.Forbidden("Invalid webhook signature")
+	}
+
+	return s.processDelivery(ctx, delivery)
+}
+
+// GetDeliveries lists stored deliveries, newest first, without their payloads.
+func (s *Service) GetDeliveries(ctx context.Context, status string, limit int) ([]*WebhookDeliveryResponse, error) {
+	deliveries, err := s.repo.FindDeliveries(ctx, status, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*WebhookDeliveryResponse, len(deliveries))
+	for i, delivery := range deliveries {
+		responses[i] = toDeliveryResponse(delivery, false)
+	}
+
+	return responses, nil
+}
+
+func (s *Service) GetDelivery(ctx context.Context, id uint) (*WebhookDeliveryResponse, error) {
+	delivery, err := s.repo.FindDeliveryByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return toDeliveryResponse(delivery, true), nil
+}
+
+// ReplayDelivery processes a stored delivery again, regardless of its previous
+// result. The outcome is recorded on the delivery rather than returned as an error.
+func (s *Service) ReplayDelivery(ctx context.Context, id uint) (*WebhookDeliveryResponse, error) {
+	delivery, err := s.repo.FindDeliveryByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !delivery.SignatureValid {
+		return nil, errors.BadRequest("Deliveries with an invalid signature cannot be replayed")
+	}
+
+	s.processDelivery(ctx, delivery)
+
+	audit.Record(ctx, audit.Event{
+		Action:       "webhook.replay",
+		ResourceType: "webhook_delivery",
+		ResourceID:   audit.ID(delivery.ID),
+		After:        toDeliveryResponse(delivery, false),
+	})
+
+	return toDeliveryResponse(delivery, true), nil
+}
+
+// processDelivery routes the delivery and records the result, returning the
+// processing error so the webhook response reflects it.
+func (s *Service) processDelivery(ctx context.Context, delivery *WebhookDelivery) error {
+	start := time.Now()
+	handled, err := s.routeEvent(ctx, delivery.EventType, delivery.Payload)
+	durationMs := time.Since(start).Milliseconds()
+
+	delivery.Attempts++
+	delivery.DurationMs = &durationMs
+	delivery.ErrorMessage = ""
+	switch {
+	case err != nil:
+		delivery.Status = DeliveryStatusFailed
+		delivery.ErrorMessage = err.Error()
+	case !handled:
+		delivery.Status = DeliveryStatusIgnored
+	default:
+		delivery.Status = DeliveryStatusProcessed
+	}
+
+	if updateErr := s.repo.UpdateDeliveryResult(ctx, delivery); updateErr != nil {
+		log.Printf("Failed to record result of webhook delivery %s: %v", delivery.DeliveryID, updateErr)
+	}
+
+	return err
+}
+
+// routeEvent dispatches the payload by its X-GitHub-Event type. It reports
+// false for event types the platform does not react to.
+func (s *Service) routeEvent(ctx context.Context, eventType string, payload []byte) (bool, error) {
+	if !handledEvents[eventType] {
+		return false, nil
+	}
+
+	event, err := github.ParseWebHook(eventType, payload)
+	if err != nil {
+		return true, errors.BadRequest("Invalid webhook payload")
+	}
+
+	switch e := event.(type) {
+	case *github.InstallationEvent:
+		return true, s.handleInstallationEvent(ctx, e)
+	case *github.InstallationRepositoriesEvent:
+		return true, s.handleInstallationRepositoriesEvent(ctx, e)
+	case *github.PushEvent:
+		return true, s.handlePushEvent(ctx, e)
+	case *github.WorkflowRunEvent:
+		return true, s.handleWorkflowRunEvent(ctx, e)
+	case *github.CheckRunEvent:
+		return true, s.handleCheckRunEvent(ctx, e)
+	case *github.RepositoryEvent:
+		return true, s.handleRepositoryEvent(ctx, e)
+	default:
+		return false, nil
+	}
+}
+
+func (s *Service) handleInstallationEvent(ctx context.Context, event *github.InstallationEvent) error {
+	installation := event.GetInstallation()
+	if installation.GetID() == 0 {
+		return errors.BadRequest("Invalid installation ID")
+	}
+	installationID := strconv.FormatInt(installation.GetID(), 10)
+
+	switch event.GetAction() {
+	case "created":
+		account := installation.GetAccount()
+		if account.GetLogin() == "" {
+			return errors.BadRequest("Invalid account login")
+		}
+
+		permissions, _ := json.Marshal(installation.GetPermissions())
+		githubInstallation := &Installation{
+			InstallationID: installationID,
+			AccountLogin:   account.GetLogin(),
+			AccountType:    account.GetType(),
+			Permissions:    string(permissions),
+		}
+		if err := s.repo.SaveInstallation(ctx, githubInstallation); err != nil {
+			return err
+		}
+
+		return s.repo.SaveInstallationRepositories(ctx, toInstallationRepositories(installationID, event.Repositories))
+	case "deleted":
+		s.forgetInstallationToken(installation.GetID())
+		return s.repo.DeleteByInstallationID(ctx, installationID)
+	case "suspend":
+		s.forgetInstallationToken(installation.GetID())
+		return s.repo.SetInstallationSuspended(ctx, installationID, true)
+	case "unsuspend":
+		return s.repo.SetInstallationSuspended(ctx, installationID, false)
+	case "new_permissions_accepted":
+		// Tokens carry the permissions they were issued with
+		s.forgetInstallationToken(installation.GetID())
+		permissions, _ := json.Marshal(installation.GetPermissions())
+		return s.repo.UpdateInstallationPermissions(ctx, installationID, string(permissions))
+	default:
+		return nil
+	}
+}
+
+func (s *Service) handleInstallationRepositoriesEvent(ctx context.Context, event *github.InstallationRepositoriesEvent) error {
+	if event.GetInstallation().GetID() == 0 {
+		return errors.BadRequest("Invalid installation ID")
+	}
+	installationID := strconv.FormatInt(event.GetInstallation().GetID(), 10)
+
+	switch event.GetAction() {
+	case "added":
+		return s.repo.SaveInstallationRepositories(ctx, toInstallationRepositories(installationID, event.RepositoriesAdded))
+	case "removed":
+		repositoryIDs := make([]int64, len(event.RepositoriesRemoved))
+		for i, repo := range event.RepositoriesRemoved {
+			repositoryIDs[i] = repo.GetID()
+		}
+		return s.repo.DeleteInstallationRepositories(ctx, installationID, repositoryIDs)
+	default:
+		return nil
+	}
+}
+
+func (s *Service) handlePushEvent(ctx context.Context, event *github.PushEvent) error {
+	// Only pushes to branches can trigger deployments; tags and branch deletions are ignored
+	if event.GetDeleted() || !strings.HasPrefix(event.GetRef(), "refs/heads/") {
+		return nil
+	}
+
+	owner := event.GetRepo().GetOwner().GetLogin()
+	if owner == "" {
+		owner = event.GetRepo().GetOwner().GetName()
+	}
+
+	push := &PushEvent{
+		InstallationID: installationIDString(event.GetInstallation()),
+		Owner:          owner,
+		Repo:           event.GetRepo().GetName(),
+		Branch:         strings.TrimPrefix(event.GetRef(), "refs/heads/"),
+		CommitSHA:      event.GetAfter(),
+	}
+
+	if len(event.Commits) < maxPushCommits {
+		var files []string
+		for _, commit := range event.Commits {
+			files = append(files, commit.Added...)
+			files = append(files, commit.Modified...)
+			files = append(files, commit.Removed...)
+		}
+		push.ChangedFiles = uniqueFiles(files)
+	} else {
+		// The payload may have left commits out; list the files of the whole push instead
+		push.ChangedFiles, push.ChangedFilesIncomplete = s.compareFiles(ctx, push, event.GetBefore())
+	}
+
+	for _, listener := range s.pushListeners {
+		if err := listener.HandlePush(ctx, push); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// compareFiles lists the files changed between base and the pushed commit with
+// the compare API. It reports true when they cannot all be listed, e.g. for a
+// new branch, which has no base to compare with.
+func (s *Service) compareFiles(ctx context.Context, push *PushEvent, base string) ([]string, bool) {
+	if base == "" || strings.Trim(base, "0") == "" {
+		return nil, true
+	}
+
+	client, err := s.clientForRepository(ctx, push.InstallationID, push.Owner, push.Repo)
+	if err != nil {
+		log.Printf("Failed to compare push to %s/%s: %v", push.Owner, push.Repo, err)
+		return nil, true
+	}
+
+	comparison, _, err := client.Repositories.CompareCommits(ctx, push.Owner, push.Repo, base, push.CommitSHA, nil)
+	if err != nil {
+		log.Printf("Failed to compare push to %s/%s: %v", push.Owner, push.Repo, err)
+		return nil, true
+	}
+	if len(comparison.Files) >= maxCompareFiles {
+		return nil, true
+	}
+
+	var files []string
+	for _, file := range comparison.Files {
+		files = append(files, file.GetFilename())
+		// A rename also changes the path it was moved from
+		if previous := file.GetPreviousFilename(); previous != "" {
+			files = append(files, previous)
+		}
+	}
+
+	return uniqueFiles(files), false
+}
+
+// uniqueFiles returns files without duplicates, in their original order.
+func uniqueFiles(files []string) []string {
+	seen := make(map[string]bool)
+	var unique []string
+	for _, file := range files {
+		if !seen[file] {
+			seen[file] = true
+			unique = append(unique, file)
+		}
+	}
+	return unique
+}
+
+func (s *Service) handleWorkflowRunEvent(ctx context.Context, event *github.WorkflowRunEvent) error {
+	run := event.GetWorkflowRun()
+	workflowRun := &WorkflowRunEvent{
+		InstallationID: installationIDString(event.GetInstallation()),
+		Owner:          event.GetRepo().GetOwner().GetLogin(),
+		Repo:           event.GetRepo().GetName(),
+		Action:         event.GetAction(),
+		RunID:          run.GetID(),
+		Name:           run.GetName(),
+		DisplayTitle:   run.GetDisplayTitle(),
+		Status:         run.GetStatus(),
+		Conclusion:     run.GetConclusion(),
+		HTMLURL:        run.GetHTMLURL(),
+	}
+
+	for _, listener := range s.workflowRunListeners {
+		if err := listener.HandleWorkflowRun(ctx, workflowRun); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) handleCheckRunEvent(ctx context.Context, event *github.CheckRunEvent) error {
+	run := event.GetCheckRun()
+	checkRun := &CheckRunEvent{
+		InstallationID: installationIDString(event.GetInstallation()),
+		Owner:          event.GetRepo().GetOwner().GetLogin(),
+		Repo:           event.GetRepo().GetName(),
+		Action:         event.GetAction(),
+		CheckRunID:     run.GetID(),
+		Name:           run.GetName(),
+		ExternalID:     run.GetExternalID(),
+		OutputTitle:    run.GetOutput().GetTitle(),
+		OutputSummary:  run.GetOutput().GetSummary(),
+		Status:         run.GetStatus(),
+		Conclusion:     run.GetConclusion(),
+		HTMLURL:        run.GetHTMLURL(),
+	}
+
+	for _, listener := range s.checkRunListeners {
+		if err := listener.HandleCheckRun(ctx, checkRun); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) handleRepositoryEvent(ctx context.Context, event *github.RepositoryEvent) error {
+	repo := event.GetRepo()
+	repositoryEvent := &RepositoryEvent{
+		Action:        event.GetAction(),
+		RepositoryID:  repo.GetID(),
+		Owner:         repo.GetOwner().GetLogin(),
+		Repo:          repo.GetName(),
+		PreviousOwner: repo.GetOwner().GetLogin(),
+		PreviousRepo:  repo.GetName(),
+	}
+
+	switch event.GetAction() {
+	case "renamed":
+		if from := event.GetChanges().GetRepo().GetName().GetFrom(); from != "" {
+			repositoryEvent.PreviousRepo = from
+		}
+		if err := s.repo.RenameRepository(ctx, repo.GetID(), repo.GetFullName()); err != nil {
+			return err
+		}
+	case "transferred":
+		previousOwner := event.GetChanges().GetOwner().GetOwnerInfo()
+		if login := previousOwner.GetUser().GetLogin(); login != "" {
+			repositoryEvent.PreviousOwner = login
+		} else if login := previousOwner.GetOrg().GetLogin(); login != "" {
+			repositoryEvent.PreviousOwner = login
+		}
+		if err := s.repo.RenameRepository(ctx, repo.GetID(), repo.GetFullName()); err != nil {
+			return err
+		}
+	case "deleted":
+		if err := s.repo.DeleteRepository(ctx, repo.GetID()); err != nil {
+			return err
+		}
+	default:
+		return nil
+	}
+
+	for _, listener := range s.repositoryListeners {
+		if err := listener.HandleRepositoryEvent(ctx, repositoryEvent); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) forgetInstallationToken(installationID int64) {
+	if s.app != nil {
+		s.app.forget(installationID)
+	}
+}
+
+func toInstallationRepositories(installationID string, repos []*github.Repository) []*InstallationRepository {
+	repositories := make([]*InstallationRepository, len(repos))
+	for i, repo := range repos {
+		repositories[i] = &InstallationRepository{
+			InstallationID: installationID,
+			RepositoryID:   repo.GetID(),
+			FullName:       repo.GetFullName(),
+			Private:        repo.GetPrivate(),
+		}
+	}
+	return repositories
+}
+
+func installationIDString(installation *github.Installation) string {
+	if installation.GetID() == 0 {
+		return ""
+	}
+	return strconv.FormatInt(installation.GetID(), 10)
+}
+
+func toDeliveryResponse(delivery *WebhookDelivery, includePayload bool) *WebhookDeliveryResponse {
+	response := &WebhookDeliveryResponse{
+		ID:             delivery.ID,
+		DeliveryID:     delivery.DeliveryID,
+		EventType:      delivery.EventType,
+		SignatureValid: delivery.SignatureValid,
+		Status:         delivery.Status,
+		ErrorMessage:   delivery.ErrorMessage,
+		DurationMs:     delivery.DurationMs,
+		Attempts:       delivery.Attempts,
+		ProcessedAt:    delivery.ProcessedAt,
+		CreatedAt:      delivery.CreatedAt,
+	}
+
+	if includePayload && json.Valid(delivery.Payload) {
+		response.Payload = delivery.Payload
+	}
+
+	return response
+}
@@ -0,0 +1,26 @@
+package gitops
+
+import "time"
+
+// SaveTargetRequest defaults EventType to config-api. Project targets need an
+// InstallationID linked to the caller that was granted the repository; when a
+// platform target omits it the installation is looked up from the repository
+// on dispatch.
+type SaveTargetRequest struct {
+	Owner          string `json:"owner" binding:"required"`
+	Repo           string `json:"repo" binding:"required"`
+	EventType      string `json:"event_type"`
+	InstallationID string `json:"installation_id"`
+}
+
+type TargetResponse struct {
+	ID             uint      `json:"id"`
+	ProjectID      *uint     `json:"project_id,omitempty"`
+	ResourceType   string    `json:"resource_type"`
+	Owner          string    `json:"owner"`
+	Repo           string    `json:"repo"`
+	EventType      string    `json:"event_type"`
+	InstallationID string    `json:"installation_id,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
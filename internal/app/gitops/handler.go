@@ -0,0 +1,130 @@
+package gitops
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/team-xquare/deployment-platform/internal/pkg/middleware"
+	"github.com/team-xquare/deployment-platform/internal/pkg/utils/errors"
+)
+
+type Handler struct {
+	service *Service
+}
+
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+func (h *Handler) RegisterRoutes(r *gin.RouterGroup) {
+	projects := r.Group("/projects/:id/gitops-targets")
+	projects.Use(middleware.Auth())
+	{
+		projects.GET("", h.GetProjectTargets)
+		projects.PUT("/:resourceType", h.SaveProjectTarget)
+		projects.DELETE("/:resourceType", h.DeleteProjectTarget)
+	}
+
+	admin := r.Group("/admin/gitops-targets")
+	admin.Use(middleware.Auth(), middleware.Admin())
+	{
+		admin.GET("", h.GetPlatformTargets)
+		admin.PUT("/:resourceType", h.SavePlatformTarget)
+		admin.DELETE("/:resourceType", h.DeletePlatformTarget)
+	}
+}
+
+func (h *Handler) GetProjectTargets(c *gin.Context) {
+	projectIDStr := c.Param("id")
+	projectID, err := strconv.ParseUint(projectIDStr, 10, 32)
+	if err != nil {
+		c.Error(errors.BadRequest("Invalid project ID"))
+		return
+	}
+
+	userID := c.GetUint("user_id")
+	targets, err := h.service.GetProjectTargets(c.Request.Context(), userID, uint(projectID))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, targets)
+}
+
+func (h *Handler) SaveProjectTarget(c *gin.Context) {
+	projectIDStr := c.Param("id")
+	projectID, err := strconv.ParseUint(projectIDStr, 10, 32)
+	if err != nil {
+		c.Error(errors.BadRequest("Invalid project ID"))
+		return
+	}
+
+	var req SaveTargetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(errors.BadRequest("Invalid request format"))
+		return
+	}
+
+	userID := c.GetUint("user_id")
+	target, err := h.service.SaveProjectTarget(c.Request.Context(), userID, uint(projectID), c.Param("resourceType"), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, target)
+}
+
+func (h *Handler) DeleteProjectTarget(c *gin.Context) {
+	projectIDStr := c.Param("id")
+	projectID, err := strconv.ParseUint(projectIDStr, 10, 32)
+	if err != nil {
+		c.Error(errors.BadRequest("Invalid project ID"))
+		return
+	}
+
+	userID := c.GetUint("user_id")
+	if err := h.service.DeleteProjectTarget(c.Request.Context(), userID, uint(projectID), c.Param("resourceType")); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "GitOps target deleted successfully"})
+}
+
+func (h *Handler) GetPlatformTargets(c *gin.Context) {
+	targets, err := h.service.GetPlatformTargets(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, targets)
+}
+
+func (h *Handler) SavePlatformTarget(c *gin.Context) {
+	var req SaveTargetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(errors.BadRequest("Invalid request format"))
+		return
+	}
+
+	target, err := h.service.SavePlatformTarget(c.Request.Context(), c.Param("resourceType"), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, target)
+}
+
+func (h *Handler) DeletePlatformTarget(c *gin.Context) {
+	if err := h.service.DeletePlatformTarget(c.Request.Context(), c.Param("resourceType")); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "GitOps target deleted successfully"})
+}
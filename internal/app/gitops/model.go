@@ -0,0 +1,27 @@
+package gitops
+
+import "time"
+
+// Resource types a target can be configured for.
+const (
+	ResourceApplication = "application"
+	ResourceAddon       = "addon"
+)
+
+func IsValidResourceType(resourceType string) bool {
+	return resourceType == ResourceApplication || resourceType == ResourceAddon
+}
+
+// Target is the infrastructure repository that receives the config-api
+// dispatches for one resource type. ProjectID is nil for platform-wide targets.
+type Target struct {
+	ID             uint      `json:"id" db:"id"`
+	ProjectID      *uint     `json:"project_id" db:"project_id"`
+	ResourceType   string    `json:"resource_type" db:"resource_type"`
+	Owner          string    `json:"owner" db:"owner"`
+	Repo           string    `json:"repo" db:"repo"`
+	EventType      string    `json:"event_type" db:"event_type"`
+	InstallationID string    `json:"installation_id" db:"installation_id"`
+	CreatedAt      time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at" db:"updated_at"`
+}
@@ -0,0 +1,15 @@
+package gitops
+
+import "context"
+
+type Repository interface {
+	// FindPlatformTarget and FindProjectTarget return nil, nil when no target is configured
+	FindPlatformTarget(ctx context.Context, resourceType string) (*Target, error)
+	FindProjectTarget(ctx context.Context, projectID uint, resourceType string) (*Target, error)
+	FindPlatformTargets(ctx context.Context) ([]*Target, error)
+	FindProjectTargets(ctx context.Context, projectID uint) ([]*Target, error)
+	SavePlatformTarget(ctx context.Context, target *Target) error
+	SaveProjectTarget(ctx context.Context, target *Target) error
+	DeletePlatformTarget(ctx context.Context, resourceType string) error
+	DeleteProjectTarget(ctx context.Context, projectID uint, resourceType string) error
+}
@@ -0,0 +1,225 @@
+package gitops
+
+import (
+	"context"
+
+	"github.com/team-xquare/deployment-platform/internal/app/audit"
+	"github.com/team-xquare/deployment-platform/internal/app/github"
+	"github.com/team-xquare/deployment-platform/internal/app/member"
+	"github.com/team-xquare/deployment-platform/internal/pkg/utils/errors"
+)
+
+type Service struct {
+	repo      Repository
+	memberSvc *member.Service
+	githubSvc *github.Service
+}
+
+func NewService(repo Repository, memberSvc *member.Service, githubSvc *github.Service) *Service {
+	return &Service{repo: repo, memberSvc: memberSvc, githubSvc: githubSvc}
+}
+
+// Resolve returns the target for a resource type in a project: the project's
+// own target if one is set, otherwise the platform target. It returns nil
+// when neither is configured.
+func (s *Service) Resolve(ctx context.Context, projectID uint, resourceType string) (*Target, error) {
+	target, err := s.repo.FindProjectTarget(ctx, projectID, resourceType)
+	if err != nil {
+		return nil, err
+	}
+	if target != nil {
+		return target, nil
+	}
+
+	return s.repo.FindPlatformTarget(ctx, resourceType)
+}
+
+func (s *Service) GetPlatformTargets(ctx context.Context) ([]*TargetResponse, error) {
+	targets, err := s.repo.FindPlatformTargets(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return toResponses(targets), nil
+}
+
+func (s *Service) SavePlatformTarget(ctx context.Context, resourceType string, req SaveTargetRequest) (*TargetResponse, error) {
+	target, err := newTarget(nil, resourceType, req)
+	if err != nil {
+		return nil, err
+	}
+
+	previous, err := s.repo.FindPlatformTarget(ctx, resourceType)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.SavePlatformTarget(ctx, target); err != nil {
+		return nil, err
+	}
+
+	response := toResponse(target)
+	audit.Record(ctx, audit.Event{
+		Action:       "gitops_target.save",
+		ResourceType: "gitops_target",
+		ResourceID:   resourceType,
+		Before:       auditSnapshot(previous),
+		After:        response,
+	})
+
+	return response, nil
+}
+
+func (s *Service) DeletePlatformTarget(ctx context.Context, resourceType string) error {
+	if !IsValidResourceType(resourceType) {
+		return errors.BadRequest("Invalid resource type")
+	}
+
+	previous, err := s.repo.FindPlatformTarget(ctx, resourceType)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.DeletePlatformTarget(ctx, resourceType); err != nil {
+		return err
+	}
+
+	audit.Record(ctx, audit.Event{
+		Action:       "gitops_target.delete",
+		ResourceType: "gitops_target",
+		ResourceID:   resourceType,
+		Before:       auditSnapshot(previous),
+	})
+
+	return nil
+}
+
+func (s *Service) GetProjectTargets(ctx context.Context, userID, projectID uint) ([]*TargetResponse, error) {
+	if _, err := s.memberSvc.Authorize(ctx, userID, projectID, member.RoleViewer); err != nil {
+		return nil, err
+	}
+
+	targets, err := s.repo.FindProjectTargets(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	return toResponses(targets), nil
+}
+
+func (s *Service) SaveProjectTarget(ctx context.Context, userID, projectID uint, resourceType string, req SaveTargetRequest) (*TargetResponse, error) {
+	if _, err := s.memberSvc.Authorize(ctx, userID, projectID, member.RoleMaintainer); err != nil {
+		return nil, err
+	}
+
+	target, err := newTarget(&projectID, resourceType, req)
+	if err != nil {
+		return nil, err
+	}
+	// Project maintainers may only dispatch to repositories they can reach
+	// through one of their own installations
+	if err := s.githubSvc.AuthorizeRepository(ctx, userID, target.InstallationID, target.Owner, target.Repo); err != nil {
+		return nil, err
+	}
+
+	previous, err := s.repo.FindProjectTarget(ctx, projectID, resourceType)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.SaveProjectTarget(ctx, target); err != nil {
+		return nil, err
+	}
+
+	response := toResponse(target)
+	audit.Record(ctx, audit.Event{
+		Action:       "gitops_target.save",
+		ResourceType: "gitops_target",
+		ResourceID:   resourceType,
+		ProjectID:    &projectID,
+		Before:       auditSnapshot(previous),
+		After:        response,
+	})
+
+	return response, nil
+}
+
+func (s *Service) DeleteProjectTarget(ctx context.Context, userID, projectID uint, resourceType string) error {
+	if _, err := s.memberSvc.Authorize(ctx, userID, projectID, member.RoleMaintainer); err != nil {
+		return err
+	}
+
+	if !IsValidResourceType(resourceType) {
+		return errors.BadRequest("Invalid resource type")
+	}
+
+	previous, err := s.repo.FindProjectTarget(ctx, projectID, resourceType)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.DeleteProjectTarget(ctx, projectID, resourceType); err != nil {
+		return err
+	}
+
+	audit.Record(ctx, audit.Event{
+		Action:       "gitops_target.delete",
+		ResourceType: "gitops_target",
+		ResourceID:   resourceType,
+		ProjectID:    &projectID,
+		Before:       auditSnapshot(previous),
+	})
+
+	return nil
+}
+
+// auditSnapshot returns the target as recorded in the audit log, or nil when
+// none was configured.
+func auditSnapshot(target *Target) interface{} {
+	if target == nil {
+		return nil
+	}
+	return toResponse(target)
+}
+
+func newTarget(projectID *uint, resourceType string, req SaveTargetRequest) (*Target, error) {
+	if !IsValidResourceType(resourceType) {
+		return nil, errors.BadRequest("Invalid resource type")
+	}
+
+	eventType := req.EventType
+	if eventType == "" {
+		eventType = github.ConfigAPIEventType
+	}
+	// GitHub rejects repository_dispatch event types longer than 100 characters
+	if len(eventType) > 100 {
+		return nil, errors.BadRequest("Event type must be at most 100 characters")
+	}
+
+	return &Target{
+		ProjectID:      projectID,
+		ResourceType:   resourceType,
+		Owner:          req.Owner,
+		Repo:           req.Repo,
+		EventType:      eventType,
+		InstallationID: req.InstallationID,
+	}, nil
+}
+
+func toResponses(targets []*Target) []*TargetResponse {
+	responses := make([]*TargetResponse, len(targets))
+	for i, target := range targets {
+		responses[i] = toResponse(target)
+	}
+	return responses
+}
+
+func toResponse(target *Target) *TargetResponse {
+	return &TargetResponse{
+		ID:             target.ID,
+		ProjectID:      target.ProjectID,
+		ResourceType:   target.ResourceType,
+		Owner:          target.Owner,
+		Repo:           target.Repo,
+		EventType:      target.EventType,
+		InstallationID: target.InstallationID,
+		CreatedAt:      target.CreatedAt,
+		UpdatedAt:      target.UpdatedAt,
+	}
+}
@@ -0,0 +1,30 @@
+package member
+
+import (
+	"time"
+
+	"github.com/team-xquare/deployment-platform/internal/pkg/utils/pagination"
+)
+
+type InviteMemberRequest struct {
+	Email string `json:"email" binding:"required,email"`
+	Role  Role   `json:"role" binding:"required"`
+}
+
+type UpdateMemberRoleRequest struct {
+	Role Role `json:"role" binding:"required"`
+}
+
+type MemberResponse struct {
+	UserID    uint      `json:"user_id"`
+	Email     string    `json:"email"`
+	Name      string    `json:"name"`
+	Role      Role      `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type MemberListResponse struct {
+	Members []*MemberResponse `json:"members"`
+	pagination.Page
+}
@@ -0,0 +1,134 @@
+package member
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/team-xquare/deployment-platform/internal/pkg/middleware"
+	"github.com/team-xquare/deployment-platform/internal/pkg/utils/errors"
+	"github.com/team-xquare/deployment-platform/internal/pkg/utils/pagination"
+)
+
+type Handler struct {
+	service *Service
+}
+
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+func (h *Handler) RegisterRoutes(r *gin.RouterGroup) {
+	members := r.Group("/projects/:id/members")
+	members.Use(middleware.Auth())
+	{
+		members.GET("", h.GetMembers)
+		members.POST("", h.InviteMember)
+		members.PUT("/:userId", h.UpdateMemberRole)
+		members.DELETE("/:userId", h.RemoveMember)
+	}
+}
+
+func (h *Handler) GetMembers(c *gin.Context) {
+	projectIDStr := c.Param("id")
+	projectID, err := strconv.ParseUint(projectIDStr, 10, 32)
+	if err != nil {
+		c.Error(errors.BadRequest("Invalid project ID"))
+		return
+	}
+
+	page, err := pagination.Parse(c, pagination.SortCreated, pagination.SortName, pagination.SortCreated)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	filter := ListFilter{Name: c.Query("name"), Role: Role(c.Query("role")), Page: page}
+
+	userID := c.GetUint("user_id")
+	members, err := h.service.GetMembers(c.Request.Context(), userID, uint(projectID), filter)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, members)
+}
+
+func (h *Handler) InviteMember(c *gin.Context) {
+	projectIDStr := c.Param("id")
+	projectID, err := strconv.ParseUint(projectIDStr, 10, 32)
+	if err != nil {
+		c.Error(errors.BadRequest("Invalid project ID"))
+		return
+	}
+
+	var req InviteMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(errors.BadRequest("Invalid request format"))
+		return
+	}
+
+	userID := c.GetUint("user_id")
+	member, err := h.service.InviteMember(c.Request.Context(), userID, uint(projectID), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, member)
+}
+
+func (h *Handler) UpdateMemberRole(c *gin.Context) {
+	projectIDStr := c.Param("id")
+	projectID, err := strconv.ParseUint(projectIDStr, 10, 32)
+	if err != nil {
+		c.Error(errors.BadRequest("Invalid project ID"))
+		return
+	}
+
+	memberUserIDStr := c.Param("userId")
+	memberUserID, err := strconv.ParseUint(memberUserIDStr, 10, 32)
+	if err != nil {
+		c.Error(errors.BadRequest("Invalid user ID"))
+		return
+	}
+
+	var req UpdateMemberRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(errors.BadRequest("Invalid request format"))
+		return
+	}
+
+	userID := c.GetUint("user_id")
+	member, err := h.service.UpdateMemberRole(c.Request.Context(), userID, uint(projectID), uint(memberUserID), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, member)
+}
+
+func (h *Handler) RemoveMember(c *gin.Context) {
+	projectIDStr := c.Param("id")
+	projectID, err := strconv.ParseUint(projectIDStr, 10, 32)
+	if err != nil {
+		c.Error(errors.BadRequest("Invalid project ID"))
+		return
+	}
+
+	memberUserIDStr := c.Param("userId")
+	memberUserID, err := strconv.ParseUint(memberUserIDStr, 10, 32)
+	if err != nil {
+		c.Error(errors.BadRequest("Invalid user ID"))
+		return
+	}
+
+	userID := c.GetUint("user_id")
+	if err := h.service.RemoveMember(c.Request.Context(), userID, uint(projectID), uint(memberUserID)); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Member removed successfully"})
+}
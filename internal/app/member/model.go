@@ -0,0 +1,55 @@
+package member
+
+import (
+	"time"
+
+	"github.com/team-xquare/deployment-platform/internal/pkg/utils/pagination"
+)
+
+type Role string
+
+const (
+	RoleOwner      Role = "owner"
+	RoleMaintainer Role = "maintainer"
+	RoleDeveloper  Role = "developer"
+	RoleViewer     Role = "viewer"
+)
+
+var roleLevels = map[Role]int{
+	RoleViewer:     1,
+	RoleDeveloper:  2,
+	RoleMaintainer: 3,
+	RoleOwner:      4,
+}
+
+// IsValid reports whether r is one of the known project roles.
+func (r Role) IsValid() bool {
+	_, ok := roleLevels[r]
+	return ok
+}
+
+// Includes reports whether r grants at least the permissions of required.
+func (r Role) Includes(required Role) bool {
+	return roleLevels[r] >= roleLevels[required]
+}
+
+type Member struct {
+	ID        uint      `json:"id" db:"id"`
+	ProjectID uint      `json:"project_id" db:"project_id"`
+	UserID    uint      `json:"user_id" db:"user_id"`
+	Role      Role      `json:"role" db:"role"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+
+	// Populated by joins with the users table
+	Email string `json:"email" db:"email"`
+	Name  string `json:"name" db:"name"`
+}
+
+// ListFilter narrows and pages the members of a project. Name matches members
+// whose name or email contains it.
+type ListFilter struct {
+	Name string
+	Role Role
+	Page pagination.Params
+}
@@ -0,0 +1,13 @@
+package member
+
+import "context"
+
+type Repository interface {
+	Save(ctx context.Context, member *Member) error
+	FindByProjectAndUser(ctx context.Context, projectID, userID uint) (*Member, error)
+	FindByProjectID(ctx context.Context, projectID uint, filter ListFilter) ([]*Member, int, error)
+	// LockOwners counts the project's owners and locks their rows until the
+	// transaction in ctx ends.
+	LockOwners(ctx context.Context, projectID uint) (int, error)
+	Delete(ctx context.Context, projectID, userID uint) error
+}
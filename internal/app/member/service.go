@@ -0,0 +1,247 @@
+package member
+
+import (
+	"context"
+
+	"github.com/team-xquare/deployment-platform/internal/app/audit"
+	"github.com/team-xquare/deployment-platform/internal/app/outbox"
+	"github.com/team-xquare/deployment-platform/internal/app/user"
+	"github.com/team-xquare/deployment-platform/internal/pkg/utils/errors"
+	"github.com/team-xquare/deployment-platform/internal/pkg/utils/pagination"
+)
+
+type Service struct {
+	repo     Repository
+	userRepo user.Repository
+	tx       outbox.Transactor
+}
+
+func NewService(repo Repository, userRepo user.Repository, tx outbox.Transactor) *Service {
+	return &Service{repo: repo, userRepo: userRepo, tx: tx}
+}
+
+// Authorize checks that the user is a member of the project with at least the
+// required role. Every project, application and addon operation goes through it.
+func (s *Service) Authorize(ctx context.Context, userID, projectID uint, required Role) (*Member, error) {
+	member, err := s.repo.FindByProjectAndUser(ctx, projectID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if member == nil || !member.Role.Includes(required) {
+		return nil, errors.Forbidden("Access denied")
+	}
+
+	return member, nil
+}
+
+// AuthorizeAuditLog lets maintainers read the project's audit log.
+func (s *Service) AuthorizeAuditLog(ctx context.Context, userID, projectID uint) error {
+	_, err := s.Authorize(ctx, userID, projectID, RoleMaintainer)
+	return err
+}
+
+func (s *Service) GetMembers(ctx context.Context, userID, projectID uint, filter ListFilter) (*MemberListResponse, error) {
+	if filter.Role != "" && !filter.Role.IsValid() {
+		return nil, errors.BadRequest("Invalid role")
+	}
+
+	if _, err := s.Authorize(ctx, userID, projectID, RoleViewer); err != nil {
+		return nil, err
+	}
+
+	members, total, err := s.repo.FindByProjectID(ctx, projectID, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	members, page := pagination.Paginate(members, filter.Page, total, func(m *Member) (uint, string) {
+		return m.ID, m.Name
+	})
+
+	responses := make([]*MemberResponse, len(members))
+	for i, member := range members {
+		responses[i] = s.toResponse(member)
+	}
+
+	return &MemberListResponse{Members: responses, Page: page}, nil
+}
+
+func (s *Service) InviteMember(ctx context.Context, userID, projectID uint, req InviteMemberRequest) (*MemberResponse, error) {
+	if !req.Role.IsValid() {
+		return nil, errors.BadRequest("Invalid role")
+	}
+
+	actor, err := s.Authorize(ctx, userID, projectID, RoleMaintainer)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only owners can hand out the owner role
+	if req.Role == RoleOwner && actor.Role != RoleOwner {
+		return nil, errors.Forbidden("Only owners can add owners")
+	}
+
+	invitee, err := s.userRepo.FindByEmail(ctx, req.Email)
+	if err != nil {
+		return nil, err
+	}
+	if invitee == nil {
+		return nil, errors.NotFound("User not found")
+	}
+
+	existing, err := s.repo.FindByProjectAndUser(ctx, projectID, invitee.ID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, errors.BadRequest("User is already a member of this project")
+	}
+
+	member := &Member{
+		ProjectID: projectID,
+		UserID:    invitee.ID,
+		Role:      req.Role,
+		Email:     invitee.Email,
+		Name:      invitee.Name,
+	}
+
+	if err := s.repo.Save(ctx, member); err != nil {
+		return nil, err
+	}
+
+	response := s.toResponse(member)
+	audit.Record(ctx, audit.Event{
+		Action:       "member.invite",
+		ResourceType: "member",
+		ResourceID:   audit.ID(member.UserID),
+		ProjectID:    &projectID,
+		After:        response,
+	})
+
+	return response, nil
+}
+
+func (s *Service) UpdateMemberRole(ctx context.Context, userID, projectID, memberUserID uint, req UpdateMemberRoleRequest) (*MemberResponse, error) {
+	if !req.Role.IsValid() {
+		return nil, errors.BadRequest("Invalid role")
+	}
+
+	actor, err := s.Authorize(ctx, userID, projectID, RoleMaintainer)
+	if err != nil {
+		return nil, err
+	}
+
+	member, err := s.repo.FindByProjectAndUser(ctx, projectID, memberUserID)
+	if err != nil {
+		return nil, err
+	}
+	if member == nil {
+		return nil, errors.NotFound("Member not found")
+	}
+
+	// Granting or revoking the owner role is reserved for owners
+	if (req.Role == RoleOwner || member.Role == RoleOwner) && actor.Role != RoleOwner {
+		return nil, errors.Forbidden("Only owners can change owner roles")
+	}
+
+	before := s.toResponse(member)
+	demoted := member.Role == RoleOwner && req.Role != RoleOwner
+	member.Role = req.Role
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if demoted {
+			if err := s.ensureAnotherOwner(ctx, projectID); err != nil {
+				return err
+			}
+		}
+		return s.repo.Save(ctx, member)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response := s.toResponse(member)
+	audit.Record(ctx, audit.Event{
+		Action:       "member.update_role",
+		ResourceType: "member",
+		ResourceID:   audit.ID(member.UserID),
+		ProjectID:    &projectID,
+		Before:       before,
+		After:        response,
+	})
+
+	return response, nil
+}
+
+func (s *Service) RemoveMember(ctx context.Context, userID, projectID, memberUserID uint) error {
+	member, err := s.repo.FindByProjectAndUser(ctx, projectID, memberUserID)
+	if err != nil {
+		return err
+	}
+
+	// Members can always leave a project on their own
+	if userID != memberUserID {
+		actor, err := s.Authorize(ctx, userID, projectID, RoleMaintainer)
+		if err != nil {
+			return err
+		}
+
+		if member != nil && member.Role == RoleOwner && actor.Role != RoleOwner {
+			return errors.Forbidden("Only owners can remove owners")
+		}
+	}
+
+	if member == nil {
+		return errors.NotFound("Member not found")
+	}
+
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if member.Role == RoleOwner {
+			if err := s.ensureAnotherOwner(ctx, projectID); err != nil {
+				return err
+			}
+		}
+		return s.repo.Delete(ctx, projectID, memberUserID)
+	})
+	if err != nil {
+		return err
+	}
+
+	audit.Record(ctx, audit.Event{
+		Action:       "member.remove",
+		ResourceType: "member",
+		ResourceID:   audit.ID(memberUserID),
+		ProjectID:    &projectID,
+		Before:       s.toResponse(member),
+	})
+
+	return nil
+}
+
+// ensureAnotherOwner refuses to demote or remove the project's last owner. It
+// must run inside the transaction that makes the change: the owners stay
+// locked until it ends, so concurrent changes cannot each leave the other as
+// the last owner and then remove it.
+func (s *Service) ensureAnotherOwner(ctx context.Context, projectID uint) error {
+	owners, err := s.repo.LockOwners(ctx, projectID)
+	if err != nil {
+		return err
+	}
+
+	if owners <= 1 {
+		return errors.BadRequest("Project must have at least one owner")
+	}
+
+	return nil
+}
+
+func (s *Service) toResponse(member *Member) *MemberResponse {
+	return &MemberResponse{
+		UserID:    member.UserID,
+		Email:     member.Email,
+		Name:      member.Name,
+		Role:      member.Role,
+		CreatedAt: member.CreatedAt,
+		UpdatedAt: member.UpdatedAt,
+	}
+}
@@ -0,0 +1,38 @@
+package outbox
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	StatusPending   = "pending"
+	StatusDelivered = "delivered"
+	StatusDead      = "dead"
+)
+
+// Aggregate types identify what an outbox message was written for, so the
+// worker can report delivery results back to the owning service.
+const (
+	AggregateDeployment = "deployment"
+)
+
+type Message struct {
+	ID             uint            `json:"id" db:"id"`
+	AggregateType  string          `json:"aggregate_type" db:"aggregate_type"`
+	AggregateID    uint            `json:"aggregate_id" db:"aggregate_id"`
+	InstallationID string          `json:"installation_id" db:"installation_id"`
+	Owner          string          `json:"owner" db:"owner"`
+	Repo           string          `json:"repo" db:"repo"`
+	EventType      string          `json:"event_type" db:"event_type"`
+	Path           string          `json:"path" db:"path"`
+	Payload        json.RawMessage `json:"payload" db:"payload"`
+	Status         string          `json:"status" db:"status"`
+	Attempts       int             `json:"attempts" db:"attempts"`
+	ClaimToken     string          `json:"claim_token" db:"claim_token"`
+	LastError      string          `json:"last_error" db:"last_error"`
+	NextAttemptAt  time.Time       `json:"next_attempt_at" db:"next_attempt_at"`
+	DeliveredAt    *time.Time      `json:"delivered_at" db:"delivered_at"`
+	CreatedAt      time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at" db:"updated_at"`
+}
@@ -0,0 +1,33 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrLeaseLost is returned when a message's lease expired and it may have been
+// claimed by another worker, whose result must not be overwritten.
+var ErrLeaseLost = errors.New("outbox: lease lost")
+
+type Repository interface {
+	Save(ctx context.Context, message *Message) error
+	// ClaimDue leases up to limit pending messages whose next attempt is due,
+	// so that concurrent workers never process the same message twice. A
+	// message is not claimed while an earlier one for the same aggregate or
+	// path is still pending, so dispatches are delivered in the order they
+	// were written.
+	ClaimDue(ctx context.Context, token string, limit int, lease time.Duration) ([]*Message, error)
+	// The Mark methods only update a message still claimed with its
+	// ClaimToken, and return ErrLeaseLost otherwise. Delivered and dead
+	// messages have the secrets removed from their payload.
+	MarkDelivered(ctx context.Context, message *Message) error
+	MarkRetry(ctx context.Context, message *Message, delay time.Duration) error
+	MarkDead(ctx context.Context, message *Message) error
+}
+
+// Transactor runs fn inside a database transaction that repositories join
+// through the context, so entity rows and their outbox messages commit together.
+type Transactor interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
@@ -0,0 +1,41 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/team-xquare/deployment-platform/internal/pkg/utils/errors"
+)
+
+type Service struct {
+	repo Repository
+}
+
+func NewService(repo Repository) *Service {
+	return &Service{repo: repo}
+}
+
+// Enqueue writes a repository dispatch to the outbox. Call it with the context
+// of the transaction that saves the entity the dispatch belongs to; the worker
+// delivers it once that transaction has committed. Dispatches for the same
+// path of owner/repo are delivered in the order they were enqueued.
+func (s *Service) Enqueue(ctx context.Context, aggregateType string, aggregateID uint, installationID, owner, repo, eventType, path string, payload interface{}) error {
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return errors.Internal("Failed to marshal payload: " + err.Error())
+	}
+
+	message := &Message{
+		AggregateType:  aggregateType,
+		AggregateID:    aggregateID,
+		InstallationID: installationID,
+		Owner:          owner,
+		Repo:           repo,
+		EventType:      eventType,
+		Path:           path,
+		Payload:        payloadBytes,
+		Status:         StatusPending,
+	}
+
+	return s.repo.Save(ctx, message)
+}
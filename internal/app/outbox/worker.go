@@ -0,0 +1,300 @@
+package outbox
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/team-xquare/deployment-platform/internal/pkg/config"
+	"github.com/team-xquare/deployment-platform/internal/pkg/utils/crypto"
+)
+
+// Dispatcher delivers a message to GitHub as a repository_dispatch event.
+type Dispatcher interface {
+	DispatchRepositoryEvent(ctx context.Context, installationID, owner, repo, eventType string, payload json.RawMessage) error
+}
+
+// ResultHandler is told about the final outcome of messages written for its
+// aggregate type.
+type ResultHandler interface {
+	HandleDelivered(ctx context.Context, message *Message) error
+	HandleDeadLettered(ctx context.Context, message *Message) error
+}
+
+type Options struct {
+	PollInterval time.Duration
+	BatchSize    int
+	MaxAttempts  int
+	BaseBackoff  time.Duration
+	MaxBackoff   time.Duration
+	// Lease is how long a claimed message stays invisible to other workers.
+	// Messages left claimed by a crashed worker are retried once it expires.
+	Lease time.Duration
+	// DispatchTimeout bounds a single dispatch. It must be shorter than Lease
+	// so a message's result is recorded before another worker can claim it.
+	DispatchTimeout time.Duration
+}
+
+func DefaultOptions() Options {
+	return Options{
+		PollInterval:    2 * time.Second,
+		BatchSize:       20,
+		MaxAttempts:     8,
+		BaseBackoff:     5 * time.Second,
+		MaxBackoff:      10 * time.Minute,
+		Lease:           2 * time.Minute,
+		DispatchTimeout: 30 * time.Second,
+	}
+}
+
+// OptionsFromConfig applies the OUTBOX_* settings on top of DefaultOptions.
+func OptionsFromConfig() Options {
+	opts := DefaultOptions()
+
+	if d, err := time.ParseDuration(config.AppConfig.OutboxPollInterval); err == nil && d > 0 {
+		opts.PollInterval = d
+	}
+	if n, err := strconv.Atoi(config.AppConfig.OutboxMaxAttempts); err == nil && n > 0 {
+		opts.MaxAttempts = n
+	}
+	if d, err := time.ParseDuration(config.AppConfig.OutboxBaseBackoff); err == nil && d > 0 {
+		opts.BaseBackoff = d
+	}
+	if d, err := time.ParseDuration(config.AppConfig.OutboxMaxBackoff); err == nil && d > 0 {
+		opts.MaxBackoff = d
+	}
+
+	return opts
+}
+
+// Worker polls the outbox and delivers pending messages, retrying failures
+// with exponential backoff until MaxAttempts is reached, after which the
+// message is dead-lettered. The values of a payload's "secrets" object are
+// stored sealed with the keyring and only decrypted for the dispatch.
+type Worker struct {
+	repo       Repository
+	dispatcher Dispatcher
+	keyring    *crypto.Keyring
+	opts       Options
+	handlers   map[string]ResultHandler
+
+	startOnce sync.Once
+	stopOnce  sync.Once
+	stop      chan struct{}
+	done      chan struct{}
+	ctx       context.Context
+	cancel    context.CancelFunc
+}
+
+func NewWorker(repo Repository, dispatcher Dispatcher, keyring *crypto.Keyring, opts Options) *Worker {
+	if opts.DispatchTimeout <= 0 || opts.DispatchTimeout >= opts.Lease {
+		opts.DispatchTimeout = opts.Lease / 2
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Worker{
+		repo:       repo,
+		dispatcher: dispatcher,
+		keyring:    keyring,
+		opts:       opts,
+		handlers:   make(map[string]ResultHandler),
+		stop:       make(chan struct{}),
+		done:       make(chan struct{}),
+		ctx:        ctx,
+		cancel:     cancel,
+	}
+}
+
+// RegisterHandler must be called before Start.
+func (w *Worker) RegisterHandler(aggregateType string, handler ResultHandler) {
+	w.handlers[aggregateType] = handler
+}
+
+func (w *Worker) Start() {
+	w.startOnce.Do(func() {
+		go w.run()
+	})
+}
+
+// Shutdown stops polling and waits for the batch in progress to finish. If ctx
+// expires first, in-flight dispatches are cancelled; their messages stay
+// claimed until the lease runs out and are then picked up again.
+func (w *Worker) Shutdown(ctx context.Context) error {
+	w.stopOnce.Do(func() {
+		close(w.stop)
+	})
+
+	select {
+	case <-w.done:
+		w.cancel()
+		return nil
+	case <-ctx.Done():
+		w.cancel()
+		<-w.done
+		return ctx.Err()
+	}
+}
+
+func (w *Worker) run() {
+	defer close(w.done)
+
+	ticker := time.NewTicker(w.opts.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		w.processBatch()
+
+		select {
+		case <-w.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *Worker) processBatch() {
+	token, err := newClaimToken()
+	if err != nil {
+		log.Printf("outbox: failed to generate claim token: %v", err)
+		return
+	}
+
+	// Taken before claiming, so it never outlasts the leases actually granted
+	leaseEnd := time.Now().Add(w.opts.Lease)
+	messages, err := w.repo.ClaimDue(w.ctx, token, w.opts.BatchSize, w.opts.Lease)
+	if err != nil {
+		log.Printf("outbox: failed to claim messages: %v", err)
+		return
+	}
+
+	for _, message := range messages {
+		select {
+		case <-w.stop:
+			return
+		default:
+		}
+		// The rest are claimed again once their leases expire
+		if time.Until(leaseEnd) < w.opts.DispatchTimeout {
+			return
+		}
+		w.process(message)
+	}
+}
+
+func (w *Worker) process(message *Message) {
+	ctx := w.ctx
+	message.Attempts++
+
+	err := w.dispatch(ctx, message)
+	if err == nil {
+		if err := w.repo.MarkDelivered(ctx, message); err != nil {
+			log.Printf("outbox: failed to mark message %d delivered: %v", message.ID, err)
+			return
+		}
+		message.Status = StatusDelivered
+		w.notify(ctx, message)
+		return
+	}
+
+	message.LastError = err.Error()
+	if message.Attempts >= w.opts.MaxAttempts {
+		if err := w.repo.MarkDead(ctx, message); err != nil {
+			log.Printf("outbox: failed to dead-letter message %d: %v", message.ID, err)
+			return
+		}
+		log.Printf("outbox: message %d dead-lettered after %d attempts: %s", message.ID, message.Attempts, message.LastError)
+		message.Status = StatusDead
+		w.notify(ctx, message)
+		return
+	}
+
+	delay := w.backoff(message.Attempts)
+	if err := w.repo.MarkRetry(ctx, message, delay); err != nil {
+		log.Printf("outbox: failed to reschedule message %d: %v", message.ID, err)
+	}
+}
+
+func (w *Worker) dispatch(ctx context.Context, message *Message) error {
+	payload, err := w.openSecrets(message.Payload)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, w.opts.DispatchTimeout)
+	defer cancel()
+
+	return w.dispatcher.DispatchRepositoryEvent(ctx, message.InstallationID, message.Owner, message.Repo, message.EventType, payload)
+}
+
+// openSecrets returns the payload with the values of its "secrets" object
+// decrypted. Messages written before secrets were sealed hold them in plain
+// text and are dispatched as they are.
+func (w *Worker) openSecrets(payload json.RawMessage) (json.RawMessage, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(payload, &fields); err != nil {
+		return nil, err
+	}
+	raw, ok := fields["secrets"]
+	if !ok {
+		return payload, nil
+	}
+
+	var secrets map[string]string
+	if err := json.Unmarshal(raw, &secrets); err != nil {
+		return nil, err
+	}
+	opened, err := w.keyring.DecryptValues(secrets)
+	if err != nil {
+		return nil, err
+	}
+
+	fields["secrets"], err = json.Marshal(opened)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(fields)
+}
+
+func (w *Worker) notify(ctx context.Context, message *Message) {
+	handler, ok := w.handlers[message.AggregateType]
+	if !ok {
+		return
+	}
+
+	var err error
+	switch message.Status {
+	case StatusDelivered:
+		err = handler.HandleDelivered(ctx, message)
+	case StatusDead:
+		err = handler.HandleDeadLettered(ctx, message)
+	}
+	if err != nil {
+		log.Printf("outbox: result handler for message %d failed: %v", message.ID, err)
+	}
+}
+
+// backoff returns BaseBackoff doubled for every failed attempt after the
+// first, capped at MaxBackoff.
+func (w *Worker) backoff(attempts int) time.Duration {
+	delay := w.opts.BaseBackoff
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= w.opts.MaxBackoff {
+			return w.opts.MaxBackoff
+		}
+	}
+	return delay
+}
+
+func newClaimToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
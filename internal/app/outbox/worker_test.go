@@ -0,0 +1,293 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/team-xquare/deployment-platform/internal/app/github"
+	"github.com/team-xquare/deployment-platform/internal/pkg/config"
+	"github.com/team-xquare/deployment-platform/internal/pkg/utils/crypto"
+)
+
+// fakeRepository holds messages in memory. Retried messages are due again at
+// once, so every processBatch makes the next attempt; the requested delays are
+// recorded instead.
+type fakeRepository struct {
+	messages []*Message
+	delays   []time.Duration
+}
+
+func (r *fakeRepository) Save(ctx context.Context, message *Message) error {
+	message.ID = uint(len(r.messages) + 1)
+	message.Status = StatusPending
+	r.messages = append(r.messages, message)
+	return nil
+}
+
+func (r *fakeRepository) ClaimDue(ctx context.Context, token string, limit int, lease time.Duration) ([]*Message, error) {
+	var claimed []*Message
+	for _, message := range r.messages {
+		if message.Status == StatusPending && message.ClaimToken == "" && len(claimed) < limit {
+			message.ClaimToken = token
+			claimed = append(claimed, message)
+		}
+	}
+	return claimed, nil
+}
+
+func (r *fakeRepository) MarkDelivered(ctx context.Context, message *Message) error {
+	message.Status = StatusDelivered
+	message.ClaimToken = ""
+	return nil
+}
+
+func (r *fakeRepository) MarkRetry(ctx context.Context, message *Message, delay time.Duration) error {
+	r.delays = append(r.delays, delay)
+	message.ClaimToken = ""
+	return nil
+}
+
+func (r *fakeRepository) MarkDead(ctx context.Context, message *Message) error {
+	message.Status = StatusDead
+	message.ClaimToken = ""
+	return nil
+}
+
+type fakeHandler struct {
+	delivered    []uint
+	deadLettered []uint
+}
+
+func (h *fakeHandler) HandleDelivered(ctx context.Context, message *Message) error {
+	h.delivered = append(h.delivered, message.AggregateID)
+	return nil
+}
+
+func (h *fakeHandler) HandleDeadLettered(ctx context.Context, message *Message) error {
+	h.deadLettered = append(h.deadLettered, message.AggregateID)
+	return nil
+}
+
+// dispatchServer stands in for the GitHub API and answers the first failures
+// repository dispatches with 500, then 204.
+type dispatchServer struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	failures int
+	requests []dispatchRequest
+}
+
+type dispatchRequest struct {
+	Path          string
+	Authorization string
+	EventType     string          `json:"event_type"`
+	ClientPayload json.RawMessage `json:"client_payload"`
+}
+
+func newDispatchServer(t *testing.T, failures int) *dispatchServer {
+	s := &dispatchServer{failures: failures}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req dispatchRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decoding dispatch request: %v", err)
+		}
+		req.Path = r.Method + " " + r.URL.Path
+		req.Authorization = r.Header.Get("Authorization")
+
+		s.mu.Lock()
+		s.requests = append(s.requests, req)
+		fail := len(s.requests) <= s.failures
+		s.mu.Unlock()
+
+		if fail {
+			http.Error(w, `{"message": "Server Error"}`, http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(s.Close)
+
+	// The GitHub service reads GITHUB_API_URL and GITHUB_TOKEN when it is created
+	previous := config.AppConfig
+	t.Cleanup(func() { config.AppConfig = previous })
+	config.AppConfig.GitHubAPIURL = s.URL
+	config.AppConfig.GitHubToken = "test-token"
+	config.AppConfig.GitHubAppID = ""
+	config.AppConfig.GitHubPrivateKey = ""
+
+	return s
+}
+
+func newTestKeyring(t *testing.T) *crypto.Keyring {
+	keyring, err := crypto.NewKeyring([]string{"test:MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY="})
+	if err != nil {
+		t.Fatalf("NewKeyring: %v", err)
+	}
+	return keyring
+}
+
+func newTestWorker(t *testing.T, repo *fakeRepository, handler *fakeHandler, keyring *crypto.Keyring) *Worker {
+	opts := Options{
+		PollInterval:    time.Second,
+		BatchSize:       10,
+		MaxAttempts:     4,
+		BaseBackoff:     time.Second,
+		MaxBackoff:      3 * time.Second,
+		Lease:           time.Minute,
+		DispatchTimeout: 5 * time.Second,
+	}
+	worker := NewWorker(repo, github.NewService(nil), keyring, opts)
+	worker.RegisterHandler(AggregateDeployment, handler)
+	// processBatch is called directly, so there is no polling loop to shut down
+	t.Cleanup(worker.cancel)
+	return worker
+}
+
+func saveTestMessage(t *testing.T, repo *fakeRepository, payload map[string]interface{}) *Message {
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatalf("marshaling payload: %v", err)
+	}
+	message := &Message{
+		AggregateType: AggregateDeployment,
+		AggregateID:   42,
+		Owner:         "team-xquare",
+		Repo:          "infrastructure-configs",
+		EventType:     "config-api",
+		Path:          "projects/demo/applications/api",
+		Payload:       raw,
+	}
+	if err := repo.Save(context.Background(), message); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	return message
+}
+
+func TestWorkerDeliversWithOpenedSecrets(t *testing.T) {
+	server := newDispatchServer(t, 0)
+	keyring := newTestKeyring(t)
+	repo := &fakeRepository{}
+	handler := &fakeHandler{}
+	worker := newTestWorker(t, repo, handler, keyring)
+
+	sealed, err := keyring.Encrypt("s3cret")
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+	message := saveTestMessage(t, repo, map[string]interface{}{
+		"path":    "projects/demo/applications/api",
+		"action":  "apply",
+		"secrets": map[string]string{"DB_PASSWORD": sealed},
+	})
+
+	worker.processBatch()
+
+	if message.Status != StatusDelivered {
+		t.Fatalf("status = %q, want %q", message.Status, StatusDelivered)
+	}
+	if len(handler.delivered) != 1 || handler.delivered[0] != 42 {
+		t.Errorf("delivered aggregates = %v, want [42]", handler.delivered)
+	}
+	if len(server.requests) != 1 {
+		t.Fatalf("got %d dispatch requests, want 1", len(server.requests))
+	}
+
+	req := server.requests[0]
+	if want := "POST /repos/team-xquare/infrastructure-configs/dispatches"; req.Path != want {
+		t.Errorf("request = %q, want %q", req.Path, want)
+	}
+	if want := "Bearer test-token"; req.Authorization != want {
+		t.Errorf("Authorization = %q, want %q", req.Authorization, want)
+	}
+	if req.EventType != "config-api" {
+		t.Errorf("event_type = %q, want config-api", req.EventType)
+	}
+
+	var payload struct {
+		Secrets map[string]string `json:"secrets"`
+	}
+	if err := json.Unmarshal(req.ClientPayload, &payload); err != nil {
+		t.Fatalf("decoding client_payload: %v", err)
+	}
+	if got := payload.Secrets["DB_PASSWORD"]; got != "s3cret" {
+		t.Errorf("dispatched secret = %q, want it decrypted", got)
+	}
+}
+
+func TestWorkerRetriesWithBackoff(t *testing.T) {
+	server := newDispatchServer(t, 3)
+	repo := &fakeRepository{}
+	handler := &fakeHandler{}
+	worker := newTestWorker(t, repo, handler, newTestKeyring(t))
+
+	message := saveTestMessage(t, repo, map[string]interface{}{"action": "apply"})
+
+	for i := 0; i < 3; i++ {
+		worker.processBatch()
+		if message.Status != StatusPending {
+			t.Fatalf("status after failed attempt %d = %q, want %q", i+1, message.Status, StatusPending)
+		}
+		if message.LastError == "" {
+			t.Errorf("attempt %d left no last error", i+1)
+		}
+	}
+
+	// Doubled from BaseBackoff and capped at MaxBackoff
+	want := []time.Duration{time.Second, 2 * time.Second, 3 * time.Second}
+	if len(repo.delays) != len(want) {
+		t.Fatalf("retry delays = %v, want %v", repo.delays, want)
+	}
+	for i := range want {
+		if repo.delays[i] != want[i] {
+			t.Errorf("retry delays = %v, want %v", repo.delays, want)
+			break
+		}
+	}
+
+	worker.processBatch()
+
+	if message.Status != StatusDelivered || message.Attempts != 4 {
+		t.Fatalf("after the fourth attempt got status %q with %d attempts, want delivered with 4", message.Status, message.Attempts)
+	}
+	if len(handler.delivered) != 1 || len(handler.deadLettered) != 0 {
+		t.Errorf("delivered %v and dead-lettered %v, want one delivery", handler.delivered, handler.deadLettered)
+	}
+	if len(server.requests) != 4 {
+		t.Errorf("got %d dispatch requests, want 4", len(server.requests))
+	}
+}
+
+func TestWorkerDeadLettersAfterMaxAttempts(t *testing.T) {
+	server := newDispatchServer(t, 100)
+	repo := &fakeRepository{}
+	handler := &fakeHandler{}
+	worker := newTestWorker(t, repo, handler, newTestKeyring(t))
+
+	message := saveTestMessage(t, repo, map[string]interface{}{"action": "apply"})
+
+	for i := 0; i < 4; i++ {
+		worker.processBatch()
+	}
+
+	if message.Status != StatusDead {
+		t.Fatalf("status = %q, want %q", message.Status, StatusDead)
+	}
+	if len(handler.deadLettered) != 1 || handler.deadLettered[0] != 42 || len(handler.delivered) != 0 {
+		t.Errorf("delivered %v and dead-lettered %v, want aggregate 42 dead-lettered", handler.delivered, handler.deadLettered)
+	}
+	if len(repo.delays) != 3 {
+		t.Errorf("got %d retries, want 3 before dead-lettering", len(repo.delays))
+	}
+
+	// Dead messages are not claimed again
+	worker.processBatch()
+	if len(server.requests) != 4 {
+		t.Errorf("got %d dispatch requests, want 4", len(server.requests))
+	}
+}
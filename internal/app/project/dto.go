@@ -1,23 +1,38 @@
 package project
 
-import "time"
+import (
+	"time"
 
+	"github.com/team-xquare/deployment-platform/internal/pkg/utils/pagination"
+)
+
+// CreateProjectRequest generates Slug from the name when it is omitted.
 type CreateProjectRequest struct {
 	Name        string `json:"name" binding:"required"`
+	Slug        string `json:"slug"`
 	Description string `json:"description"`
 }
 
+// Project slugs are immutable; UpdateProjectRequest only accepts the current
+// slug.
 type UpdateProjectRequest struct {
 	Name        string `json:"name" binding:"required"`
+	Slug        string `json:"slug"`
 	Description string `json:"description"`
 }
 
 type ProjectResponse struct {
 	ID          uint      `json:"id"`
 	Name        string    `json:"name"`
+	Slug        string    `json:"slug"`
 	Description string    `json:"description"`
 	OwnerID     uint      `json:"owner_id"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
 }
 
+type ProjectListResponse struct {
+	Projects []*ProjectResponse `json:"projects"`
+	pagination.Page
+}
+
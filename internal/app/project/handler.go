@@ -6,6 +6,7 @@ import (
 
 	"github.com/team-xquare/deployment-platform/internal/pkg/middleware"
 	"github.com/team-xquare/deployment-platform/internal/pkg/utils/errors"
+	"github.com/team-xquare/deployment-platform/internal/pkg/utils/pagination"
 
 	"github.com/gin-gonic/gin"
 )
@@ -48,8 +49,15 @@ func (h *Handler) CreateProject(c *gin.Context) {
 }
 
 func (h *Handler) GetProjects(c *gin.Context) {
+	page, err := pagination.Parse(c, "-"+pagination.SortCreated, pagination.SortName, pagination.SortCreated)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	filter := ListFilter{Name: c.Query("name"), Page: page}
+
 	userID := c.GetUint("user_id")
-	projects, err := h.service.GetUserProjects(c.Request.Context(), userID)
+	projects, err := h.service.GetUserProjects(c.Request.Context(), userID, filter)
 	if err != nil {
 		c.Error(err)
 		return
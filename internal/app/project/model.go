@@ -1,12 +1,24 @@
 package project
 
-import "time"
+import (
+	"time"
+
+	"github.com/team-xquare/deployment-platform/internal/pkg/utils/pagination"
+)
 
 type Project struct {
 	ID          uint      `json:"id" db:"id"`
 	Name        string    `json:"name" db:"name"`
+	Slug        string    `json:"slug" db:"slug"`
 	Description string    `json:"description" db:"description"`
 	OwnerID     uint      `json:"owner_id" db:"owner_id"`
 	CreatedAt   time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
 }
+
+// ListFilter narrows and pages the projects a user is a member of. Name
+// matches projects whose name contains it.
+type ListFilter struct {
+	Name string
+	Page pagination.Params
+}
@@ -5,7 +5,8 @@ import "context"
 type Repository interface {
 	Save(ctx context.Context, project *Project) error
 	FindByID(ctx context.Context, id uint) (*Project, error)
-	FindByOwnerID(ctx context.Context, ownerID uint) ([]*Project, error)
+	FindByMemberID(ctx context.Context, userID uint, filter ListFilter) ([]*Project, int, error)
 	FindByOwnerAndName(ctx context.Context, ownerID uint, name string) (*Project, error)
+	FindBySlug(ctx context.Context, slug string) (*Project, error)
 	Delete(ctx context.Context, id uint) error
 }
@@ -2,18 +2,24 @@ package project
 
 import (
 	"context"
+	"strconv"
+	"strings"
 
+	"github.com/team-xquare/deployment-platform/internal/app/audit"
 	"github.com/team-xquare/deployment-platform/internal/app/github"
+	"github.com/team-xquare/deployment-platform/internal/app/member"
 	"github.com/team-xquare/deployment-platform/internal/pkg/utils/errors"
+	"github.com/team-xquare/deployment-platform/internal/pkg/utils/pagination"
 )
 
 type Service struct {
 	repo       Repository
 	githubRepo github.Repository
+	memberSvc  *member.Service
 }
 
-func NewService(repo Repository, githubRepo github.Repository) *Service {
-	return &Service{repo: repo, githubRepo: githubRepo}
+func NewService(repo Repository, githubRepo github.Repository, memberSvc *member.Service) *Service {
+	return &Service{repo: repo, githubRepo: githubRepo, memberSvc: memberSvc}
 }
 
 func (s *Service) CreateProject(ctx context.Context, userID uint, req CreateProjectRequest) (*ProjectResponse, error) {
@@ -25,8 +31,28 @@ func (s *Service) CreateProject(ctx context.Context, userID uint, req CreateProj
 		return nil, errors.BadRequest("Project with this name already exists")
 	}
 
+	slug := req.Slug
+	if slug != "" {
+		if err := validateSlug(slug); err != nil {
+			return nil, err
+		}
+		taken, err := s.repo.FindBySlug(ctx, slug)
+		if err != nil {
+			return nil, err
+		}
+		if taken != nil {
+			return nil, errors.BadRequest("Project slug is already taken")
+		}
+	} else {
+		slug, err = s.generateSlug(ctx, req.Name)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	project := &Project{
 		Name:        req.Name,
+		Slug:        slug,
 		Description: req.Description,
 		OwnerID:     userID,
 	}
@@ -35,67 +61,64 @@ func (s *Service) CreateProject(ctx context.Context, userID uint, req CreateProj
 		return nil, err
 	}
 
-	return &ProjectResponse{
-		ID:          project.ID,
-		Name:        project.Name,
-		Description: project.Description,
-		OwnerID:     project.OwnerID,
-		CreatedAt:   project.CreatedAt,
-		UpdatedAt:   project.UpdatedAt,
-	}, nil
+	response := toResponse(project)
+	audit.Record(ctx, audit.Event{
+		Action:       "project.create",
+		ResourceType: "project",
+		ResourceID:   audit.ID(project.ID),
+		ProjectID:    &project.ID,
+		After:        response,
+	})
+
+	return response, nil
 }
 
 func (s *Service) GetProject(ctx context.Context, userID, projectID uint) (*ProjectResponse, error) {
-	project, err := s.repo.FindByID(ctx, projectID)
-	if err != nil {
+	if _, err := s.memberSvc.Authorize(ctx, userID, projectID, member.RoleViewer); err != nil {
 		return nil, err
 	}
 
-	if project.OwnerID != userID {
-		return nil, errors.Forbidden("Access denied")
+	project, err := s.repo.FindByID(ctx, projectID)
+	if err != nil {
+		return nil, err
 	}
 
-	return &ProjectResponse{
-		ID:          project.ID,
-		Name:        project.Name,
-		Description: project.Description,
-		OwnerID:     project.OwnerID,
-		CreatedAt:   project.CreatedAt,
-		UpdatedAt:   project.UpdatedAt,
-	}, nil
+	return toResponse(project), nil
 }
 
-func (s *Service) GetUserProjects(ctx context.Context, userID uint) ([]*ProjectResponse, error) {
-	projects, err := s.repo.FindByOwnerID(ctx, userID)
+func (s *Service) GetUserProjects(ctx context.Context, userID uint, filter ListFilter) (*ProjectListResponse, error) {
+	projects, total, err := s.repo.FindByMemberID(ctx, userID, filter)
 	if err != nil {
 		return nil, err
 	}
 
+	projects, page := pagination.Paginate(projects, filter.Page, total, func(p *Project) (uint, string) {
+		return p.ID, p.Name
+	})
+
 	responses := make([]*ProjectResponse, len(projects))
 	for i, project := range projects {
-		responses[i] = &ProjectResponse{
-			ID:          project.ID,
-			Name:        project.Name,
-			Description: project.Description,
-			OwnerID:     project.OwnerID,
-			CreatedAt:   project.CreatedAt,
-			UpdatedAt:   project.UpdatedAt,
-		}
+		responses[i] = toResponse(project)
 	}
 
-	return responses, nil
+	return &ProjectListResponse{Projects: responses, Page: page}, nil
 }
 
 func (s *Service) UpdateProject(ctx context.Context, userID, projectID uint, req UpdateProjectRequest) (*ProjectResponse, error) {
+	if _, err := s.memberSvc.Authorize(ctx, userID, projectID, member.RoleMaintainer); err != nil {
+		return nil, err
+	}
+
 	project, err := s.repo.FindByID(ctx, projectID)
 	if err != nil {
 		return nil, err
 	}
 
-	if project.OwnerID != userID {
-		return nil, errors.Forbidden("Access denied")
+	if req.Slug != "" && req.Slug != project.Slug {
+		return nil, errors.BadRequest("Project slug cannot be changed")
 	}
 
+	before := toResponse(project)
 	project.Name = req.Name
 	project.Description = req.Description
 
@@ -103,25 +126,79 @@ func (s *Service) UpdateProject(ctx context.Context, userID, projectID uint, req
 		return nil, err
 	}
 
+	response := toResponse(project)
+	audit.Record(ctx, audit.Event{
+		Action:       "project.update",
+		ResourceType: "project",
+		ResourceID:   audit.ID(project.ID),
+		ProjectID:    &project.ID,
+		Before:       before,
+		After:        response,
+	})
+
+	return response, nil
+}
+
+func (s *Service) DeleteProject(ctx context.Context, userID, projectID uint) error {
+	if _, err := s.memberSvc.Authorize(ctx, userID, projectID, member.RoleOwner); err != nil {
+		return err
+	}
+
+	project, err := s.repo.FindByID(ctx, projectID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.Delete(ctx, projectID); err != nil {
+		return err
+	}
+
+	audit.Record(ctx, audit.Event{
+		Action:       "project.delete",
+		ResourceType: "project",
+		ResourceID:   audit.ID(projectID),
+		ProjectID:    &projectID,
+		Before:       toResponse(project),
+	})
+
+	return nil
+}
+
+func toResponse(project *Project) *ProjectResponse {
 	return &ProjectResponse{
 		ID:          project.ID,
 		Name:        project.Name,
+		Slug:        project.Slug,
 		Description: project.Description,
 		OwnerID:     project.OwnerID,
 		CreatedAt:   project.CreatedAt,
 		UpdatedAt:   project.UpdatedAt,
-	}, nil
+	}
 }
 
-func (s *Service) DeleteProject(ctx context.Context, userID, projectID uint) error {
-	project, err := s.repo.FindByID(ctx, projectID)
-	if err != nil {
-		return err
-	}
+// generateSlug derives a slug from the project name, adding a numeric suffix
+// when it is already taken.
+func (s *Service) generateSlug(ctx context.Context, name string) (string, error) {
+	base := slugify(name)
+
+	for i := 1; i <= 100; i++ {
+		slug := base
+		if i > 1 {
+			suffix := "-" + strconv.Itoa(i)
+			if len(slug)+len(suffix) > maxSlugLength {
+				slug = strings.TrimRight(slug[:maxSlugLength-len(suffix)], "-")
+			}
+			slug += suffix
+		}
 
-	if project.OwnerID != userID {
-		return errors.Forbidden("Access denied")
+		existing, err := s.repo.FindBySlug(ctx, slug)
+		if err != nil {
+			return "", err
+		}
+		if existing == nil {
+			return slug, nil
+		}
 	}
 
-	return s.repo.Delete(ctx, projectID)
-}
\ No newline at end of file
+	return "", errors.BadRequest("Could not generate a unique project slug, please provide one")
+}
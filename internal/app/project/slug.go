@@ -0,0 +1,53 @@
+package project
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/team-xquare/deployment-platform/internal/pkg/utils/errors"
+)
+
+// Slugs are used as a DNS label and as a directory in the infrastructure
+// config repository, so they follow RFC 1123 label rules.
+const maxSlugLength = 63
+
+var (
+	slugPattern    = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]*[a-z0-9])?$`)
+	nonSlugPattern = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+func validateSlug(slug string) error {
+	if len(slug) > maxSlugLength || !slugPattern.MatchString(slug) {
+		return errors.BadRequest("Project slug must be at most 63 lowercase letters, digits or hyphens and start and end with a letter or digit")
+	}
+	return nil
+}
+
+// slugify derives a slug from a project name, e.g. "My Project!" becomes "my-project".
+func slugify(name string) string {
+	slug := nonSlugPattern.ReplaceAllString(strings.ToLower(name), "-")
+	slug = strings.Trim(slug, "-")
+	if len(slug) > maxSlugLength {
+		slug = strings.TrimRight(slug[:maxSlugLength], "-")
+	}
+	if slug == "" {
+		return "project"
+	}
+	return slug
+}
+
+// ApplicationPath is the directory of an application in the infrastructure config repository.
+func ApplicationPath(slug, name string) string {
+	return "projects/" + slug + "/applications/" + name
+}
+
+// EnvironmentPath is the directory of an application environment other than
+// the default one, which keeps the application's own directory.
+func EnvironmentPath(slug, name, environment string) string {
+	return ApplicationPath(slug, name) + "/environments/" + environment
+}
+
+// AddonPath is the directory of an addon in the infrastructure config repository.
+func AddonPath(slug, name string) string {
+	return "projects/" + slug + "/addons/" + name
+}
@@ -0,0 +1,42 @@
+package tier
+
+import "time"
+
+// SaveTierRequest defaults MinReplicas and MaxReplicas to 1.
+type SaveTierRequest struct {
+	CPUMillicores int `json:"cpu_millicores" binding:"required"`
+	MemoryMiB     int `json:"memory_mib" binding:"required"`
+	MinReplicas   int `json:"min_replicas"`
+	MaxReplicas   int `json:"max_replicas"`
+}
+
+type SaveQuotaRequest struct {
+	CPUMillicores int `json:"cpu_millicores" binding:"required"`
+	MemoryMiB     int `json:"memory_mib" binding:"required"`
+}
+
+type TierResponse struct {
+	ID            uint      `json:"id"`
+	Name          string    `json:"name"`
+	CPUMillicores int       `json:"cpu_millicores"`
+	MemoryMiB     int       `json:"memory_mib"`
+	MinReplicas   int       `json:"min_replicas"`
+	MaxReplicas   int       `json:"max_replicas"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// QuotaResponse reports a scope's usage; Quota is nil when the scope is not
+// limited.
+type QuotaResponse struct {
+	Scope   string      `json:"scope"`
+	ScopeID uint        `json:"scope_id"`
+	Quota   *QuotaLimit `json:"quota"`
+	Used    Resources   `json:"used"`
+}
+
+type QuotaLimit struct {
+	CPUMillicores int       `json:"cpu_millicores"`
+	MemoryMiB     int       `json:"memory_mib"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
@@ -0,0 +1,156 @@
+package tier
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/team-xquare/deployment-platform/internal/pkg/middleware"
+	"github.com/team-xquare/deployment-platform/internal/pkg/utils/errors"
+)
+
+type Handler struct {
+	service *Service
+}
+
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+func (h *Handler) RegisterRoutes(r *gin.RouterGroup) {
+	tiers := r.Group("/tiers")
+	tiers.Use(middleware.Auth())
+	{
+		tiers.GET("", h.GetTiers)
+	}
+
+	projects := r.Group("/projects/:id/quotas")
+	projects.Use(middleware.Auth())
+	{
+		projects.GET("", h.GetProjectQuotas)
+	}
+
+	adminTiers := r.Group("/admin/tiers")
+	adminTiers.Use(middleware.Auth(), middleware.Admin())
+	{
+		adminTiers.PUT("/:name", h.SaveTier)
+		adminTiers.DELETE("/:name", h.DeleteTier)
+	}
+
+	adminQuotas := r.Group("/admin/quotas")
+	adminQuotas.Use(middleware.Auth(), middleware.Admin())
+	{
+		adminQuotas.GET("/:scope/:id", h.GetQuota)
+		adminQuotas.PUT("/:scope/:id", h.SaveQuota)
+		adminQuotas.DELETE("/:scope/:id", h.DeleteQuota)
+	}
+}
+
+func (h *Handler) GetTiers(c *gin.Context) {
+	tiers, err := h.service.GetTiers(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tiers)
+}
+
+func (h *Handler) SaveTier(c *gin.Context) {
+	var req SaveTierRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(errors.BadRequest("Invalid request format"))
+		return
+	}
+
+	tier, err := h.service.SaveTier(c.Request.Context(), c.Param("name"), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tier)
+}
+
+func (h *Handler) DeleteTier(c *gin.Context) {
+	if err := h.service.DeleteTier(c.Request.Context(), c.Param("name")); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Tier deleted successfully"})
+}
+
+func (h *Handler) GetProjectQuotas(c *gin.Context) {
+	projectIDStr := c.Param("id")
+	projectID, err := strconv.ParseUint(projectIDStr, 10, 32)
+	if err != nil {
+		c.Error(errors.BadRequest("Invalid project ID"))
+		return
+	}
+
+	userID := c.GetUint("user_id")
+	quotas, err := h.service.GetProjectQuotas(c.Request.Context(), userID, uint(projectID))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, quotas)
+}
+
+func (h *Handler) GetQuota(c *gin.Context) {
+	scopeIDStr := c.Param("id")
+	scopeID, err := strconv.ParseUint(scopeIDStr, 10, 32)
+	if err != nil {
+		c.Error(errors.BadRequest("Invalid " + c.Param("scope") + " ID"))
+		return
+	}
+
+	quota, err := h.service.GetQuota(c.Request.Context(), c.Param("scope"), uint(scopeID))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, quota)
+}
+
+func (h *Handler) SaveQuota(c *gin.Context) {
+	scopeIDStr := c.Param("id")
+	scopeID, err := strconv.ParseUint(scopeIDStr, 10, 32)
+	if err != nil {
+		c.Error(errors.BadRequest("Invalid " + c.Param("scope") + " ID"))
+		return
+	}
+
+	var req SaveQuotaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(errors.BadRequest("Invalid request format"))
+		return
+	}
+
+	quota, err := h.service.SaveQuota(c.Request.Context(), c.Param("scope"), uint(scopeID), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, quota)
+}
+
+func (h *Handler) DeleteQuota(c *gin.Context) {
+	scopeIDStr := c.Param("id")
+	scopeID, err := strconv.ParseUint(scopeIDStr, 10, 32)
+	if err != nil {
+		c.Error(errors.BadRequest("Invalid " + c.Param("scope") + " ID"))
+		return
+	}
+
+	if err := h.service.DeleteQuota(c.Request.Context(), c.Param("scope"), uint(scopeID)); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Quota deleted successfully"})
+}
@@ -0,0 +1,75 @@
+package tier
+
+import (
+	"strconv"
+	"time"
+)
+
+// Tier is a size applications and addons are deployed with. CPU and memory
+// are per replica.
+type Tier struct {
+	ID            uint      `json:"id" db:"id"`
+	Name          string    `json:"name" db:"name"`
+	CPUMillicores int       `json:"cpu_millicores" db:"cpu_millicores"`
+	MemoryMiB     int       `json:"memory_mib" db:"memory_mib"`
+	MinReplicas   int       `json:"min_replicas" db:"min_replicas"`
+	MaxReplicas   int       `json:"max_replicas" db:"max_replicas"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// Allotment is what a resource of the tier counts against quotas: its CPU and
+// memory at the most replicas it can scale to.
+func (t *Tier) Allotment() Resources {
+	return Resources{
+		CPUMillicores: t.CPUMillicores * t.MaxReplicas,
+		MemoryMiB:     t.MemoryMiB * t.MaxReplicas,
+	}
+}
+
+// Spec returns the tier as the resources block of a dispatched spec.
+func (t *Tier) Spec() map[string]interface{} {
+	return map[string]interface{}{
+		"cpu":    strconv.Itoa(t.CPUMillicores) + "m",
+		"memory": strconv.Itoa(t.MemoryMiB) + "Mi",
+		"replicas": map[string]int{
+			"min": t.MinReplicas,
+			"max": t.MaxReplicas,
+		},
+	}
+}
+
+// Resources is an amount of CPU and memory.
+type Resources struct {
+	CPUMillicores int `json:"cpu_millicores"`
+	MemoryMiB     int `json:"memory_mib"`
+}
+
+func (r Resources) add(other Resources) Resources {
+	return Resources{CPUMillicores: r.CPUMillicores + other.CPUMillicores, MemoryMiB: r.MemoryMiB + other.MemoryMiB}
+}
+
+func (r Resources) sub(other Resources) Resources {
+	return Resources{CPUMillicores: r.CPUMillicores - other.CPUMillicores, MemoryMiB: r.MemoryMiB - other.MemoryMiB}
+}
+
+// Scopes a quota can be set for. A user quota covers every project the user owns.
+const (
+	ScopeProject = "project"
+	ScopeUser    = "user"
+)
+
+// Quota caps the total allotment of the applications and addons in its scope.
+type Quota struct {
+	ID            uint      `json:"id" db:"id"`
+	Scope         string    `json:"scope"`
+	ScopeID       uint      `json:"scope_id"`
+	CPUMillicores int       `json:"cpu_millicores" db:"cpu_millicores"`
+	MemoryMiB     int       `json:"memory_mib" db:"memory_mib"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at" db:"updated_at"`
+}
+
+func (q *Quota) Limit() Resources {
+	return Resources{CPUMillicores: q.CPUMillicores, MemoryMiB: q.MemoryMiB}
+}
@@ -0,0 +1,22 @@
+package tier
+
+import "context"
+
+type Repository interface {
+	FindAll(ctx context.Context) ([]*Tier, error)
+	// FindByName and FindQuota return nil, nil when there is no such tier or quota
+	FindByName(ctx context.Context, name string) (*Tier, error)
+	Save(ctx context.Context, tier *Tier) error
+	Delete(ctx context.Context, name string) error
+	// IsInUse reports whether an application environment or addon uses the tier
+	IsInUse(ctx context.Context, name string) (bool, error)
+	FindQuota(ctx context.Context, scope string, scopeID uint) (*Quota, error)
+	// LockQuotas returns the quotas covering a project, its own followed by its
+	// owner's, and locks them until the transaction in ctx ends.
+	LockQuotas(ctx context.Context, projectID uint) ([]*Quota, error)
+	SaveQuota(ctx context.Context, quota *Quota) error
+	DeleteQuota(ctx context.Context, scope string, scopeID uint) error
+	// Usage sums the allotments of the application environments and addons in
+	// the scope. Resources whose tier is not in the catalog count as nothing.
+	Usage(ctx context.Context, scope string, scopeID uint) (Resources, error)
+}
@@ -0,0 +1,397 @@
+package tier
+
+import (
+	"context"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/team-xquare/deployment-platform/internal/app/audit"
+	"github.com/team-xquare/deployment-platform/internal/app/member"
+	"github.com/team-xquare/deployment-platform/internal/app/project"
+	"github.com/team-xquare/deployment-platform/internal/app/user"
+	"github.com/team-xquare/deployment-platform/internal/pkg/utils/errors"
+)
+
+var namePattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9.-]*[a-z0-9])?$`)
+
+type Service struct {
+	repo        Repository
+	projectRepo project.Repository
+	userRepo    user.Repository
+	memberSvc   *member.Service
+}
+
+func NewService(repo Repository, projectRepo project.Repository, userRepo user.Repository, memberSvc *member.Service) *Service {
+	return &Service{repo: repo, projectRepo: projectRepo, userRepo: userRepo, memberSvc: memberSvc}
+}
+
+func (s *Service) GetTiers(ctx context.Context) ([]*TierResponse, error) {
+	tiers, err := s.repo.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*TierResponse, len(tiers))
+	for i, tier := range tiers {
+		responses[i] = toResponse(tier)
+	}
+
+	return responses, nil
+}
+
+// SaveTier creates the named tier or changes its resources. Resources already
+// deployed with the tier pick up the change on their next deployment.
+func (s *Service) SaveTier(ctx context.Context, name string, req SaveTierRequest) (*TierResponse, error) {
+	if req.MinReplicas == 0 {
+		req.MinReplicas = 1
+	}
+	if req.MaxReplicas == 0 {
+		req.MaxReplicas = req.MinReplicas
+	}
+
+	var fieldErrors []errors.FieldError
+	if len(name) > 50 || !namePattern.MatchString(name) {
+		fieldErrors = append(fieldErrors, errors.FieldError{Field: "name", Message: "must be at most 50 lowercase letters, digits, dots or hyphens"})
+	}
+	if req.CPUMillicores < 1 {
+		fieldErrors = append(fieldErrors, errors.FieldError{Field: "cpu_millicores", Message: "must be positive"})
+	}
+	if req.MemoryMiB < 1 {
+		fieldErrors = append(fieldErrors, errors.FieldError{Field: "memory_mib", Message: "must be positive"})
+	}
+	if req.MinReplicas < 1 {
+		fieldErrors = append(fieldErrors, errors.FieldError{Field: "min_replicas", Message: "must be positive"})
+	}
+	if req.MaxReplicas < req.MinReplicas {
+		fieldErrors = append(fieldErrors, errors.FieldError{Field: "max_replicas", Message: "must be at least min_replicas"})
+	}
+	if len(fieldErrors) > 0 {
+		return nil, errors.Validation(fieldErrors)
+	}
+
+	previous, err := s.repo.FindByName(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	var before interface{}
+	if previous != nil {
+		before = toResponse(previous)
+	}
+
+	tier := &Tier{
+		Name:          name,
+		CPUMillicores: req.CPUMillicores,
+		MemoryMiB:     req.MemoryMiB,
+		MinReplicas:   req.MinReplicas,
+		MaxReplicas:   req.MaxReplicas,
+	}
+	if err := s.repo.Save(ctx, tier); err != nil {
+		return nil, err
+	}
+
+	response := toResponse(tier)
+	audit.Record(ctx, audit.Event{
+		Action:       "tier.save",
+		ResourceType: "tier",
+		ResourceID:   name,
+		Before:       before,
+		After:        response,
+	})
+
+	return response, nil
+}
+
+func (s *Service) DeleteTier(ctx context.Context, name string) error {
+	tier, err := s.repo.FindByName(ctx, name)
+	if err != nil {
+		return err
+	}
+	if tier == nil {
+		return errors.NotFound("Tier not found")
+	}
+
+	inUse, err := s.repo.IsInUse(ctx, name)
+	if err != nil {
+		return err
+	}
+	if inUse {
+		return errors.BadRequest("Tier " + name + " is still used by applications or addons")
+	}
+
+	if err := s.repo.Delete(ctx, name); err != nil {
+		return err
+	}
+
+	audit.Record(ctx, audit.Event{
+		Action:       "tier.delete",
+		ResourceType: "tier",
+		ResourceID:   name,
+		Before:       toResponse(tier),
+	})
+
+	return nil
+}
+
+// Names returns the names of the tiers in the catalog.
+func (s *Service) Names(ctx context.Context) ([]string, error) {
+	tiers, err := s.repo.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, len(tiers))
+	for i, tier := range tiers {
+		names[i] = tier.Name
+	}
+	return names, nil
+}
+
+// Resolve returns the named tier, or a validation error on the tier field when
+// the catalog has no such tier.
+func (s *Service) Resolve(ctx context.Context, name string) (*Tier, error) {
+	tier, err := s.repo.FindByName(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if tier != nil {
+		return tier, nil
+	}
+
+	tiers, err := s.repo.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, len(tiers))
+	for i, t := range tiers {
+		names[i] = t.Name
+	}
+
+	return nil, errors.Validation([]errors.FieldError{{Field: "tier", Message: "must be one of " + strings.Join(names, ", ")}})
+}
+
+// Spec returns the resources block for a tier, or nil when the tier is not in
+// the catalog. Resources saved before the catalog existed are still deployed.
+func (s *Service) Spec(ctx context.Context, name string) (map[string]interface{}, error) {
+	tier, err := s.repo.FindByName(ctx, name)
+	if err != nil || tier == nil {
+		return nil, err
+	}
+	return tier.Spec(), nil
+}
+
+// quotaLabels names each quota scope in the message of an exceeded quota.
+var quotaLabels = map[string]string{
+	ScopeProject: "the project's quota",
+	ScopeUser:    "the project owner's quota",
+}
+
+// CheckQuota validates the tier a resource of the project is moving to and
+// checks that the change fits the quotas of the project and of its owner.
+// from is the resource's current tier, or "" for a new resource; changes that
+// do not grow the allotment are always allowed.
+//
+// It must be the first call in the transaction that saves the resource. The
+// quotas stay locked until that transaction ends, so concurrent changes in
+// the same scope are checked one after the other, each against the usage
+// committed by the ones before it.
+func (s *Service) CheckQuota(ctx context.Context, projectID uint, from, to string) error {
+	quotas, err := s.repo.LockQuotas(ctx, projectID)
+	if err != nil {
+		return err
+	}
+
+	next, err := s.Resolve(ctx, to)
+	if err != nil {
+		return err
+	}
+
+	growth := next.Allotment()
+	if from != "" {
+		current, err := s.repo.FindByName(ctx, from)
+		if err != nil {
+			return err
+		}
+		if current != nil {
+			growth = growth.sub(current.Allotment())
+		}
+	}
+	if growth.CPUMillicores <= 0 && growth.MemoryMiB <= 0 {
+		return nil
+	}
+
+	for _, quota := range quotas {
+		used, err := s.repo.Usage(ctx, quota.Scope, quota.ScopeID)
+		if err != nil {
+			return err
+		}
+		if message := exceeded(used.add(growth), quota.Limit()); message != "" {
+			return errors.Validation([]errors.FieldError{{Field: "tier", Message: to + " would exceed " + quotaLabels[quota.Scope] + ": " + message}})
+		}
+	}
+
+	return nil
+}
+
+func exceeded(needed, limit Resources) string {
+	var over []string
+	if needed.CPUMillicores > limit.CPUMillicores {
+		over = append(over, strconv.Itoa(needed.CPUMillicores)+"m of "+strconv.Itoa(limit.CPUMillicores)+"m CPU")
+	}
+	if needed.MemoryMiB > limit.MemoryMiB {
+		over = append(over, strconv.Itoa(needed.MemoryMiB)+"Mi of "+strconv.Itoa(limit.MemoryMiB)+"Mi memory")
+	}
+	return strings.Join(over, ", ")
+}
+
+// GetProjectQuotas reports the usage of a project against its own quota and
+// its owner's.
+func (s *Service) GetProjectQuotas(ctx context.Context, userID, projectID uint) ([]*QuotaResponse, error) {
+	if _, err := s.memberSvc.Authorize(ctx, userID, projectID, member.RoleViewer); err != nil {
+		return nil, err
+	}
+
+	proj, err := s.projectRepo.FindByID(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	projectQuota, err := s.quotaResponse(ctx, ScopeProject, proj.ID)
+	if err != nil {
+		return nil, err
+	}
+	ownerQuota, err := s.quotaResponse(ctx, ScopeUser, proj.OwnerID)
+	if err != nil {
+		return nil, err
+	}
+
+	return []*QuotaResponse{projectQuota, ownerQuota}, nil
+}
+
+func (s *Service) GetQuota(ctx context.Context, scope string, scopeID uint) (*QuotaResponse, error) {
+	if err := s.checkScope(ctx, scope, scopeID); err != nil {
+		return nil, err
+	}
+
+	return s.quotaResponse(ctx, scope, scopeID)
+}
+
+// SaveQuota sets the quota of a project or user. Lowering a quota below the
+// current usage only blocks further growth.
+func (s *Service) SaveQuota(ctx context.Context, scope string, scopeID uint, req SaveQuotaRequest) (*QuotaResponse, error) {
+	if err := s.checkScope(ctx, scope, scopeID); err != nil {
+		return nil, err
+	}
+
+	var fieldErrors []errors.FieldError
+	if req.CPUMillicores < 1 {
+		fieldErrors = append(fieldErrors, errors.FieldError{Field: "cpu_millicores", Message: "must be positive"})
+	}
+	if req.MemoryMiB < 1 {
+		fieldErrors = append(fieldErrors, errors.FieldError{Field: "memory_mib", Message: "must be positive"})
+	}
+	if len(fieldErrors) > 0 {
+		return nil, errors.Validation(fieldErrors)
+	}
+
+	before, err := s.quotaResponse(ctx, scope, scopeID)
+	if err != nil {
+		return nil, err
+	}
+
+	quota := &Quota{Scope: scope, ScopeID: scopeID, CPUMillicores: req.CPUMillicores, MemoryMiB: req.MemoryMiB}
+	if err := s.repo.SaveQuota(ctx, quota); err != nil {
+		return nil, err
+	}
+
+	response, err := s.quotaResponse(ctx, scope, scopeID)
+	if err != nil {
+		return nil, err
+	}
+	audit.Record(ctx, quotaEvent("quota.save", scope, scopeID, before, response))
+
+	return response, nil
+}
+
+func (s *Service) DeleteQuota(ctx context.Context, scope string, scopeID uint) error {
+	if err := s.checkScope(ctx, scope, scopeID); err != nil {
+		return err
+	}
+
+	before, err := s.quotaResponse(ctx, scope, scopeID)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.DeleteQuota(ctx, scope, scopeID); err != nil {
+		return err
+	}
+
+	audit.Record(ctx, quotaEvent("quota.delete", scope, scopeID, before, nil))
+
+	return nil
+}
+
+// quotaEvent describes a change to a scope's quota for the audit log; changes
+// to a project's quota are also listed in the project's log.
+func quotaEvent(action, scope string, scopeID uint, before, after interface{}) audit.Event {
+	event := audit.Event{
+		Action:       action,
+		ResourceType: "quota",
+		ResourceID:   scope + "/" + audit.ID(scopeID),
+		Before:       before,
+		After:        after,
+	}
+	if scope == ScopeProject {
+		event.ProjectID = &scopeID
+	}
+	return event
+}
+
+// checkScope makes sure the project or user a quota is addressed to exists.
+func (s *Service) checkScope(ctx context.Context, scope string, scopeID uint) error {
+	switch scope {
+	case ScopeProject:
+		_, err := s.projectRepo.FindByID(ctx, scopeID)
+		return err
+	case ScopeUser:
+		_, err := s.userRepo.FindById(ctx, scopeID)
+		return err
+	default:
+		return errors.BadRequest("Invalid quota scope")
+	}
+}
+
+func (s *Service) quotaResponse(ctx context.Context, scope string, scopeID uint) (*QuotaResponse, error) {
+	quota, err := s.repo.FindQuota(ctx, scope, scopeID)
+	if err != nil {
+		return nil, err
+	}
+	used, err := s.repo.Usage(ctx, scope, scopeID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &QuotaResponse{Scope: scope, ScopeID: scopeID, Used: used}
+	if quota != nil {
+		response.Quota = &QuotaLimit{
+			CPUMillicores: quota.CPUMillicores,
+			MemoryMiB:     quota.MemoryMiB,
+			UpdatedAt:     quota.UpdatedAt,
+		}
+	}
+
+	return response, nil
+}
+
+func toResponse(tier *Tier) *TierResponse {
+	return &TierResponse{
+		ID:            tier.ID,
+		Name:          tier.Name,
+		CPUMillicores: tier.CPUMillicores,
+		MemoryMiB:     tier.MemoryMiB,
+		MinReplicas:   tier.MinReplicas,
+		MaxReplicas:   tier.MaxReplicas,
+		CreatedAt:     tier.CreatedAt,
+		UpdatedAt:     tier.UpdatedAt,
+	}
+}
@@ -3,6 +3,7 @@ package user
 import (
 	"context"
 
+	"github.com/team-xquare/deployment-platform/internal/app/audit"
 	"github.com/team-xquare/deployment-platform/internal/pkg/utils/errors"
 
 	"golang.org/x/crypto/bcrypt"
@@ -35,8 +36,20 @@ func (s *Service) Register(ctx context.Context, req RegisterRequest) error {
 		Password: string(hashedPassword),
 		Name:     req.Name,
 	}
+	if err := s.repo.Save(ctx, user); err != nil {
+		return err
+	}
 
-	return s.repo.Save(ctx, user)
+	audit.Record(ctx, audit.Event{
+		ActorID:      &user.ID,
+		ActorEmail:   user.Email,
+		Action:       "user.register",
+		ResourceType: "user",
+		ResourceID:   audit.ID(user.ID),
+		After:        toResponse(user),
+	})
+
+	return nil
 }
 
 func (s *Service) Login(ctx context.Context, req LoginRequest) (*User, error) {
@@ -64,13 +77,7 @@ func (s *Service) GetByID(ctx context.Context, id uint) (*UserResponse, error) {
 		return nil, errors.NotFound("User not found")
 	}
 
-	return &UserResponse{
-		ID:        user.ID,
-		Email:     user.Email,
-		Name:      user.Name,
-		GitHubID:  user.GitHubID,
-		CreatedAt: user.CreatedAt,
-	}, nil
+	return toResponse(user), nil
 }
 
 func (s *Service) Update(ctx context.Context, id uint, req UpdateUserRequest) error {
@@ -87,14 +94,57 @@ func (s *Service) Update(ctx context.Context, id uint, req UpdateUserRequest) er
 		return errors.Internal("Failed to hash password")
 	}
 
+	before := toResponse(user)
 	user.Name = req.Name
 	user.Password = string(hashedPassword)
+	if err := s.repo.Update(ctx, user); err != nil {
+		return err
+	}
+
+	audit.Record(ctx, audit.Event{
+		Action:       "user.update",
+		ResourceType: "user",
+		ResourceID:   audit.ID(user.ID),
+		Before:       before,
+		After:        toResponse(user),
+	})
 
-	return s.repo.Update(ctx, user)
+	return nil
 }
 
 func (s *Service) Delete(ctx context.Context, id uint) error {
-	return s.repo.Delete(ctx, id)
+	user, err := s.repo.FindById(ctx, id)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return errors.NotFound("User not found")
+	}
+
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	audit.Record(ctx, audit.Event{
+		Action:       "user.delete",
+		ResourceType: "user",
+		ResourceID:   audit.ID(id),
+		Before:       toResponse(user),
+	})
+
+	return nil
+}
+
+// toResponse never includes the password hash, so it is also what the audit
+// log keeps of a user.
+func toResponse(user *User) *UserResponse {
+	return &UserResponse{
+		ID:        user.ID,
+		Email:     user.Email,
+		Name:      user.Name,
+		GitHubID:  user.GitHubID,
+		CreatedAt: user.CreatedAt,
+	}
 }
 
 func (s *Service) FindOrCreateByGitHub(ctx context.Context, githubID, email, name string) (*User, error) {
@@ -1,51 +1,172 @@
 package config
 
 import (
+	"fmt"
 	"os"
+	"strings"
 )
 
 type Config struct {
-	AppPort             string
-	JWTSecret           string
-	JWTAccessExpiry     string
-	JWTRefreshExpiry    string
-	MySQLHost           string
-	MySQLPort           string
-	MySQLDatabase       string
-	MySQLUsername       string
-	MySQLPassword       string
-	RedisHost           string
-	RedisPort           string
-	RedisPassword       string
-	RedisDB             string
-	GitHubAppID         string
-	GitHubPrivateKey    string
-	GitHubWebhookSecret string
-	GitHubToken         string
+	AppEnv                   string
+	AppPort                  string
+	JWTSecret                string
+	JWTAccessExpiry          string
+	JWTRefreshExpiry         string
+	MySQLHost                string
+	MySQLPort                string
+	MySQLDatabase            string
+	MySQLUsername            string
+	MySQLPassword            string
+	RedisHost                string
+	RedisPort                string
+	RedisPassword            string
+	RedisDB                  string
+	GitHubAppID              string
+	GitHubPrivateKey         string
+	GitHubWebhookSecret      string
+	GitHubWebhookSecrets     string
+	GitHubWebhookInsecureDev string
+	GitHubWebhookAllowSHA1   string
+	GitHubToken              string
+	GitHubAPIURL             string
+	OutboxPollInterval       string
+	OutboxMaxAttempts        string
+	OutboxBaseBackoff        string
+	OutboxMaxBackoff         string
+	AdminEmails              string
+	SecretsEncryptionKeys    string
+	PlatformBaseDomain       string
+	TrustedProxies           string
 }
 
+// devEncryptionKey seals secrets when SECRETS_ENCRYPTION_KEYS is unset. It is
+// rejected in production.
+const devEncryptionKey = "dev:ZGV2LWtleS1kby1ub3QtdXNlLWluLXByb2R1Y3Rpb24="
+
 var AppConfig Config
 
 func Load() {
 	AppConfig = Config{
-		AppPort:             getEnv("APP_PORT", "8080"),
-		JWTSecret:           getEnv("JWT_SECRET", "dev-secret"),
-		JWTAccessExpiry:     getEnv("JWT_ACCESS_EXPIRY", "24h"),
-		JWTRefreshExpiry:    getEnv("JWT_REFRESH_EXPIRY", "168h"),
-		MySQLHost:           getEnv("MYSQL_HOST", "localhost"),
-		MySQLPort:           getEnv("MYSQL_PORT", "3306"),
-		MySQLDatabase:       getEnv("MYSQL_DATABASE", "deployment_platform"),
-		MySQLUsername:       getEnv("MYSQL_USERNAME", "root"),
-		MySQLPassword:       getEnv("MYSQL_PASSWORD", ""),
-		RedisHost:           getEnv("REDIS_HOST", "localhost"),
-		RedisPort:           getEnv("REDIS_PORT", "6379"),
-		RedisPassword:       getEnv("REDIS_PASSWORD", ""),
-		RedisDB:             getEnv("REDIS_DB", "0"),
-		GitHubAppID:         os.Getenv("GITHUB_APP_ID"),
-		GitHubPrivateKey:    os.Getenv("GITHUB_PRIVATE_KEY"),
-		GitHubWebhookSecret: os.Getenv("GITHUB_WEBHOOK_SECRET"),
-		GitHubToken:         os.Getenv("GITHUB_TOKEN"),
+		AppEnv:                   getEnv("APP_ENV", "development"),
+		AppPort:                  getEnv("APP_PORT", "8080"),
+		JWTSecret:                getEnv("JWT_SECRET", "dev-secret"),
+		JWTAccessExpiry:          getEnv("JWT_ACCESS_EXPIRY", "24h"),
+		JWTRefreshExpiry:         getEnv("JWT_REFRESH_EXPIRY", "168h"),
+		MySQLHost:                getEnv("MYSQL_HOST", "localhost"),
+		MySQLPort:                getEnv("MYSQL_PORT", "3306"),
+		MySQLDatabase:            getEnv("MYSQL_DATABASE", "deployment_platform"),
+		MySQLUsername:            getEnv("MYSQL_USERNAME", "root"),
+		MySQLPassword:            getEnv("MYSQL_PASSWORD", ""),
+		RedisHost:                getEnv("REDIS_HOST", "localhost"),
+		RedisPort:                getEnv("REDIS_PORT", "6379"),
+		RedisPassword:            getEnv("REDIS_PASSWORD", ""),
+		RedisDB:                  getEnv("REDIS_DB", "0"),
+		GitHubAppID:              os.Getenv("GITHUB_APP_ID"),
+		GitHubPrivateKey:         os.Getenv("GITHUB_PRIVATE_KEY"),
+		GitHubWebhookSecret:      os.Getenv("GITHUB_WEBHOOK_SECRET"),
+		GitHubWebhookSecrets:     os.Getenv("GITHUB_WEBHOOK_SECRETS"),
+		GitHubWebhookInsecureDev: os.Getenv("GITHUB_WEBHOOK_INSECURE_DEV"),
+		GitHubWebhookAllowSHA1:   os.Getenv("GITHUB_WEBHOOK_ALLOW_SHA1"),
+		GitHubToken:              os.Getenv("GITHUB_TOKEN"),
+		GitHubAPIURL:             os.Getenv("GITHUB_API_URL"),
+		OutboxPollInterval:       getEnv("OUTBOX_POLL_INTERVAL", "2s"),
+		OutboxMaxAttempts:        getEnv("OUTBOX_MAX_ATTEMPTS", "8"),
+		OutboxBaseBackoff:        getEnv("OUTBOX_BASE_BACKOFF", "5s"),
+		OutboxMaxBackoff:         getEnv("OUTBOX_MAX_BACKOFF", "10m"),
+		AdminEmails:              os.Getenv("ADMIN_EMAILS"),
+		SecretsEncryptionKeys:    getEnv("SECRETS_ENCRYPTION_KEYS", devEncryptionKey),
+		PlatformBaseDomain:       getEnv("PLATFORM_BASE_DOMAIN", "xquare.app"),
+		TrustedProxies:           os.Getenv("TRUSTED_PROXIES"),
+	}
+}
+
+// Validate reports settings that must not reach a running server.
+func Validate() error {
+	if AppConfig.AppEnv == "production" {
+		if len(WebhookSecrets()) == 0 {
+			return fmt.Errorf("GITHUB_WEBHOOK_SECRET must be set when APP_ENV is production")
+		}
+		if WebhookInsecureDev() {
+			return fmt.Errorf("GITHUB_WEBHOOK_INSECURE_DEV cannot be enabled when APP_ENV is production")
+		}
+		for _, key := range SecretsEncryptionKeys() {
+			if key == devEncryptionKey {
+				return fmt.Errorf("SECRETS_ENCRYPTION_KEYS cannot use the development key when APP_ENV is production")
+			}
+		}
+	}
+
+	return nil
+}
+
+// WebhookSecrets returns every secret webhook signatures are checked against:
+// GITHUB_WEBHOOK_SECRET plus the comma-separated GITHUB_WEBHOOK_SECRETS that
+// stay valid while the secret is being rotated.
+func WebhookSecrets() []string {
+	var secrets []string
+	for _, secret := range append([]string{AppConfig.GitHubWebhookSecret}, strings.Split(AppConfig.GitHubWebhookSecrets, ",")...) {
+		if secret = strings.TrimSpace(secret); secret != "" {
+			secrets = append(secrets, secret)
+		}
+	}
+	return secrets
+}
+
+// WebhookInsecureDev reports whether unsigned webhooks are accepted because no
+// secret is configured. Meant for local development only.
+func WebhookInsecureDev() bool {
+	return AppConfig.GitHubWebhookInsecureDev == "true"
+}
+
+// WebhookAllowSHA1 reports whether the legacy X-Hub-Signature header is accepted.
+func WebhookAllowSHA1() bool {
+	return AppConfig.GitHubWebhookAllowSHA1 == "true"
+}
+
+// SecretsEncryptionKeys returns the comma-separated "id:base64key" entries of
+// SECRETS_ENCRYPTION_KEYS. The first key encrypts new secrets; the rest stay
+// readable until secrets are re-encrypted after a rotation.
+func SecretsEncryptionKeys() []string {
+	var keys []string
+	for _, key := range strings.Split(AppConfig.SecretsEncryptionKeys, ",") {
+		if key = strings.TrimSpace(key); key != "" {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// PlatformBaseDomain returns the domain application hostnames are allocated
+// under, without a leading or trailing dot.
+func PlatformBaseDomain() string {
+	return strings.ToLower(strings.Trim(strings.TrimSpace(AppConfig.PlatformBaseDomain), "."))
+}
+
+// TrustedProxies returns the comma-separated addresses or CIDRs of
+// TRUSTED_PROXIES, whose X-Forwarded-For headers are believed when resolving
+// client IPs. It is nil when the server is not behind a proxy.
+func TrustedProxies() []string {
+	var proxies []string
+	for _, proxy := range strings.Split(AppConfig.TrustedProxies, ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			proxies = append(proxies, proxy)
+		}
+	}
+	return proxies
+}
+
+// IsAdmin reports whether email is listed in ADMIN_EMAILS.
+func IsAdmin(email string) bool {
+	if email == "" {
+		return false
+	}
+
+	for _, admin := range strings.Split(AppConfig.AdminEmails, ",") {
+		if strings.EqualFold(strings.TrimSpace(admin), email) {
+			return true
+		}
 	}
+	return false
 }
 
 func getEnv(key, defaultValue string) string {
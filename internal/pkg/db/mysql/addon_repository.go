@@ -3,9 +3,11 @@ package mysql
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 
 	"github.com/team-xquare/deployment-platform/internal/app/addon"
 	"github.com/team-xquare/deployment-platform/internal/pkg/utils/errors"
+	"github.com/team-xquare/deployment-platform/internal/pkg/utils/pagination"
 )
 
 type addonRepository struct {
@@ -20,11 +22,11 @@ func (r *addonRepository) Save(ctx context.Context, addon *addon.Addon) error {
 	if addon.ID == 0 {
 		// Insert new addon
 		query := `
-			INSERT INTO addons (project_id, name, type, tier, storage)
-			VALUES (?, ?, ?, ?, ?)
+			INSERT INTO addons (project_id, name, type, version, tier, storage)
+			VALUES (?, ?, ?, NULLIF(?, ''), ?, ?)
 		`
-		result, err := r.db.ExecContext(ctx, query,
-			addon.ProjectID, addon.Name, addon.Type, addon.Tier, addon.Storage,
+		result, err := conn(ctx, r.db).ExecContext(ctx, query,
+			addon.ProjectID, addon.Name, addon.Type, addon.Version, addon.Tier, addon.Storage,
 		)
 		if err != nil {
 			return errors.Internal("Failed to create addon")
@@ -39,11 +41,11 @@ func (r *addonRepository) Save(ctx context.Context, addon *addon.Addon) error {
 		// Update existing addon
 		query := `
 			UPDATE addons SET
-				name = ?, type = ?, tier = ?, storage = ?, updated_at = CURRENT_TIMESTAMP
+				name = ?, type = ?, version = NULLIF(?, ''), tier = ?, storage = ?, updated_at = CURRENT_TIMESTAMP
 			WHERE id = ?
 		`
-		_, err := r.db.ExecContext(ctx, query,
-			addon.Name, addon.Type, addon.Tier, addon.Storage, addon.ID,
+		_, err := conn(ctx, r.db).ExecContext(ctx, query,
+			addon.Name, addon.Type, addon.Version, addon.Tier, addon.Storage, addon.ID,
 		)
 		if err != nil {
 			return errors.Internal("Failed to update addon")
@@ -55,14 +57,15 @@ func (r *addonRepository) Save(ctx context.Context, addon *addon.Addon) error {
 
 func (r *addonRepository) FindByID(ctx context.Context, id uint) (*addon.Addon, error) {
 	query := `
-		SELECT id, project_id, name, type, tier, storage, created_at, updated_at
+		SELECT id, project_id, name, type, version, tier, storage, created_at, updated_at
 		FROM addons WHERE id = ?
 	`
 
 	var addon addon.Addon
+	var version sql.NullString
 
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&addon.ID, &addon.ProjectID, &addon.Name, &addon.Type, &addon.Tier, &addon.Storage,
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, id).Scan(
+		&addon.ID, &addon.ProjectID, &addon.Name, &addon.Type, &version, &addon.Tier, &addon.Storage,
 		&addon.CreatedAt, &addon.UpdatedAt,
 	)
 	if err != nil {
@@ -71,45 +74,62 @@ func (r *addonRepository) FindByID(ctx context.Context, id uint) (*addon.Addon,
 		}
 		return nil, errors.Internal("Failed to get addon")
 	}
+	addon.Version = version.String
 
 	return &addon, nil
 }
 
-func (r *addonRepository) FindByProjectID(ctx context.Context, projectID uint) ([]*addon.Addon, error) {
-	query := `
-		SELECT id, project_id, name, type, tier, storage, created_at, updated_at
-		FROM addons WHERE project_id = ?
-		ORDER BY created_at DESC
-	`
+func (r *addonRepository) FindByProjectID(ctx context.Context, projectID uint, filter addon.ListFilter) ([]*addon.Addon, int, error) {
+	var q listQuery
+	q.where("project_id = ?", projectID)
+	if filter.Name != "" {
+		q.whereContains(filter.Name, "name")
+	}
+	if filter.Type != "" {
+		q.where("type = ?", filter.Type)
+	}
+	if filter.Tier != "" {
+		q.where("tier = ?", filter.Tier)
+	}
 
-	rows, err := r.db.QueryContext(ctx, query, projectID)
+	total, err := q.count(ctx, conn(ctx, r.db), "addons")
 	if err != nil {
-		return nil, errors.Internal("Failed to get addons")
+		return nil, 0, errors.Internal("Failed to count addons")
+	}
+
+	clauses, args := q.page(filter.Page, "id", "name")
+	query := "SELECT id, project_id, name, type, version, tier, storage, created_at, updated_at FROM addons" + clauses
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, errors.Internal("Failed to get addons")
 	}
 	defer rows.Close()
 
 	var addons []*addon.Addon
 	for rows.Next() {
 		var addon addon.Addon
+		var version sql.NullString
 
 		err := rows.Scan(
-			&addon.ID, &addon.ProjectID, &addon.Name, &addon.Type, &addon.Tier, &addon.Storage,
+			&addon.ID, &addon.ProjectID, &addon.Name, &addon.Type, &version, &addon.Tier, &addon.Storage,
 			&addon.CreatedAt, &addon.UpdatedAt,
 		)
 		if err != nil {
-			return nil, errors.Internal("Failed to scan addon")
+			return nil, 0, errors.Internal("Failed to scan addon")
 		}
+		addon.Version = version.String
 
 		addons = append(addons, &addon)
 	}
 
-	return addons, nil
+	return addons, total, nil
 }
 
 func (r *addonRepository) Delete(ctx context.Context, id uint) error {
 	query := "DELETE FROM addons WHERE id = ?"
 
-	result, err := r.db.ExecContext(ctx, query, id)
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, id)
 	if err != nil {
 		return errors.Internal("Failed to delete addon")
 	}
@@ -124,4 +144,204 @@ func (r *addonRepository) Delete(ctx context.Context, id uint) error {
 	}
 
 	return nil
-}
\ No newline at end of file
+}
+
+const bindingColumns = `
+	id, addon_id, application_id, env_prefix, username, password, created_at
+`
+
+func (r *addonRepository) SaveBinding(ctx context.Context, binding *addon.Binding) error {
+	query := `
+		INSERT INTO addon_bindings (addon_id, application_id, env_prefix, username, password)
+		VALUES (?, ?, ?, ?, ?)
+	`
+	result, err := conn(ctx, r.db).ExecContext(ctx, query,
+		binding.AddonID, binding.ApplicationID, binding.EnvPrefix, binding.Username, binding.Password,
+	)
+	if err != nil {
+		return errors.Internal("Failed to create addon binding")
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return errors.Internal("Failed to get addon binding ID")
+	}
+	binding.ID = uint(id)
+
+	return nil
+}
+
+func (r *addonRepository) FindBinding(ctx context.Context, addonID, applicationID uint) (*addon.Binding, error) {
+	query := "SELECT " + bindingColumns + " FROM addon_bindings WHERE addon_id = ? AND application_id = ?"
+
+	binding, err := scanBinding(conn(ctx, r.db).QueryRowContext(ctx, query, addonID, applicationID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, errors.Internal("Failed to get addon binding")
+	}
+
+	return binding, nil
+}
+
+func (r *addonRepository) FindBindingsByAddonID(ctx context.Context, addonID uint) ([]*addon.Binding, error) {
+	query := "SELECT " + bindingColumns + " FROM addon_bindings WHERE addon_id = ? ORDER BY id"
+	return r.findBindings(ctx, query, addonID)
+}
+
+func (r *addonRepository) FindBindingsByApplicationID(ctx context.Context, applicationID uint) ([]*addon.Binding, error) {
+	query := "SELECT " + bindingColumns + " FROM addon_bindings WHERE application_id = ? ORDER BY id"
+	return r.findBindings(ctx, query, applicationID)
+}
+
+func (r *addonRepository) FindBindings(ctx context.Context) ([]*addon.Binding, error) {
+	query := "SELECT " + bindingColumns + " FROM addon_bindings ORDER BY id"
+	return r.findBindings(ctx, query)
+}
+
+func (r *addonRepository) findBindings(ctx context.Context, query string, args ...interface{}) ([]*addon.Binding, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Internal("Failed to get addon bindings")
+	}
+	defer rows.Close()
+
+	var bindings []*addon.Binding
+	for rows.Next() {
+		binding, err := scanBinding(rows)
+		if err != nil {
+			return nil, errors.Internal("Failed to scan addon binding")
+		}
+		bindings = append(bindings, binding)
+	}
+
+	return bindings, nil
+}
+
+func (r *addonRepository) DeleteBinding(ctx context.Context, id uint) error {
+	result, err := conn(ctx, r.db).ExecContext(ctx, "DELETE FROM addon_bindings WHERE id = ?", id)
+	if err != nil {
+		return errors.Internal("Failed to delete addon binding")
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return errors.Internal("Failed to get affected rows")
+	}
+
+	if rows == 0 {
+		return errors.NotFound("Binding not found")
+	}
+
+	return nil
+}
+
+func (r *addonRepository) UpdateBindingPassword(ctx context.Context, id uint, password string) error {
+	_, err := conn(ctx, r.db).ExecContext(ctx, "UPDATE addon_bindings SET password = ? WHERE id = ?", password, id)
+	if err != nil {
+		return errors.Internal("Failed to update addon binding")
+	}
+
+	return nil
+}
+
+func scanBinding(row rowScanner) (*addon.Binding, error) {
+	var binding addon.Binding
+
+	err := row.Scan(
+		&binding.ID, &binding.AddonID, &binding.ApplicationID, &binding.EnvPrefix,
+		&binding.Username, &binding.Password, &binding.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &binding, nil
+}
+
+const addonRevisionColumns = `
+	id, addon_id, number, config, created_by, created_at
+`
+
+func (r *addonRepository) SaveRevision(ctx context.Context, rev *addon.Revision) error {
+	configJSON, _ := json.Marshal(rev.Config)
+
+	query := "SELECT COALESCE(MAX(number), 0) + 1 FROM addon_revisions WHERE addon_id = ? FOR UPDATE"
+	if err := conn(ctx, r.db).QueryRowContext(ctx, query, rev.AddonID).Scan(&rev.Number); err != nil {
+		return errors.Internal("Failed to number revision")
+	}
+
+	query = `
+		INSERT INTO addon_revisions (addon_id, number, config, created_by)
+		VALUES (?, ?, ?, ?)
+	`
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, rev.AddonID, rev.Number, string(configJSON), rev.CreatedBy)
+	if err != nil {
+		return errors.Internal("Failed to create revision")
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return errors.Internal("Failed to get revision ID")
+	}
+	rev.ID = uint(id)
+
+	return nil
+}
+
+func (r *addonRepository) FindRevisions(ctx context.Context, addonID uint, page pagination.Params) ([]*addon.Revision, int, error) {
+	var q listQuery
+	q.where("addon_id = ?", addonID)
+
+	total, err := q.count(ctx, conn(ctx, r.db), "addon_revisions")
+	if err != nil {
+		return nil, 0, errors.Internal("Failed to count revisions")
+	}
+
+	// Revisions are numbered in the order they are created, like their IDs
+	clauses, args := q.page(page, "id", "")
+	rows, err := conn(ctx, r.db).QueryContext(ctx, "SELECT "+addonRevisionColumns+" FROM addon_revisions"+clauses, args...)
+	if err != nil {
+		return nil, 0, errors.Internal("Failed to get revisions")
+	}
+	defer rows.Close()
+
+	var revisions []*addon.Revision
+	for rows.Next() {
+		rev, err := scanAddonRevision(rows)
+		if err != nil {
+			return nil, 0, errors.Internal("Failed to scan revision")
+		}
+		revisions = append(revisions, rev)
+	}
+
+	return revisions, total, nil
+}
+
+func (r *addonRepository) FindRevision(ctx context.Context, addonID uint, number int) (*addon.Revision, error) {
+	query := "SELECT " + addonRevisionColumns + " FROM addon_revisions WHERE addon_id = ? AND number = ?"
+
+	rev, err := scanAddonRevision(conn(ctx, r.db).QueryRowContext(ctx, query, addonID, number))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, errors.Internal("Failed to get revision")
+	}
+
+	return rev, nil
+}
+
+func scanAddonRevision(row rowScanner) (*addon.Revision, error) {
+	var rev addon.Revision
+	var configJSON string
+
+	err := row.Scan(&rev.ID, &rev.AddonID, &rev.Number, &configJSON, &rev.CreatedBy, &rev.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	json.Unmarshal([]byte(configJSON), &rev.Config)
+
+	return &rev, nil
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/team-xquare/deployment-platform/internal/app/application"
 	"github.com/team-xquare/deployment-platform/internal/pkg/utils/errors"
+	"github.com/team-xquare/deployment-platform/internal/pkg/utils/pagination"
 )
 
 type applicationRepository struct {
@@ -31,7 +32,7 @@ func (r *applicationRepository) Save(ctx context.Context, app *application.Appli
 				build_type, build_config, endpoints
 			) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 		`
-		result, err := r.db.ExecContext(ctx, query,
+		result, err := conn(ctx, r.db).ExecContext(ctx, query,
 			app.ProjectID, app.Name, app.Tier,
 			app.GitHubOwner, app.GitHubRepo, app.GitHubBranch, app.GitHubInstallationID, string(triggerPathsJSON),
 			app.BuildType, string(buildConfigJSON), string(endpointsJSON),
@@ -54,7 +55,7 @@ func (r *applicationRepository) Save(ctx context.Context, app *application.Appli
 				build_type = ?, build_config = ?, endpoints = ?, updated_at = CURRENT_TIMESTAMP
 			WHERE id = ?
 		`
-		_, err := r.db.ExecContext(ctx, query,
+		_, err := conn(ctx, r.db).ExecContext(ctx, query,
 			app.Name, app.Tier,
 			app.GitHubOwner, app.GitHubRepo, app.GitHubBranch, app.GitHubInstallationID, string(triggerPathsJSON),
 			app.BuildType, string(buildConfigJSON), string(endpointsJSON),
@@ -72,17 +73,20 @@ func (r *applicationRepository) FindByID(ctx context.Context, id uint) (*applica
 	query := `
 		SELECT id, project_id, name, tier,
 			github_owner, github_repo, github_branch, github_installation_id, github_trigger_paths,
-			build_type, build_config, endpoints, created_at, updated_at
+			build_type, build_config, endpoints, created_at, updated_at,
+			(SELECT hostname FROM application_environments e WHERE e.application_id = applications.id AND e.is_default) AS hostname
 		FROM applications WHERE id = ?
 	`
 
 	var app application.Application
 	var triggerPathsJSON, buildConfigJSON, endpointsJSON string
+	var hostname sql.NullString
 
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, id).Scan(
 		&app.ID, &app.ProjectID, &app.Name, &app.Tier,
 		&app.GitHubOwner, &app.GitHubRepo, &app.GitHubBranch, &app.GitHubInstallationID, &triggerPathsJSON,
 		&app.BuildType, &buildConfigJSON, &endpointsJSON, &app.CreatedAt, &app.UpdatedAt,
+		&hostname,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -95,20 +99,83 @@ func (r *applicationRepository) FindByID(ctx context.Context, id uint) (*applica
 	json.Unmarshal([]byte(triggerPathsJSON), &app.GitHubTriggerPaths)
 	json.Unmarshal([]byte(buildConfigJSON), &app.BuildConfig)
 	json.Unmarshal([]byte(endpointsJSON), &app.Endpoints)
+	app.Hostname = hostname.String
 
 	return &app, nil
 }
 
-func (r *applicationRepository) FindByProjectID(ctx context.Context, projectID uint) ([]*application.Application, error) {
+func (r *applicationRepository) FindByProjectID(ctx context.Context, projectID uint, filter application.ListFilter) ([]*application.Application, int, error) {
+	var q listQuery
+	q.where("project_id = ?", projectID)
+	if filter.Name != "" {
+		q.whereContains(filter.Name, "name")
+	}
+	if filter.Tier != "" {
+		q.where("tier = ?", filter.Tier)
+	}
+	if filter.BuildType != "" {
+		q.where("build_type = ?", filter.BuildType)
+	}
+
+	total, err := q.count(ctx, conn(ctx, r.db), "applications")
+	if err != nil {
+		return nil, 0, errors.Internal("Failed to count applications")
+	}
+
+	clauses, args := q.page(filter.Page, "id", "name")
+	query := `
+		SELECT id, project_id, name, tier,
+			github_owner, github_repo, github_branch, github_installation_id, github_trigger_paths,
+			build_type, build_config, endpoints, created_at, updated_at,
+			(SELECT hostname FROM application_environments e WHERE e.application_id = applications.id AND e.is_default) AS hostname
+		FROM applications` + clauses
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, errors.Internal("Failed to get applications")
+	}
+	defer rows.Close()
+
+	var applications []*application.Application
+	for rows.Next() {
+		var app application.Application
+		var triggerPathsJSON, buildConfigJSON, endpointsJSON string
+		var hostname sql.NullString
+
+		err := rows.Scan(
+			&app.ID, &app.ProjectID, &app.Name, &app.Tier,
+			&app.GitHubOwner, &app.GitHubRepo, &app.GitHubBranch, &app.GitHubInstallationID, &triggerPathsJSON,
+			&app.BuildType, &buildConfigJSON, &endpointsJSON, &app.CreatedAt, &app.UpdatedAt,
+			&hostname,
+		)
+		if err != nil {
+			return nil, 0, errors.Internal("Failed to scan application")
+		}
+
+		// Unmarshal JSON fields
+		json.Unmarshal([]byte(triggerPathsJSON), &app.GitHubTriggerPaths)
+		json.Unmarshal([]byte(buildConfigJSON), &app.BuildConfig)
+		json.Unmarshal([]byte(endpointsJSON), &app.Endpoints)
+		app.Hostname = hostname.String
+
+		applications = append(applications, &app)
+	}
+
+	return applications, total, nil
+}
+
+func (r *applicationRepository) FindByGitHubRepository(ctx context.Context, owner, repo, branch string) ([]*application.Application, error) {
 	query := `
 		SELECT id, project_id, name, tier,
 			github_owner, github_repo, github_branch, github_installation_id, github_trigger_paths,
-			build_type, build_config, endpoints, created_at, updated_at
-		FROM applications WHERE project_id = ?
-		ORDER BY created_at DESC
+			build_type, build_config, endpoints, created_at, updated_at,
+			(SELECT hostname FROM application_environments e WHERE e.application_id = applications.id AND e.is_default) AS hostname
+		FROM applications
+		WHERE github_owner = ? AND github_repo = ?
+			AND id IN (SELECT application_id FROM application_environments WHERE branch = ?)
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, projectID)
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, owner, repo, branch)
 	if err != nil {
 		return nil, errors.Internal("Failed to get applications")
 	}
@@ -118,11 +185,13 @@ func (r *applicationRepository) FindByProjectID(ctx context.Context, projectID u
 	for rows.Next() {
 		var app application.Application
 		var triggerPathsJSON, buildConfigJSON, endpointsJSON string
+		var hostname sql.NullString
 
 		err := rows.Scan(
 			&app.ID, &app.ProjectID, &app.Name, &app.Tier,
 			&app.GitHubOwner, &app.GitHubRepo, &app.GitHubBranch, &app.GitHubInstallationID, &triggerPathsJSON,
 			&app.BuildType, &buildConfigJSON, &endpointsJSON, &app.CreatedAt, &app.UpdatedAt,
+			&hostname,
 		)
 		if err != nil {
 			return nil, errors.Internal("Failed to scan application")
@@ -132,6 +201,7 @@ func (r *applicationRepository) FindByProjectID(ctx context.Context, projectID u
 		json.Unmarshal([]byte(triggerPathsJSON), &app.GitHubTriggerPaths)
 		json.Unmarshal([]byte(buildConfigJSON), &app.BuildConfig)
 		json.Unmarshal([]byte(endpointsJSON), &app.Endpoints)
+		app.Hostname = hostname.String
 
 		applications = append(applications, &app)
 	}
@@ -142,7 +212,7 @@ func (r *applicationRepository) FindByProjectID(ctx context.Context, projectID u
 func (r *applicationRepository) Delete(ctx context.Context, id uint) error {
 	query := "DELETE FROM applications WHERE id = ?"
 
-	result, err := r.db.ExecContext(ctx, query, id)
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, id)
 	if err != nil {
 		return errors.Internal("Failed to delete application")
 	}
@@ -157,4 +227,480 @@ func (r *applicationRepository) Delete(ctx context.Context, id uint) error {
 	}
 
 	return nil
-}
\ No newline at end of file
+}
+
+func (r *applicationRepository) UpdateGitHubRepository(ctx context.Context, previousOwner, previousRepo, owner, repo string) error {
+	query := "UPDATE applications SET github_owner = ?, github_repo = ? WHERE github_owner = ? AND github_repo = ?"
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, owner, repo, previousOwner, previousRepo)
+	if err != nil {
+		return errors.Internal("Failed to update application repository")
+	}
+
+	return nil
+}
+
+func (r *applicationRepository) ClearGitHubRepository(ctx context.Context, owner, repo string) error {
+	query := `
+		UPDATE applications SET github_owner = '', github_repo = '', github_installation_id = ''
+		WHERE github_owner = ? AND github_repo = ?
+	`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, owner, repo)
+	if err != nil {
+		return errors.Internal("Failed to update application repository")
+	}
+
+	return nil
+}
+
+const environmentColumns = `
+	id, application_id, name, branch, tier, endpoints, hostname, is_default, created_at, updated_at
+`
+
+func (r *applicationRepository) SaveEnvironment(ctx context.Context, env *application.Environment) error {
+	endpointsJSON, _ := json.Marshal(env.Endpoints)
+
+	if env.ID == 0 {
+		query := `
+			INSERT INTO application_environments (application_id, name, branch, tier, endpoints, hostname, is_default)
+			VALUES (?, ?, NULLIF(?, ''), ?, ?, NULLIF(?, ''), ?)
+		`
+		result, err := conn(ctx, r.db).ExecContext(ctx, query,
+			env.ApplicationID, env.Name, env.Branch, env.Tier, string(endpointsJSON), env.Hostname, env.IsDefault,
+		)
+		if err != nil {
+			return errors.Internal("Failed to create environment")
+		}
+
+		id, err := result.LastInsertId()
+		if err != nil {
+			return errors.Internal("Failed to get environment ID")
+		}
+		env.ID = uint(id)
+	} else {
+		query := `
+			UPDATE application_environments SET
+				branch = NULLIF(?, ''), tier = ?, endpoints = ?, hostname = NULLIF(?, ''), updated_at = CURRENT_TIMESTAMP
+			WHERE id = ?
+		`
+		_, err := conn(ctx, r.db).ExecContext(ctx, query, env.Branch, env.Tier, string(endpointsJSON), env.Hostname, env.ID)
+		if err != nil {
+			return errors.Internal("Failed to update environment")
+		}
+	}
+
+	return nil
+}
+
+func (r *applicationRepository) FindEnvironments(ctx context.Context, applicationID uint) ([]*application.Environment, error) {
+	query := "SELECT " + environmentColumns + " FROM application_environments WHERE application_id = ? ORDER BY is_default DESC, name"
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, applicationID)
+	if err != nil {
+		return nil, errors.Internal("Failed to get environments")
+	}
+	defer rows.Close()
+
+	var envs []*application.Environment
+	for rows.Next() {
+		env, err := scanEnvironment(rows)
+		if err != nil {
+			return nil, errors.Internal("Failed to scan environment")
+		}
+		envs = append(envs, env)
+	}
+
+	return envs, nil
+}
+
+func (r *applicationRepository) FindEnvironment(ctx context.Context, applicationID uint, name string) (*application.Environment, error) {
+	query := "SELECT " + environmentColumns + " FROM application_environments WHERE application_id = ? AND name = ?"
+
+	env, err := scanEnvironment(conn(ctx, r.db).QueryRowContext(ctx, query, applicationID, name))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, errors.Internal("Failed to get environment")
+	}
+
+	return env, nil
+}
+
+func (r *applicationRepository) DeleteEnvironment(ctx context.Context, id uint) error {
+	result, err := conn(ctx, r.db).ExecContext(ctx, "DELETE FROM application_environments WHERE id = ?", id)
+	if err != nil {
+		return errors.Internal("Failed to delete environment")
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return errors.Internal("Failed to get affected rows")
+	}
+
+	if rows == 0 {
+		return errors.NotFound("Environment not found")
+	}
+
+	return nil
+}
+
+func (r *applicationRepository) FindEnvironmentByHostname(ctx context.Context, hostname string) (*application.Environment, error) {
+	query := "SELECT " + environmentColumns + " FROM application_environments WHERE hostname = ?"
+
+	env, err := scanEnvironment(conn(ctx, r.db).QueryRowContext(ctx, query, hostname))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, errors.Internal("Failed to get environment")
+	}
+
+	return env, nil
+}
+
+func (r *applicationRepository) FindRoute(ctx context.Context, host, path string) (*application.Route, error) {
+	query := "SELECT environment_id, host, path FROM environment_routes WHERE host = ? AND path = ?"
+
+	var route application.Route
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, host, path).Scan(&route.EnvironmentID, &route.Host, &route.Path)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, errors.Internal("Failed to get route")
+	}
+
+	return &route, nil
+}
+
+func (r *applicationRepository) ReplaceRoutes(ctx context.Context, environmentID uint, routes []*application.Route) error {
+	if _, err := conn(ctx, r.db).ExecContext(ctx, "DELETE FROM environment_routes WHERE environment_id = ?", environmentID); err != nil {
+		return errors.Internal("Failed to update routes")
+	}
+
+	for _, route := range routes {
+		query := "INSERT INTO environment_routes (environment_id, host, path) VALUES (?, ?, ?)"
+		if _, err := conn(ctx, r.db).ExecContext(ctx, query, environmentID, route.Host, route.Path); err != nil {
+			return errors.Internal("Failed to reserve route " + route.Host + route.Path)
+		}
+	}
+
+	return nil
+}
+
+func scanEnvironment(row rowScanner) (*application.Environment, error) {
+	var env application.Environment
+	var branch, endpointsJSON, hostname sql.NullString
+
+	err := row.Scan(
+		&env.ID, &env.ApplicationID, &env.Name, &branch, &env.Tier, &endpointsJSON, &hostname, &env.IsDefault,
+		&env.CreatedAt, &env.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	env.Branch = branch.String
+	env.Hostname = hostname.String
+	if endpointsJSON.Valid {
+		json.Unmarshal([]byte(endpointsJSON.String), &env.Endpoints)
+	}
+
+	return &env, nil
+}
+
+const envVarColumns = `
+	id, application_id, environment_id, name, value, secret, created_at, updated_at
+`
+
+func (r *applicationRepository) SaveEnvVar(ctx context.Context, envVar *application.EnvVar) error {
+	if envVar.ID == 0 {
+		query := `
+			INSERT INTO application_env_vars (application_id, environment_id, name, value, secret)
+			VALUES (?, ?, ?, ?, ?)
+		`
+		result, err := conn(ctx, r.db).ExecContext(ctx, query,
+			envVar.ApplicationID, envVar.EnvironmentID, envVar.Name, envVar.Value, envVar.Secret,
+		)
+		if err != nil {
+			return errors.Internal("Failed to create environment variable")
+		}
+
+		id, err := result.LastInsertId()
+		if err != nil {
+			return errors.Internal("Failed to get environment variable ID")
+		}
+		envVar.ID = uint(id)
+	} else {
+		query := `
+			UPDATE application_env_vars SET
+				value = ?, secret = ?, updated_at = CURRENT_TIMESTAMP
+			WHERE id = ?
+		`
+		_, err := conn(ctx, r.db).ExecContext(ctx, query, envVar.Value, envVar.Secret, envVar.ID)
+		if err != nil {
+			return errors.Internal("Failed to update environment variable")
+		}
+	}
+
+	return nil
+}
+
+// FindEnvVars compares the environment scope with <=> so that a nil
+// environmentID matches the application-wide variables.
+func (r *applicationRepository) FindEnvVars(ctx context.Context, applicationID uint, environmentID *uint) ([]*application.EnvVar, error) {
+	query := "SELECT " + envVarColumns + " FROM application_env_vars WHERE application_id = ? AND environment_id <=> ? ORDER BY name"
+	return r.findEnvVars(ctx, query, applicationID, environmentID)
+}
+
+func (r *applicationRepository) FindEnvVar(ctx context.Context, applicationID uint, environmentID *uint, name string) (*application.EnvVar, error) {
+	query := "SELECT " + envVarColumns + " FROM application_env_vars WHERE application_id = ? AND environment_id <=> ? AND name = ?"
+
+	envVar, err := scanEnvVar(conn(ctx, r.db).QueryRowContext(ctx, query, applicationID, environmentID, name))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, errors.Internal("Failed to get environment variable")
+	}
+
+	return envVar, nil
+}
+
+func (r *applicationRepository) DeleteEnvVar(ctx context.Context, id uint) error {
+	result, err := conn(ctx, r.db).ExecContext(ctx, "DELETE FROM application_env_vars WHERE id = ?", id)
+	if err != nil {
+		return errors.Internal("Failed to delete environment variable")
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return errors.Internal("Failed to get affected rows")
+	}
+
+	if rows == 0 {
+		return errors.NotFound("Environment variable not found")
+	}
+
+	return nil
+}
+
+func (r *applicationRepository) FindSecretEnvVars(ctx context.Context) ([]*application.EnvVar, error) {
+	query := "SELECT " + envVarColumns + " FROM application_env_vars WHERE secret = TRUE ORDER BY id"
+	return r.findEnvVars(ctx, query)
+}
+
+func (r *applicationRepository) findEnvVars(ctx context.Context, query string, args ...interface{}) ([]*application.EnvVar, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Internal("Failed to get environment variables")
+	}
+	defer rows.Close()
+
+	var envVars []*application.EnvVar
+	for rows.Next() {
+		envVar, err := scanEnvVar(rows)
+		if err != nil {
+			return nil, errors.Internal("Failed to scan environment variable")
+		}
+		envVars = append(envVars, envVar)
+	}
+
+	return envVars, nil
+}
+
+func scanEnvVar(row rowScanner) (*application.EnvVar, error) {
+	var envVar application.EnvVar
+
+	err := row.Scan(
+		&envVar.ID, &envVar.ApplicationID, &envVar.EnvironmentID, &envVar.Name, &envVar.Value, &envVar.Secret,
+		&envVar.CreatedAt, &envVar.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &envVar, nil
+}
+
+const domainColumns = `
+	id, application_id, environment_id, domain, port, token, status, last_error,
+	checked_at, verified_at, created_at, updated_at
+`
+
+func (r *applicationRepository) SaveDomain(ctx context.Context, d *application.CustomDomain) error {
+	if d.ID == 0 {
+		query := `
+			INSERT INTO custom_domains (application_id, environment_id, domain, port, token, status)
+			VALUES (?, ?, ?, ?, ?, ?)
+		`
+		result, err := conn(ctx, r.db).ExecContext(ctx, query,
+			d.ApplicationID, d.EnvironmentID, d.Domain, d.Port, d.Token, d.Status,
+		)
+		if err != nil {
+			return errors.Internal("Failed to create custom domain")
+		}
+
+		id, err := result.LastInsertId()
+		if err != nil {
+			return errors.Internal("Failed to get custom domain ID")
+		}
+		d.ID = uint(id)
+	} else {
+		query := `
+			UPDATE custom_domains SET
+				status = ?, last_error = NULLIF(?, ''), checked_at = ?, verified_at = ?, updated_at = CURRENT_TIMESTAMP
+			WHERE id = ?
+		`
+		_, err := conn(ctx, r.db).ExecContext(ctx, query, d.Status, d.LastError, d.CheckedAt, d.VerifiedAt, d.ID)
+		// unique_verified_domain lets a domain be verified for a single attachment
+		if isDuplicateEntry(err) {
+			return errors.BadRequest("Domain " + d.Domain + " is already verified by another application")
+		}
+		if err != nil {
+			return errors.Internal("Failed to update custom domain")
+		}
+	}
+
+	return nil
+}
+
+func (r *applicationRepository) FindDomains(ctx context.Context, applicationID uint) ([]*application.CustomDomain, error) {
+	query := "SELECT " + domainColumns + " FROM custom_domains WHERE application_id = ? ORDER BY domain"
+	return r.findDomains(ctx, query, applicationID)
+}
+
+func (r *applicationRepository) FindDomainsByName(ctx context.Context, domain string) ([]*application.CustomDomain, error) {
+	query := "SELECT " + domainColumns + " FROM custom_domains WHERE domain = ? ORDER BY id"
+	return r.findDomains(ctx, query, domain)
+}
+
+func (r *applicationRepository) FindVerifiedDomains(ctx context.Context, environmentID uint) ([]*application.CustomDomain, error) {
+	query := "SELECT " + domainColumns + " FROM custom_domains WHERE environment_id = ? AND status = ? ORDER BY domain"
+	return r.findDomains(ctx, query, environmentID, application.DomainVerified)
+}
+
+func (r *applicationRepository) DeleteDomain(ctx context.Context, id uint) error {
+	_, err := conn(ctx, r.db).ExecContext(ctx, "DELETE FROM custom_domains WHERE id = ?", id)
+	if err != nil {
+		return errors.Internal("Failed to delete custom domain")
+	}
+	return nil
+}
+
+func (r *applicationRepository) findDomains(ctx context.Context, query string, args ...interface{}) ([]*application.CustomDomain, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Internal("Failed to get custom domains")
+	}
+	defer rows.Close()
+
+	var domains []*application.CustomDomain
+	for rows.Next() {
+		var d application.CustomDomain
+		var lastError sql.NullString
+		err := rows.Scan(
+			&d.ID, &d.ApplicationID, &d.EnvironmentID, &d.Domain, &d.Port, &d.Token, &d.Status, &lastError,
+			&d.CheckedAt, &d.VerifiedAt, &d.CreatedAt, &d.UpdatedAt,
+		)
+		if err != nil {
+			return nil, errors.Internal("Failed to scan custom domain")
+		}
+		d.LastError = lastError.String
+		domains = append(domains, &d)
+	}
+
+	return domains, nil
+}
+
+const revisionColumns = `
+	id, application_id, number, config, rollback_of, created_by, created_at
+`
+
+func (r *applicationRepository) SaveRevision(ctx context.Context, rev *application.Revision) error {
+	configJSON, _ := json.Marshal(rev.Config)
+
+	query := "SELECT COALESCE(MAX(number), 0) + 1 FROM application_revisions WHERE application_id = ? FOR UPDATE"
+	if err := conn(ctx, r.db).QueryRowContext(ctx, query, rev.ApplicationID).Scan(&rev.Number); err != nil {
+		return errors.Internal("Failed to number revision")
+	}
+
+	query = `
+		INSERT INTO application_revisions (application_id, number, config, rollback_of, created_by)
+		VALUES (?, ?, ?, ?, ?)
+	`
+	result, err := conn(ctx, r.db).ExecContext(ctx, query,
+		rev.ApplicationID, rev.Number, string(configJSON), rev.RollbackOf, rev.CreatedBy,
+	)
+	if err != nil {
+		return errors.Internal("Failed to create revision")
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return errors.Internal("Failed to get revision ID")
+	}
+	rev.ID = uint(id)
+
+	return nil
+}
+
+func (r *applicationRepository) FindRevisions(ctx context.Context, applicationID uint, page pagination.Params) ([]*application.Revision, int, error) {
+	var q listQuery
+	q.where("application_id = ?", applicationID)
+
+	total, err := q.count(ctx, conn(ctx, r.db), "application_revisions")
+	if err != nil {
+		return nil, 0, errors.Internal("Failed to count revisions")
+	}
+
+	// Revisions are numbered in the order they are created, like their IDs
+	clauses, args := q.page(page, "id", "")
+	rows, err := conn(ctx, r.db).QueryContext(ctx, "SELECT "+revisionColumns+" FROM application_revisions"+clauses, args...)
+	if err != nil {
+		return nil, 0, errors.Internal("Failed to get revisions")
+	}
+	defer rows.Close()
+
+	var revisions []*application.Revision
+	for rows.Next() {
+		rev, err := scanRevision(rows)
+		if err != nil {
+			return nil, 0, errors.Internal("Failed to scan revision")
+		}
+		revisions = append(revisions, rev)
+	}
+
+	return revisions, total, nil
+}
+
+func (r *applicationRepository) FindRevision(ctx context.Context, applicationID uint, number int) (*application.Revision, error) {
+	query := "SELECT " + revisionColumns + " FROM application_revisions WHERE application_id = ? AND number = ?"
+
+	rev, err := scanRevision(conn(ctx, r.db).QueryRowContext(ctx, query, applicationID, number))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, errors.Internal("Failed to get revision")
+	}
+
+	return rev, nil
+}
+
+func scanRevision(row rowScanner) (*application.Revision, error) {
+	var rev application.Revision
+	var configJSON string
+
+	err := row.Scan(
+		&rev.ID, &rev.ApplicationID, &rev.Number, &configJSON, &rev.RollbackOf, &rev.CreatedBy, &rev.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	json.Unmarshal([]byte(configJSON), &rev.Config)
+
+	return &rev, nil
+}
@@ -0,0 +1,130 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/team-xquare/deployment-platform/internal/app/audit"
+	"github.com/team-xquare/deployment-platform/internal/pkg/utils/errors"
+)
+
+type auditRepository struct {
+	db *sql.DB
+}
+
+func NewAuditRepository(db *sql.DB) audit.Repository {
+	return &auditRepository{db: db}
+}
+
+const auditEventColumns = `
+	id, actor_id, actor_email, action, resource_type, resource_id, project_id,
+	request_id, client_ip, before_snapshot, after_snapshot, created_at
+`
+
+func (r *auditRepository) Save(ctx context.Context, e *audit.Event) error {
+	query := `
+		INSERT INTO audit_events (
+			actor_id, actor_email, action, resource_type, resource_id, project_id,
+			request_id, client_ip, before_snapshot, after_snapshot
+		) VALUES (?, NULLIF(?, ''), ?, ?, NULLIF(?, ''), ?, NULLIF(?, ''), NULLIF(?, ''), ?, ?)
+	`
+	result, err := conn(ctx, r.db).ExecContext(ctx, query,
+		e.ActorID, e.ActorEmail, e.Action, e.ResourceType, e.ResourceID, e.ProjectID,
+		e.RequestID, e.ClientIP, snapshotJSON(e.Before), snapshotJSON(e.After),
+	)
+	if err != nil {
+		return errors.Internal("Failed to save audit event")
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return errors.Internal("Failed to get audit event ID")
+	}
+	e.ID = uint(id)
+
+	return nil
+}
+
+func (r *auditRepository) Find(ctx context.Context, filter audit.Filter) ([]*audit.Event, int, error) {
+	var q listQuery
+	if filter.ProjectID != nil {
+		q.where("project_id = ?", *filter.ProjectID)
+	}
+	if filter.ActorID != nil {
+		q.where("actor_id = ?", *filter.ActorID)
+	}
+	if filter.Action != "" {
+		q.where("action = ?", filter.Action)
+	}
+	if filter.ResourceType != "" {
+		q.where("resource_type = ?", filter.ResourceType)
+	}
+	if filter.ResourceID != "" {
+		q.where("resource_id = ?", filter.ResourceID)
+	}
+	if filter.Since != nil {
+		q.where("created_at >= ?", *filter.Since)
+	}
+	if filter.Until != nil {
+		q.where("created_at < ?", *filter.Until)
+	}
+
+	total, err := q.count(ctx, conn(ctx, r.db), "audit_events")
+	if err != nil {
+		return nil, 0, errors.Internal("Failed to count audit events")
+	}
+
+	clauses, args := q.page(filter.Page, "id", "")
+	rows, err := conn(ctx, r.db).QueryContext(ctx, "SELECT "+auditEventColumns+" FROM audit_events"+clauses, args...)
+	if err != nil {
+		return nil, 0, errors.Internal("Failed to get audit events")
+	}
+	defer rows.Close()
+
+	var events []*audit.Event
+	for rows.Next() {
+		e, err := scanAuditEvent(rows)
+		if err != nil {
+			return nil, 0, errors.Internal("Failed to scan audit event")
+		}
+		events = append(events, e)
+	}
+
+	return events, total, nil
+}
+
+// snapshotJSON stores a missing snapshot as NULL rather than JSON null.
+func snapshotJSON(snapshot interface{}) interface{} {
+	if snapshot == nil {
+		return nil
+	}
+	data, _ := json.Marshal(snapshot)
+	return string(data)
+}
+
+func scanAuditEvent(row rowScanner) (*audit.Event, error) {
+	var e audit.Event
+	var actorEmail, resourceID, requestID, clientIP, before, after sql.NullString
+
+	err := row.Scan(
+		&e.ID, &e.ActorID, &actorEmail, &e.Action, &e.ResourceType, &resourceID, &e.ProjectID,
+		&requestID, &clientIP, &before, &after, &e.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	e.ActorEmail = actorEmail.String
+	e.ResourceID = resourceID.String
+	e.RequestID = requestID.String
+	e.ClientIP = clientIP.String
+	if before.Valid {
+		json.Unmarshal([]byte(before.String), &e.Before)
+	}
+	if after.Valid {
+		json.Unmarshal([]byte(after.String), &e.After)
+	}
+
+	return &e, nil
+}
@@ -0,0 +1,181 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/team-xquare/deployment-platform/internal/app/deployment"
+	"github.com/team-xquare/deployment-platform/internal/pkg/utils/errors"
+	"github.com/team-xquare/deployment-platform/internal/pkg/utils/pagination"
+)
+
+type deploymentRepository struct {
+	db *sql.DB
+}
+
+func NewDeploymentRepository(db *sql.DB) deployment.Repository {
+	return &deploymentRepository{db: db}
+}
+
+const deploymentColumns = `
+	id, project_id, application_id, application_name, environment_id, environment_name,
+	addon_id, addon_name, action, spec, commit_sha, correlation_id, target_owner, target_repo, run_url, requested_by,
+	status, error_message, dispatched_at, completed_at, created_at, updated_at
+`
+
+func (r *deploymentRepository) Save(ctx context.Context, d *deployment.Deployment) error {
+	specJSON, _ := json.Marshal(d.Spec)
+
+	query := `
+		INSERT INTO deployments (
+			project_id, application_id, application_name, environment_id, environment_name,
+			addon_id, addon_name, action, spec, commit_sha, correlation_id, target_owner, target_repo, requested_by, status
+		) VALUES (?, ?, NULLIF(?, ''), ?, NULLIF(?, ''), ?, NULLIF(?, ''), ?, ?, NULLIF(?, ''), NULLIF(?, ''), NULLIF(?, ''), NULLIF(?, ''), ?, ?)
+	`
+	result, err := conn(ctx, r.db).ExecContext(ctx, query,
+		d.ProjectID, d.ApplicationID, d.ApplicationName, d.EnvironmentID, d.EnvironmentName,
+		d.AddonID, d.AddonName, d.Action, string(specJSON),
+		d.CommitSHA, d.CorrelationID, d.TargetOwner, d.TargetRepo, d.RequestedBy, d.Status,
+	)
+	if err != nil {
+		return errors.Internal("Failed to create deployment")
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return errors.Internal("Failed to get deployment ID")
+	}
+	d.ID = uint(id)
+
+	return nil
+}
+
+func (r *deploymentRepository) FindByID(ctx context.Context, id uint) (*deployment.Deployment, error) {
+	query := "SELECT " + deploymentColumns + " FROM deployments WHERE id = ?"
+
+	d, err := scanDeployment(conn(ctx, r.db).QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.NotFound("Deployment not found")
+		}
+		return nil, errors.Internal("Failed to get deployment")
+	}
+
+	return d, nil
+}
+
+func (r *deploymentRepository) FindByApplicationID(ctx context.Context, applicationID uint, page pagination.Params) ([]*deployment.Deployment, int, error) {
+	return r.findBy(ctx, "application_id", applicationID, page)
+}
+
+func (r *deploymentRepository) FindByEnvironmentID(ctx context.Context, environmentID uint, page pagination.Params) ([]*deployment.Deployment, int, error) {
+	return r.findBy(ctx, "environment_id", environmentID, page)
+}
+
+func (r *deploymentRepository) FindByAddonID(ctx context.Context, addonID uint, page pagination.Params) ([]*deployment.Deployment, int, error) {
+	return r.findBy(ctx, "addon_id", addonID, page)
+}
+
+// findBy lists one page of the deployments whose column holds id.
+func (r *deploymentRepository) findBy(ctx context.Context, column string, id uint, page pagination.Params) ([]*deployment.Deployment, int, error) {
+	var q listQuery
+	q.where(column+" = ?", id)
+
+	total, err := q.count(ctx, conn(ctx, r.db), "deployments")
+	if err != nil {
+		return nil, 0, errors.Internal("Failed to count deployments")
+	}
+
+	clauses, args := q.page(page, "id", "")
+	rows, err := conn(ctx, r.db).QueryContext(ctx, "SELECT "+deploymentColumns+" FROM deployments"+clauses, args...)
+	if err != nil {
+		return nil, 0, errors.Internal("Failed to get deployments")
+	}
+	defer rows.Close()
+
+	var deployments []*deployment.Deployment
+	for rows.Next() {
+		d, err := scanDeployment(rows)
+		if err != nil {
+			return nil, 0, errors.Internal("Failed to scan deployment")
+		}
+		deployments = append(deployments, d)
+	}
+
+	return deployments, total, nil
+}
+
+func (r *deploymentRepository) FindByCorrelationID(ctx context.Context, correlationID string) (*deployment.Deployment, error) {
+	query := "SELECT " + deploymentColumns + " FROM deployments WHERE correlation_id = ?"
+
+	d, err := scanDeployment(conn(ctx, r.db).QueryRowContext(ctx, query, correlationID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, errors.Internal("Failed to get deployment")
+	}
+
+	return d, nil
+}
+
+func (r *deploymentRepository) UpdateStatus(ctx context.Context, id uint, status, errorMessage string) error {
+	// dispatched_at and completed_at are stamped the first time a deployment reaches those states
+	// A late delivery report must not undo a result already reported by the workflow
+	query := `
+		UPDATE deployments SET
+			status = ?,
+			error_message = NULLIF(?, ''),
+			dispatched_at = IF(? = 'dispatched' AND dispatched_at IS NULL, CURRENT_TIMESTAMP, dispatched_at),
+			completed_at = IF(? IN ('failed', 'succeeded'), CURRENT_TIMESTAMP, completed_at),
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = ? AND NOT (? = 'dispatched' AND status IN ('failed', 'succeeded'))
+	`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, status, errorMessage, status, status, id, status)
+	if err != nil {
+		return errors.Internal("Failed to update deployment status")
+	}
+
+	return nil
+}
+
+func scanDeployment(row rowScanner) (*deployment.Deployment, error) {
+	var d deployment.Deployment
+	var applicationName, environmentName, addonName, specJSON, commitSHA, correlationID, targetOwner, targetRepo, runURL, errorMessage sql.NullString
+
+	err := row.Scan(
+		&d.ID, &d.ProjectID, &d.ApplicationID, &applicationName, &d.EnvironmentID, &environmentName,
+		&d.AddonID, &addonName, &d.Action, &specJSON, &commitSHA, &correlationID, &targetOwner, &targetRepo, &runURL, &d.RequestedBy,
+		&d.Status, &errorMessage, &d.DispatchedAt, &d.CompletedAt, &d.CreatedAt, &d.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if specJSON.Valid {
+		json.Unmarshal([]byte(specJSON.String), &d.Spec)
+	}
+	d.ApplicationName = applicationName.String
+	d.EnvironmentName = environmentName.String
+	d.AddonName = addonName.String
+	d.CommitSHA = commitSHA.String
+	d.CorrelationID = correlationID.String
+	d.TargetOwner = targetOwner.String
+	d.TargetRepo = targetRepo.String
+	d.RunURL = runURL.String
+	d.ErrorMessage = errorMessage.String
+
+	return &d, nil
+}
+
+func (r *deploymentRepository) UpdateRunURL(ctx context.Context, id uint, runURL string) error {
+	query := "UPDATE deployments SET run_url = ? WHERE id = ?"
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, runURL, id)
+	if err != nil {
+		return errors.Internal("Failed to update deployment")
+	}
+
+	return nil
+}
@@ -3,6 +3,7 @@ package mysql
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/team-xquare/deployment-platform/internal/app/github"
 	"github.com/team-xquare/deployment-platform/internal/pkg/utils/errors"
@@ -50,7 +51,7 @@ func (r *githubRepository) SaveInstallation(ctx context.Context, installation *g
 
 func (r *githubRepository) FindByInstallationID(ctx context.Context, installationID string) (*github.Installation, error) {
 	query := `
-        SELECT id, installation_id, account_login, account_type, permissions, created_at, updated_at
+        SELECT id, installation_id, account_login, account_type, permissions, suspended_at, created_at, updated_at
         FROM github_installations WHERE installation_id = ?
     `
 
@@ -61,6 +62,7 @@ func (r *githubRepository) FindByInstallationID(ctx context.Context, installatio
 		&installation.AccountLogin,
 		&installation.AccountType,
 		&installation.Permissions,
+		&installation.SuspendedAt,
 		&installation.CreatedAt,
 		&installation.UpdatedAt,
 	)
@@ -76,7 +78,7 @@ func (r *githubRepository) FindByInstallationID(ctx context.Context, installatio
 
 func (r *githubRepository) FindByUserID(ctx context.Context, userID uint) ([]*github.Installation, error) {
 	query := `
-        SELECT gi.id, gi.installation_id, gi.account_login, gi.account_type, gi.permissions, gi.created_at, gi.updated_at
+        SELECT gi.id, gi.installation_id, gi.account_login, gi.account_type, gi.permissions, gi.suspended_at, gi.created_at, gi.updated_at
         FROM github_installations gi
         INNER JOIN user_github_installations ugi ON gi.installation_id = ugi.installation_id
         WHERE ugi.user_id = ?
@@ -97,6 +99,7 @@ func (r *githubRepository) FindByUserID(ctx context.Context, userID uint) ([]*gi
 			&installation.AccountLogin,
 			&installation.AccountType,
 			&installation.Permissions,
+			&installation.SuspendedAt,
 			&installation.CreatedAt,
 			&installation.UpdatedAt,
 		)
@@ -123,6 +126,11 @@ func (r *githubRepository) DeleteByInstallationID(ctx context.Context, installat
 		return errors.Internal("Failed to delete GitHub installation user links")
 	}
 
+	_, err = tx.ExecContext(ctx, "DELETE FROM github_installation_repositories WHERE installation_id = ?", installationID)
+	if err != nil {
+		return errors.Internal("Failed to delete GitHub installation repositories")
+	}
+
 	// Delete installation
 	result, err := tx.ExecContext(ctx, "DELETE FROM github_installations WHERE installation_id = ?", installationID)
 	if err != nil {
@@ -174,3 +182,274 @@ func (r *githubRepository) IsUserLinkedToInstallation(ctx context.Context, userI
 
 	return true, nil
 }
+
+func (r *githubRepository) SetInstallationSuspended(ctx context.Context, installationID string, suspended bool) error {
+	query := "UPDATE github_installations SET suspended_at = NULL WHERE installation_id = ?"
+	if suspended {
+		query = "UPDATE github_installations SET suspended_at = CURRENT_TIMESTAMP WHERE installation_id = ?"
+	}
+
+	_, err := r.db.ExecContext(ctx, query, installationID)
+	if err != nil {
+		return errors.Internal("Failed to update GitHub installation")
+	}
+
+	return nil
+}
+
+func (r *githubRepository) UpdateInstallationPermissions(ctx context.Context, installationID, permissions string) error {
+	query := "UPDATE github_installations SET permissions = ? WHERE installation_id = ?"
+
+	_, err := r.db.ExecContext(ctx, query, permissions, installationID)
+	if err != nil {
+		return errors.Internal("Failed to update GitHub installation")
+	}
+
+	return nil
+}
+
+func (r *githubRepository) SaveInstallationRepositories(ctx context.Context, repositories []*github.InstallationRepository) error {
+	query := `
+        INSERT INTO github_installation_repositories (installation_id, repository_id, full_name, private)
+        VALUES (?, ?, ?, ?)
+        ON DUPLICATE KEY UPDATE
+        full_name = VALUES(full_name),
+        private = VALUES(private),
+        updated_at = CURRENT_TIMESTAMP
+    `
+
+	for _, repository := range repositories {
+		_, err := r.db.ExecContext(ctx, query,
+			repository.InstallationID,
+			repository.RepositoryID,
+			repository.FullName,
+			repository.Private,
+		)
+		if err != nil {
+			return errors.Internal("Failed to save GitHub installation repository")
+		}
+	}
+
+	return nil
+}
+
+func (r *githubRepository) HasInstallationRepository(ctx context.Context, installationID, fullName string) (bool, error) {
+	query := "SELECT 1 FROM github_installation_repositories WHERE installation_id = ? AND full_name = ?"
+
+	var exists int
+	err := r.db.QueryRowContext(ctx, query, installationID, fullName).Scan(&exists)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		return false, errors.Internal("Failed to check GitHub installation repository")
+	}
+
+	return true, nil
+}
+
+func (r *githubRepository) DeleteInstallationRepositories(ctx context.Context, installationID string, repositoryIDs []int64) error {
+	query := "DELETE FROM github_installation_repositories WHERE installation_id = ? AND repository_id = ?"
+
+	for _, repositoryID := range repositoryIDs {
+		_, err := r.db.ExecContext(ctx, query, installationID, repositoryID)
+		if err != nil {
+			return errors.Internal("Failed to delete GitHub installation repository")
+		}
+	}
+
+	return nil
+}
+
+func (r *githubRepository) RenameRepository(ctx context.Context, repositoryID int64, fullName string) error {
+	query := "UPDATE github_installation_repositories SET full_name = ? WHERE repository_id = ?"
+
+	_, err := r.db.ExecContext(ctx, query, fullName, repositoryID)
+	if err != nil {
+		return errors.Internal("Failed to rename GitHub repository")
+	}
+
+	return nil
+}
+
+func (r *githubRepository) DeleteRepository(ctx context.Context, repositoryID int64) error {
+	_, err := r.db.ExecContext(ctx, "DELETE FROM github_installation_repositories WHERE repository_id = ?", repositoryID)
+	if err != nil {
+		return errors.Internal("Failed to delete GitHub repository")
+	}
+
+	return nil
+}
+
+const webhookDeliveryColumns = `
+	id, delivery_id, event_type, payload, signature_valid, status, error_message,
+	duration_ms, attempts, processed_at, created_at, updated_at
+`
+
+func (r *githubRepository) CreateDelivery(ctx context.Context, delivery *github.WebhookDelivery) (bool, error) {
+	query := `
+        INSERT IGNORE INTO webhook_deliveries (delivery_id, event_type, payload, signature_valid, status)
+        VALUES (?, ?, ?, ?, ?)
+    `
+
+	result, err := r.db.ExecContext(ctx, query,
+		delivery.DeliveryID,
+		delivery.EventType,
+		string(delivery.Payload),
+		delivery.SignatureValid,
+		delivery.Status,
+	)
+	if err != nil {
+		return false, errors.Internal("Failed to save webhook delivery")
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, errors.Internal("Failed to get affected rows")
+	}
+	if rows == 0 {
+		return false, nil
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return false, errors.Internal("Failed to get webhook delivery ID")
+	}
+	delivery.ID = uint(id)
+
+	return true, nil
+}
+
+func (r *githubRepository) FindDeliveryByID(ctx context.Context, id uint) (*github.WebhookDelivery, error) {
+	query := "SELECT " + webhookDeliveryColumns + " FROM webhook_deliveries WHERE id = ?"
+
+	delivery, err := scanWebhookDelivery(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.NotFound("Webhook delivery not found")
+		}
+		return nil, errors.Internal("Failed to get webhook delivery")
+	}
+
+	return delivery, nil
+}
+
+func (r *githubRepository) FindDeliveryByDeliveryID(ctx context.Context, deliveryID string) (*github.WebhookDelivery, error) {
+	query := "SELECT " + webhookDeliveryColumns + " FROM webhook_deliveries WHERE delivery_id = ?"
+
+	delivery, err := scanWebhookDelivery(r.db.QueryRowContext(ctx, query, deliveryID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.NotFound("Webhook delivery not found")
+		}
+		return nil, errors.Internal("Failed to get webhook delivery")
+	}
+
+	return delivery, nil
+}
+
+func (r *githubRepository) FindDeliveries(ctx context.Context, status string, limit int) ([]*github.WebhookDelivery, error) {
+	query := "SELECT " + webhookDeliveryColumns + " FROM webhook_deliveries"
+	args := []interface{}{}
+	if status != "" {
+		query += " WHERE status = ?"
+		args = append(args, status)
+	}
+	query += " ORDER BY id DESC LIMIT ?"
+	args = append(args, limit)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Internal("Failed to get webhook deliveries")
+	}
+	defer rows.Close()
+
+	var deliveries []*github.WebhookDelivery
+	for rows.Next() {
+		delivery, err := scanWebhookDelivery(rows)
+		if err != nil {
+			return nil, errors.Internal("Failed to scan webhook delivery")
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	return deliveries, nil
+}
+
+func (r *githubRepository) RetryDelivery(ctx context.Context, id uint, staleAfter time.Duration) (bool, error) {
+	query := `
+        UPDATE webhook_deliveries SET status = 'processing', updated_at = CURRENT_TIMESTAMP
+        WHERE id = ? AND (
+            status IN ('failed', 'rejected')
+            OR (status = 'processing' AND updated_at < CURRENT_TIMESTAMP - INTERVAL ? SECOND)
+        )
+    `
+
+	result, err := r.db.ExecContext(ctx, query, id, int64(staleAfter.Seconds()))
+	if err != nil {
+		return false, errors.Internal("Failed to update webhook delivery")
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, errors.Internal("Failed to get affected rows")
+	}
+
+	return rows > 0, nil
+}
+
+func (r *githubRepository) UpdateDeliveryResult(ctx context.Context, delivery *github.WebhookDelivery) error {
+	query := `
+        UPDATE webhook_deliveries SET
+        signature_valid = ?,
+        status = ?,
+        error_message = NULLIF(?, ''),
+        duration_ms = ?,
+        attempts = ?,
+        processed_at = CURRENT_TIMESTAMP
+        WHERE id = ?
+    `
+
+	_, err := r.db.ExecContext(ctx, query,
+		delivery.SignatureValid,
+		delivery.Status,
+		delivery.ErrorMessage,
+		delivery.DurationMs,
+		delivery.Attempts,
+		delivery.ID,
+	)
+	if err != nil {
+		return errors.Internal("Failed to update webhook delivery")
+	}
+
+	return nil
+}
+
+func scanWebhookDelivery(row rowScanner) (*github.WebhookDelivery, error) {
+	var delivery github.WebhookDelivery
+	var payload []byte
+	var errorMessage sql.NullString
+
+	err := row.Scan(
+		&delivery.ID,
+		&delivery.DeliveryID,
+		&delivery.EventType,
+		&payload,
+		&delivery.SignatureValid,
+		&delivery.Status,
+		&errorMessage,
+		&delivery.DurationMs,
+		&delivery.Attempts,
+		&delivery.ProcessedAt,
+		&delivery.CreatedAt,
+		&delivery.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	delivery.Payload = payload
+	delivery.ErrorMessage = errorMessage.String
+
+	return &delivery, nil
+}
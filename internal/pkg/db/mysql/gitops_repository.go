@@ -0,0 +1,194 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/team-xquare/deployment-platform/internal/app/gitops"
+	"github.com/team-xquare/deployment-platform/internal/pkg/utils/errors"
+)
+
+type gitopsRepository struct {
+	db *sql.DB
+}
+
+func NewGitOpsRepository(db *sql.DB) gitops.Repository {
+	return &gitopsRepository{db: db}
+}
+
+const platformTargetColumns = `
+	id, NULL, resource_type, owner, repo, event_type, installation_id, created_at, updated_at
+`
+
+const projectTargetColumns = `
+	id, project_id, resource_type, owner, repo, event_type, installation_id, created_at, updated_at
+`
+
+func (r *gitopsRepository) FindPlatformTarget(ctx context.Context, resourceType string) (*gitops.Target, error) {
+	query := "SELECT " + platformTargetColumns + " FROM platform_gitops_targets WHERE resource_type = ?"
+
+	target, err := scanTarget(r.db.QueryRowContext(ctx, query, resourceType))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, errors.Internal("Failed to get GitOps target")
+	}
+
+	return target, nil
+}
+
+func (r *gitopsRepository) FindProjectTarget(ctx context.Context, projectID uint, resourceType string) (*gitops.Target, error) {
+	query := "SELECT " + projectTargetColumns + " FROM project_gitops_targets WHERE project_id = ? AND resource_type = ?"
+
+	target, err := scanTarget(r.db.QueryRowContext(ctx, query, projectID, resourceType))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, errors.Internal("Failed to get GitOps target")
+	}
+
+	return target, nil
+}
+
+func (r *gitopsRepository) FindPlatformTargets(ctx context.Context) ([]*gitops.Target, error) {
+	query := "SELECT " + platformTargetColumns + " FROM platform_gitops_targets ORDER BY resource_type"
+	return r.findTargets(ctx, query)
+}
+
+func (r *gitopsRepository) FindProjectTargets(ctx context.Context, projectID uint) ([]*gitops.Target, error) {
+	query := "SELECT " + projectTargetColumns + " FROM project_gitops_targets WHERE project_id = ? ORDER BY resource_type"
+	return r.findTargets(ctx, query, projectID)
+}
+
+func (r *gitopsRepository) SavePlatformTarget(ctx context.Context, target *gitops.Target) error {
+	query := `
+		INSERT INTO platform_gitops_targets (resource_type, owner, repo, event_type, installation_id)
+		VALUES (?, ?, ?, ?, NULLIF(?, ''))
+		ON DUPLICATE KEY UPDATE
+			owner = VALUES(owner),
+			repo = VALUES(repo),
+			event_type = VALUES(event_type),
+			installation_id = VALUES(installation_id)
+	`
+	_, err := r.db.ExecContext(ctx, query,
+		target.ResourceType, target.Owner, target.Repo, target.EventType, target.InstallationID,
+	)
+	if err != nil {
+		return errors.Internal("Failed to save GitOps target")
+	}
+
+	saved, err := r.FindPlatformTarget(ctx, target.ResourceType)
+	if err != nil {
+		return err
+	}
+	*target = *saved
+
+	return nil
+}
+
+func (r *gitopsRepository) SaveProjectTarget(ctx context.Context, target *gitops.Target) error {
+	query := `
+		INSERT INTO project_gitops_targets (project_id, resource_type, owner, repo, event_type, installation_id)
+		VALUES (?, ?, ?, ?, ?, NULLIF(?, ''))
+		ON DUPLICATE KEY UPDATE
+			owner = VALUES(owner),
+			repo = VALUES(repo),
+			event_type = VALUES(event_type),
+			installation_id = VALUES(installation_id)
+	`
+	_, err := r.db.ExecContext(ctx, query,
+		*target.ProjectID, target.ResourceType, target.Owner, target.Repo, target.EventType, target.InstallationID,
+	)
+	if err != nil {
+		return errors.Internal("Failed to save GitOps target")
+	}
+
+	saved, err := r.FindProjectTarget(ctx, *target.ProjectID, target.ResourceType)
+	if err != nil {
+		return err
+	}
+	*target = *saved
+
+	return nil
+}
+
+func (r *gitopsRepository) DeletePlatformTarget(ctx context.Context, resourceType string) error {
+	result, err := r.db.ExecContext(ctx, "DELETE FROM platform_gitops_targets WHERE resource_type = ?", resourceType)
+	if err != nil {
+		return errors.Internal("Failed to delete GitOps target")
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return errors.Internal("Failed to get affected rows")
+	}
+
+	if rows == 0 {
+		return errors.NotFound("GitOps target not found")
+	}
+
+	return nil
+}
+
+func (r *gitopsRepository) DeleteProjectTarget(ctx context.Context, projectID uint, resourceType string) error {
+	result, err := r.db.ExecContext(ctx,
+		"DELETE FROM project_gitops_targets WHERE project_id = ? AND resource_type = ?", projectID, resourceType,
+	)
+	if err != nil {
+		return errors.Internal("Failed to delete GitOps target")
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return errors.Internal("Failed to get affected rows")
+	}
+
+	if rows == 0 {
+		return errors.NotFound("GitOps target not found")
+	}
+
+	return nil
+}
+
+func (r *gitopsRepository) findTargets(ctx context.Context, query string, args ...interface{}) ([]*gitops.Target, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Internal("Failed to get GitOps targets")
+	}
+	defer rows.Close()
+
+	var targets []*gitops.Target
+	for rows.Next() {
+		target, err := scanTarget(rows)
+		if err != nil {
+			return nil, errors.Internal("Failed to scan GitOps target")
+		}
+		targets = append(targets, target)
+	}
+
+	return targets, nil
+}
+
+func scanTarget(row rowScanner) (*gitops.Target, error) {
+	var target gitops.Target
+	var projectID sql.NullInt64
+	var installationID sql.NullString
+
+	err := row.Scan(
+		&target.ID, &projectID, &target.ResourceType, &target.Owner, &target.Repo,
+		&target.EventType, &installationID, &target.CreatedAt, &target.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if projectID.Valid {
+		id := uint(projectID.Int64)
+		target.ProjectID = &id
+	}
+	target.InstallationID = installationID.String
+
+	return &target, nil
+}
@@ -0,0 +1,82 @@
+package mysql
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/team-xquare/deployment-platform/internal/pkg/utils/pagination"
+)
+
+// listQuery collects the filter conditions of a paginated list so the same
+// conditions select both the total count and the page.
+type listQuery struct {
+	conditions []string
+	args       []interface{}
+}
+
+func (q *listQuery) where(condition string, args ...interface{}) {
+	q.conditions = append(q.conditions, condition)
+	q.args = append(q.args, args...)
+}
+
+// whereContains matches rows where any of the columns contains s.
+func (q *listQuery) whereContains(s string, columns ...string) {
+	pattern := "%" + strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s) + "%"
+
+	matches := make([]string, len(columns))
+	args := make([]interface{}, len(columns))
+	for i, column := range columns {
+		matches[i] = column + " LIKE ?"
+		args[i] = pattern
+	}
+	q.where("("+strings.Join(matches, " OR ")+")", args...)
+}
+
+func (q *listQuery) whereClause() string {
+	if len(q.conditions) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(q.conditions, " AND ")
+}
+
+// count returns how many rows of from match the conditions.
+func (q *listQuery) count(ctx context.Context, db executor, from string) (int, error) {
+	var total int
+	err := db.QueryRowContext(ctx, "SELECT COUNT(*) FROM "+from+q.whereClause(), q.args...).Scan(&total)
+	return total, err
+}
+
+// page returns the WHERE, ORDER BY and LIMIT clauses selecting one page, with
+// their arguments. It fetches one row more than the page holds so
+// pagination.Paginate can tell whether another page follows. nameColumn is
+// empty for lists that cannot be sorted by name.
+func (q *listQuery) page(params pagination.Params, idColumn, nameColumn string) (string, []interface{}) {
+	page := listQuery{
+		conditions: append([]string(nil), q.conditions...),
+		args:       append([]interface{}(nil), q.args...),
+	}
+
+	op, direction := ">", " ASC"
+	if params.Desc {
+		op, direction = "<", " DESC"
+	}
+
+	order := idColumn + direction
+	if params.Sort == pagination.SortName {
+		order = nameColumn + direction + ", " + order
+	}
+
+	if cursor := params.Cursor; cursor != nil {
+		if params.Sort == pagination.SortName {
+			page.where(
+				"("+nameColumn+" "+op+" ? OR ("+nameColumn+" = ? AND "+idColumn+" "+op+" ?))",
+				cursor.Name, cursor.Name, cursor.ID,
+			)
+		} else {
+			page.where(idColumn+" "+op+" ?", cursor.ID)
+		}
+	}
+
+	return page.whereClause() + " ORDER BY " + order + " LIMIT " + strconv.Itoa(params.Limit+1), page.args
+}
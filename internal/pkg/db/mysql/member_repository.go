@@ -0,0 +1,154 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/team-xquare/deployment-platform/internal/app/member"
+	"github.com/team-xquare/deployment-platform/internal/pkg/utils/errors"
+)
+
+type memberRepository struct {
+	db *sql.DB
+}
+
+func NewMemberRepository(db *sql.DB) member.Repository {
+	return &memberRepository{db: db}
+}
+
+func (r *memberRepository) Save(ctx context.Context, m *member.Member) error {
+	if m.ID == 0 {
+		// Insert new member
+		query := `
+			INSERT INTO project_members (project_id, user_id, role)
+			VALUES (?, ?, ?)
+		`
+		result, err := conn(ctx, r.db).ExecContext(ctx, query, m.ProjectID, m.UserID, m.Role)
+		if err != nil {
+			return errors.Internal("Failed to add project member")
+		}
+
+		id, err := result.LastInsertId()
+		if err != nil {
+			return errors.Internal("Failed to get project member ID")
+		}
+		m.ID = uint(id)
+	} else {
+		// Update existing member
+		query := `
+			UPDATE project_members
+			SET role = ?, updated_at = CURRENT_TIMESTAMP
+			WHERE id = ?
+		`
+		_, err := conn(ctx, r.db).ExecContext(ctx, query, m.Role, m.ID)
+		if err != nil {
+			return errors.Internal("Failed to update project member")
+		}
+	}
+
+	return nil
+}
+
+func (r *memberRepository) FindByProjectAndUser(ctx context.Context, projectID, userID uint) (*member.Member, error) {
+	query := `
+		SELECT pm.id, pm.project_id, pm.user_id, pm.role, pm.created_at, pm.updated_at, u.email, u.name
+		FROM project_members pm
+		INNER JOIN users u ON u.id = pm.user_id
+		WHERE pm.project_id = ? AND pm.user_id = ?
+	`
+
+	var m member.Member
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, projectID, userID).Scan(
+		&m.ID, &m.ProjectID, &m.UserID, &m.Role, &m.CreatedAt, &m.UpdatedAt, &m.Email, &m.Name,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // Not a member, not an error
+		}
+		return nil, errors.Internal("Failed to get project member")
+	}
+
+	return &m, nil
+}
+
+func (r *memberRepository) FindByProjectID(ctx context.Context, projectID uint, filter member.ListFilter) ([]*member.Member, int, error) {
+	const from = "project_members pm INNER JOIN users u ON u.id = pm.user_id"
+
+	var q listQuery
+	q.where("pm.project_id = ?", projectID)
+	if filter.Name != "" {
+		q.whereContains(filter.Name, "u.name", "u.email")
+	}
+	if filter.Role != "" {
+		q.where("pm.role = ?", filter.Role)
+	}
+
+	total, err := q.count(ctx, conn(ctx, r.db), from)
+	if err != nil {
+		return nil, 0, errors.Internal("Failed to count project members")
+	}
+
+	clauses, args := q.page(filter.Page, "pm.id", "u.name")
+	query := "SELECT pm.id, pm.project_id, pm.user_id, pm.role, pm.created_at, pm.updated_at, u.email, u.name FROM " + from + clauses
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, errors.Internal("Failed to get project members")
+	}
+	defer rows.Close()
+
+	var members []*member.Member
+	for rows.Next() {
+		var m member.Member
+		err := rows.Scan(
+			&m.ID, &m.ProjectID, &m.UserID, &m.Role, &m.CreatedAt, &m.UpdatedAt, &m.Email, &m.Name,
+		)
+		if err != nil {
+			return nil, 0, errors.Internal("Failed to scan project member")
+		}
+		members = append(members, &m)
+	}
+
+	return members, total, nil
+}
+
+func (r *memberRepository) LockOwners(ctx context.Context, projectID uint) (int, error) {
+	// A locking read, so it sees owners committed by concurrent role changes
+	query := "SELECT user_id FROM project_members WHERE project_id = ? AND role = ? FOR UPDATE"
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, projectID, member.RoleOwner)
+	if err != nil {
+		return 0, errors.Internal("Failed to lock project owners")
+	}
+	defer rows.Close()
+
+	count := 0
+	for rows.Next() {
+		count++
+	}
+	if err := rows.Err(); err != nil {
+		return 0, errors.Internal("Failed to lock project owners")
+	}
+
+	return count, nil
+}
+
+func (r *memberRepository) Delete(ctx context.Context, projectID, userID uint) error {
+	query := "DELETE FROM project_members WHERE project_id = ? AND user_id = ?"
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, projectID, userID)
+	if err != nil {
+		return errors.Internal("Failed to remove project member")
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return errors.Internal("Failed to get affected rows")
+	}
+
+	if rows == 0 {
+		return errors.NotFound("Member not found")
+	}
+
+	return nil
+}
@@ -7,12 +7,23 @@ import (
 
 	"github.com/team-xquare/deployment-platform/internal/pkg/config"
 
-	_ "github.com/go-sql-driver/mysql"
+	mysqldriver "github.com/go-sql-driver/mysql"
 	"github.com/golang-migrate/migrate/v4"
 	"github.com/golang-migrate/migrate/v4/database/mysql"
 	_ "github.com/golang-migrate/migrate/v4/source/file"
 )
 
+// rowScanner is satisfied by both *sql.Row and *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// isDuplicateEntry reports whether err is a violation of a unique key.
+func isDuplicateEntry(err error) bool {
+	mysqlErr, ok := err.(*mysqldriver.MySQLError)
+	return ok && mysqlErr.Number == 1062
+}
+
 func NewConnection() (*sql.DB, error) {
 	dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?parseTime=true&charset=utf8mb4&collation=utf8mb4_unicode_ci",
 		config.AppConfig.MySQLUsername,
@@ -0,0 +1,159 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/team-xquare/deployment-platform/internal/app/outbox"
+	"github.com/team-xquare/deployment-platform/internal/pkg/utils/errors"
+)
+
+type outboxRepository struct {
+	db *sql.DB
+}
+
+func NewOutboxRepository(db *sql.DB) outbox.Repository {
+	return &outboxRepository{db: db}
+}
+
+func (r *outboxRepository) Save(ctx context.Context, message *outbox.Message) error {
+	query := `
+		INSERT INTO outbox_messages (aggregate_type, aggregate_id, installation_id, owner, repo, event_type, path, payload, status)
+		VALUES (?, ?, ?, ?, ?, ?, NULLIF(?, ''), ?, ?)
+	`
+	result, err := conn(ctx, r.db).ExecContext(ctx, query,
+		message.AggregateType, message.AggregateID, message.InstallationID, message.Owner, message.Repo,
+		message.EventType, message.Path, string(message.Payload), message.Status,
+	)
+	if err != nil {
+		return errors.Internal("Failed to create outbox message")
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return errors.Internal("Failed to get outbox message ID")
+	}
+	message.ID = uint(id)
+
+	return nil
+}
+
+func (r *outboxRepository) ClaimDue(ctx context.Context, token string, limit int, lease time.Duration) ([]*outbox.Message, error) {
+	// Time comparisons use the database clock so that workers on different
+	// hosts agree on when a message is due or a lease has expired. A message
+	// waits while an earlier one for its aggregate or path is pending, even if
+	// that one is only waiting for a retry; dead messages no longer hold it up.
+	// The due IDs are selected in a derived table because MySQL cannot read
+	// the table being updated in a subquery.
+	claim := `
+		UPDATE outbox_messages m
+		JOIN (
+			SELECT c.id FROM outbox_messages c
+			WHERE c.status = 'pending'
+				AND c.next_attempt_at <= CURRENT_TIMESTAMP
+				AND (c.claimed_until IS NULL OR c.claimed_until < CURRENT_TIMESTAMP)
+				AND NOT EXISTS (
+					SELECT 1 FROM outbox_messages e
+					WHERE e.status = 'pending' AND e.id < c.id
+						AND (
+							(e.aggregate_type = c.aggregate_type AND e.aggregate_id = c.aggregate_id)
+							OR (e.owner = c.owner AND e.repo = c.repo AND e.path = c.path)
+						)
+				)
+			ORDER BY c.id
+			LIMIT ?
+		) due ON due.id = m.id
+		SET m.claim_token = ?, m.claimed_until = CURRENT_TIMESTAMP + INTERVAL ? MICROSECOND
+		WHERE m.status = 'pending' AND (m.claimed_until IS NULL OR m.claimed_until < CURRENT_TIMESTAMP)
+	`
+	if _, err := r.db.ExecContext(ctx, claim, limit, token, lease.Microseconds()); err != nil {
+		return nil, errors.Internal("Failed to claim outbox messages")
+	}
+
+	query := `
+		SELECT id, aggregate_type, aggregate_id, installation_id, owner, repo, event_type, path, payload, status,
+			attempts, claim_token, last_error, next_attempt_at, delivered_at, created_at, updated_at
+		FROM outbox_messages WHERE claim_token = ? AND status = 'pending'
+		ORDER BY id
+	`
+	rows, err := r.db.QueryContext(ctx, query, token)
+	if err != nil {
+		return nil, errors.Internal("Failed to get outbox messages")
+	}
+	defer rows.Close()
+
+	var messages []*outbox.Message
+	for rows.Next() {
+		var m outbox.Message
+		var payload []byte
+		var installationID, path, lastError sql.NullString
+
+		err := rows.Scan(
+			&m.ID, &m.AggregateType, &m.AggregateID, &installationID, &m.Owner, &m.Repo, &m.EventType, &path, &payload, &m.Status,
+			&m.Attempts, &m.ClaimToken, &lastError, &m.NextAttemptAt, &m.DeliveredAt, &m.CreatedAt, &m.UpdatedAt,
+		)
+		if err != nil {
+			return nil, errors.Internal("Failed to scan outbox message")
+		}
+
+		m.Payload = payload
+		m.InstallationID = installationID.String
+		m.Path = path.String
+		m.LastError = lastError.String
+		messages = append(messages, &m)
+	}
+
+	return messages, nil
+}
+
+func (r *outboxRepository) MarkDelivered(ctx context.Context, message *outbox.Message) error {
+	query := `
+		UPDATE outbox_messages SET
+			status = 'delivered', attempts = ?, delivered_at = CURRENT_TIMESTAMP,
+			payload = JSON_REMOVE(payload, '$.secrets'),
+			claim_token = NULL, claimed_until = NULL
+		WHERE id = ? AND claim_token = ?
+	`
+	return r.markClaimed(ctx, query, message.Attempts, message.ID, message.ClaimToken)
+}
+
+func (r *outboxRepository) MarkRetry(ctx context.Context, message *outbox.Message, delay time.Duration) error {
+	query := `
+		UPDATE outbox_messages SET
+			attempts = ?, last_error = ?, next_attempt_at = CURRENT_TIMESTAMP + INTERVAL ? MICROSECOND,
+			claim_token = NULL, claimed_until = NULL
+		WHERE id = ? AND claim_token = ?
+	`
+	return r.markClaimed(ctx, query, message.Attempts, message.LastError, delay.Microseconds(), message.ID, message.ClaimToken)
+}
+
+func (r *outboxRepository) MarkDead(ctx context.Context, message *outbox.Message) error {
+	query := `
+		UPDATE outbox_messages SET
+			status = 'dead', attempts = ?, last_error = ?,
+			payload = JSON_REMOVE(payload, '$.secrets'),
+			claim_token = NULL, claimed_until = NULL
+		WHERE id = ? AND claim_token = ?
+	`
+	return r.markClaimed(ctx, query, message.Attempts, message.LastError, message.ID, message.ClaimToken)
+}
+
+// markClaimed runs an update guarded by the message's claim token, so a worker
+// whose lease expired cannot overwrite the result of the worker that took over.
+func (r *outboxRepository) markClaimed(ctx context.Context, query string, args ...interface{}) error {
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return errors.Internal("Failed to update outbox message")
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return errors.Internal("Failed to get affected rows")
+	}
+	if rows == 0 {
+		return outbox.ErrLeaseLost
+	}
+
+	return nil
+}
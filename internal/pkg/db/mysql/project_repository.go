@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 
+	"github.com/team-xquare/deployment-platform/internal/app/member"
 	"github.com/team-xquare/deployment-platform/internal/app/project"
 	"github.com/team-xquare/deployment-platform/internal/pkg/utils/errors"
 )
@@ -18,12 +19,18 @@ func NewProjectRepository(db *sql.DB) project.Repository {
 
 func (r *projectRepository) Save(ctx context.Context, proj *project.Project) error {
 	if proj.ID == 0 {
-		// Insert new project
+		// Insert new project together with its owner membership
+		tx, err := r.db.BeginTx(ctx, nil)
+		if err != nil {
+			return errors.Internal("Failed to start transaction")
+		}
+		defer tx.Rollback()
+
 		query := `
-			INSERT INTO projects (name, description, owner_id)
-			VALUES (?, ?, ?)
+			INSERT INTO projects (name, slug, description, owner_id)
+			VALUES (?, ?, ?, ?)
 		`
-		result, err := r.db.ExecContext(ctx, query, proj.Name, proj.Description, proj.OwnerID)
+		result, err := tx.ExecContext(ctx, query, proj.Name, proj.Slug, proj.Description, proj.OwnerID)
 		if err != nil {
 			return errors.Internal("Failed to create project")
 		}
@@ -32,6 +39,18 @@ func (r *projectRepository) Save(ctx context.Context, proj *project.Project) err
 		if err != nil {
 			return errors.Internal("Failed to get project ID")
 		}
+
+		_, err = tx.ExecContext(ctx,
+			"INSERT INTO project_members (project_id, user_id, role) VALUES (?, ?, ?)",
+			id, proj.OwnerID, member.RoleOwner,
+		)
+		if err != nil {
+			return errors.Internal("Failed to add project owner")
+		}
+
+		if err = tx.Commit(); err != nil {
+			return errors.Internal("Failed to commit transaction")
+		}
 		proj.ID = uint(id)
 	} else {
 		// Update existing project
@@ -51,13 +70,13 @@ func (r *projectRepository) Save(ctx context.Context, proj *project.Project) err
 
 func (r *projectRepository) FindByID(ctx context.Context, id uint) (*project.Project, error) {
 	query := `
-		SELECT id, name, description, owner_id, created_at, updated_at
+		SELECT id, name, slug, description, owner_id, created_at, updated_at
 		FROM projects WHERE id = ?
 	`
 
 	var p project.Project
 	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&p.ID, &p.Name, &p.Description, &p.OwnerID, &p.CreatedAt, &p.UpdatedAt,
+		&p.ID, &p.Name, &p.Slug, &p.Description, &p.OwnerID, &p.CreatedAt, &p.UpdatedAt,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -69,16 +88,26 @@ func (r *projectRepository) FindByID(ctx context.Context, id uint) (*project.Pro
 	return &p, nil
 }
 
-func (r *projectRepository) FindByOwnerID(ctx context.Context, ownerID uint) ([]*project.Project, error) {
-	query := `
-		SELECT id, name, description, owner_id, created_at, updated_at
-		FROM projects WHERE owner_id = ?
-		ORDER BY created_at DESC
-	`
+func (r *projectRepository) FindByMemberID(ctx context.Context, userID uint, filter project.ListFilter) ([]*project.Project, int, error) {
+	const from = "projects p INNER JOIN project_members pm ON pm.project_id = p.id"
+
+	var q listQuery
+	q.where("pm.user_id = ?", userID)
+	if filter.Name != "" {
+		q.whereContains(filter.Name, "p.name")
+	}
+
+	total, err := q.count(ctx, r.db, from)
+	if err != nil {
+		return nil, 0, errors.Internal("Failed to count projects")
+	}
 
-	rows, err := r.db.QueryContext(ctx, query, ownerID)
+	clauses, args := q.page(filter.Page, "p.id", "p.name")
+	query := "SELECT p.id, p.name, p.slug, p.description, p.owner_id, p.created_at, p.updated_at FROM " + from + clauses
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
-		return nil, errors.Internal("Failed to get projects")
+		return nil, 0, errors.Internal("Failed to get projects")
 	}
 	defer rows.Close()
 
@@ -86,26 +115,46 @@ func (r *projectRepository) FindByOwnerID(ctx context.Context, ownerID uint) ([]
 	for rows.Next() {
 		var p project.Project
 		err := rows.Scan(
-			&p.ID, &p.Name, &p.Description, &p.OwnerID, &p.CreatedAt, &p.UpdatedAt,
+			&p.ID, &p.Name, &p.Slug, &p.Description, &p.OwnerID, &p.CreatedAt, &p.UpdatedAt,
 		)
 		if err != nil {
-			return nil, errors.Internal("Failed to scan project")
+			return nil, 0, errors.Internal("Failed to scan project")
 		}
 		projects = append(projects, &p)
 	}
 
-	return projects, nil
+	return projects, total, nil
 }
 
 func (r *projectRepository) FindByOwnerAndName(ctx context.Context, ownerID uint, name string) (*project.Project, error) {
 	query := `
-		SELECT id, name, description, owner_id, created_at, updated_at
+		SELECT id, name, slug, description, owner_id, created_at, updated_at
 		FROM projects WHERE owner_id = ? AND name = ?
 	`
 
 	var p project.Project
 	err := r.db.QueryRowContext(ctx, query, ownerID, name).Scan(
-		&p.ID, &p.Name, &p.Description, &p.OwnerID, &p.CreatedAt, &p.UpdatedAt,
+		&p.ID, &p.Name, &p.Slug, &p.Description, &p.OwnerID, &p.CreatedAt, &p.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // Not found, not an error
+		}
+		return nil, errors.Internal("Failed to get project")
+	}
+
+	return &p, nil
+}
+
+func (r *projectRepository) FindBySlug(ctx context.Context, slug string) (*project.Project, error) {
+	query := `
+		SELECT id, name, slug, description, owner_id, created_at, updated_at
+		FROM projects WHERE slug = ?
+	`
+
+	var p project.Project
+	err := r.db.QueryRowContext(ctx, query, slug).Scan(
+		&p.ID, &p.Name, &p.Slug, &p.Description, &p.OwnerID, &p.CreatedAt, &p.UpdatedAt,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
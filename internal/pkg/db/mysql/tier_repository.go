@@ -0,0 +1,242 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/team-xquare/deployment-platform/internal/app/tier"
+	"github.com/team-xquare/deployment-platform/internal/pkg/utils/errors"
+)
+
+type tierRepository struct {
+	db *sql.DB
+}
+
+func NewTierRepository(db *sql.DB) tier.Repository {
+	return &tierRepository{db: db}
+}
+
+const tierColumns = `
+	id, name, cpu_millicores, memory_mib, min_replicas, max_replicas, created_at, updated_at
+`
+
+const quotaColumns = `
+	id, cpu_millicores, memory_mib, created_at, updated_at
+`
+
+// quotaTables maps a quota scope to its table and the column holding the scope ID.
+var quotaTables = map[string]struct{ table, column string }{
+	tier.ScopeProject: {"project_quotas", "project_id"},
+	tier.ScopeUser:    {"user_quotas", "user_id"},
+}
+
+// scopedProjects selects the IDs of the projects a quota scope covers.
+var scopedProjects = map[string]string{
+	tier.ScopeProject: "SELECT id FROM projects WHERE id = ?",
+	tier.ScopeUser:    "SELECT id FROM projects WHERE owner_id = ?",
+}
+
+func (r *tierRepository) FindAll(ctx context.Context) ([]*tier.Tier, error) {
+	query := "SELECT " + tierColumns + " FROM tiers ORDER BY cpu_millicores, memory_mib, name"
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query)
+	if err != nil {
+		return nil, errors.Internal("Failed to get tiers")
+	}
+	defer rows.Close()
+
+	var tiers []*tier.Tier
+	for rows.Next() {
+		t, err := scanTier(rows)
+		if err != nil {
+			return nil, errors.Internal("Failed to scan tier")
+		}
+		tiers = append(tiers, t)
+	}
+
+	return tiers, nil
+}
+
+func (r *tierRepository) FindByName(ctx context.Context, name string) (*tier.Tier, error) {
+	query := "SELECT " + tierColumns + " FROM tiers WHERE name = ?"
+
+	t, err := scanTier(conn(ctx, r.db).QueryRowContext(ctx, query, name))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, errors.Internal("Failed to get tier")
+	}
+
+	return t, nil
+}
+
+func (r *tierRepository) Save(ctx context.Context, t *tier.Tier) error {
+	query := `
+		INSERT INTO tiers (name, cpu_millicores, memory_mib, min_replicas, max_replicas)
+		VALUES (?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE
+			cpu_millicores = VALUES(cpu_millicores),
+			memory_mib = VALUES(memory_mib),
+			min_replicas = VALUES(min_replicas),
+			max_replicas = VALUES(max_replicas)
+	`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, t.Name, t.CPUMillicores, t.MemoryMiB, t.MinReplicas, t.MaxReplicas)
+	if err != nil {
+		return errors.Internal("Failed to save tier")
+	}
+
+	saved, err := r.FindByName(ctx, t.Name)
+	if err != nil {
+		return err
+	}
+	*t = *saved
+
+	return nil
+}
+
+func (r *tierRepository) Delete(ctx context.Context, name string) error {
+	_, err := conn(ctx, r.db).ExecContext(ctx, "DELETE FROM tiers WHERE name = ?", name)
+	if err != nil {
+		return errors.Internal("Failed to delete tier")
+	}
+	return nil
+}
+
+func (r *tierRepository) IsInUse(ctx context.Context, name string) (bool, error) {
+	query := `
+		SELECT EXISTS (SELECT 1 FROM application_environments WHERE tier = ?)
+			OR EXISTS (SELECT 1 FROM addons WHERE tier = ?)
+	`
+
+	var inUse bool
+	if err := conn(ctx, r.db).QueryRowContext(ctx, query, name, name).Scan(&inUse); err != nil {
+		return false, errors.Internal("Failed to check tier usage")
+	}
+
+	return inUse, nil
+}
+
+func (r *tierRepository) FindQuota(ctx context.Context, scope string, scopeID uint) (*tier.Quota, error) {
+	t := quotaTables[scope]
+	query := "SELECT " + quotaColumns + " FROM " + t.table + " WHERE " + t.column + " = ?"
+
+	var q tier.Quota
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, scopeID).Scan(
+		&q.ID, &q.CPUMillicores, &q.MemoryMiB, &q.CreatedAt, &q.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, errors.Internal("Failed to get quota")
+	}
+	q.Scope, q.ScopeID = scope, scopeID
+
+	return &q, nil
+}
+
+func (r *tierRepository) LockQuotas(ctx context.Context, projectID uint) ([]*tier.Quota, error) {
+	// Both are locking reads, which see the latest committed quotas and do not
+	// fix the transaction's snapshot
+	queries := []struct {
+		scope string
+		query string
+	}{
+		{tier.ScopeProject, `
+			SELECT q.project_id, q.id, q.cpu_millicores, q.memory_mib, q.created_at, q.updated_at
+			FROM project_quotas q WHERE q.project_id = ?
+			FOR UPDATE
+		`},
+		{tier.ScopeUser, `
+			SELECT q.user_id, q.id, q.cpu_millicores, q.memory_mib, q.created_at, q.updated_at
+			FROM projects p JOIN user_quotas q ON q.user_id = p.owner_id WHERE p.id = ?
+			FOR UPDATE
+		`},
+	}
+
+	var quotas []*tier.Quota
+	for _, q := range queries {
+		quota := tier.Quota{Scope: q.scope}
+		err := conn(ctx, r.db).QueryRowContext(ctx, q.query, projectID).Scan(
+			&quota.ScopeID, &quota.ID, &quota.CPUMillicores, &quota.MemoryMiB, &quota.CreatedAt, &quota.UpdatedAt,
+		)
+		if err == sql.ErrNoRows {
+			continue
+		}
+		if err != nil {
+			return nil, errors.Internal("Failed to lock quota")
+		}
+		quotas = append(quotas, &quota)
+	}
+
+	return quotas, nil
+}
+
+func (r *tierRepository) SaveQuota(ctx context.Context, q *tier.Quota) error {
+	t := quotaTables[q.Scope]
+	query := `
+		INSERT INTO ` + t.table + ` (` + t.column + `, cpu_millicores, memory_mib)
+		VALUES (?, ?, ?)
+		ON DUPLICATE KEY UPDATE
+			cpu_millicores = VALUES(cpu_millicores),
+			memory_mib = VALUES(memory_mib)
+	`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, q.ScopeID, q.CPUMillicores, q.MemoryMiB)
+	if err != nil {
+		return errors.Internal("Failed to save quota")
+	}
+
+	saved, err := r.FindQuota(ctx, q.Scope, q.ScopeID)
+	if err != nil {
+		return err
+	}
+	*q = *saved
+
+	return nil
+}
+
+func (r *tierRepository) DeleteQuota(ctx context.Context, scope string, scopeID uint) error {
+	t := quotaTables[scope]
+	_, err := conn(ctx, r.db).ExecContext(ctx, "DELETE FROM "+t.table+" WHERE "+t.column+" = ?", scopeID)
+	if err != nil {
+		return errors.Internal("Failed to delete quota")
+	}
+	return nil
+}
+
+func (r *tierRepository) Usage(ctx context.Context, scope string, scopeID uint) (tier.Resources, error) {
+	projects := scopedProjects[scope]
+	query := `
+		SELECT
+			COALESCE(SUM(t.cpu_millicores * t.max_replicas), 0),
+			COALESCE(SUM(t.memory_mib * t.max_replicas), 0)
+		FROM (
+			SELECT e.tier FROM application_environments e
+			JOIN applications a ON a.id = e.application_id
+			WHERE a.project_id IN (` + projects + `)
+			UNION ALL
+			SELECT tier FROM addons WHERE project_id IN (` + projects + `)
+		) used
+		JOIN tiers t ON t.name = used.tier
+	`
+
+	var usage tier.Resources
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, scopeID, scopeID).Scan(&usage.CPUMillicores, &usage.MemoryMiB)
+	if err != nil {
+		return tier.Resources{}, errors.Internal("Failed to get resource usage")
+	}
+
+	return usage, nil
+}
+
+func scanTier(row rowScanner) (*tier.Tier, error) {
+	var t tier.Tier
+	err := row.Scan(
+		&t.ID, &t.Name, &t.CPUMillicores, &t.MemoryMiB, &t.MinReplicas, &t.MaxReplicas, &t.CreatedAt, &t.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
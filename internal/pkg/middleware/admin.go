@@ -0,0 +1,22 @@
+package middleware
+
+import (
+	"github.com/team-xquare/deployment-platform/internal/pkg/config"
+	"github.com/team-xquare/deployment-platform/internal/pkg/utils/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Admin restricts a route to the platform administrators listed in
+// ADMIN_EMAILS. It must run after Auth.
+func Admin() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !config.IsAdmin(c.GetString("email")) {
+			c.Error(errors.Forbidden("Admin access required"))
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,158 @@
+package crypto
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+// Encrypted values look like "enc:v1:<key id>:<base64 nonce and ciphertext>",
+// so every value names the key it was sealed with.
+const encryptedPrefix = "enc:v1:"
+
+// Keyring encrypts secrets at rest with AES-256-GCM. The primary key seals
+// new values; the other keys are kept so values sealed before a rotation can
+// still be opened until they are re-encrypted.
+type Keyring struct {
+	primary string
+	keys    map[string]cipher.AEAD
+}
+
+// NewKeyring builds a keyring from "id:base64key" entries holding 32-byte
+// keys. The first entry is the primary key.
+func NewKeyring(entries []string) (*Keyring, error) {
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("at least one encryption key is required")
+	}
+
+	keyring := &Keyring{keys: make(map[string]cipher.AEAD, len(entries))}
+	for _, entry := range entries {
+		id, encoded, ok := strings.Cut(entry, ":")
+		if !ok || id == "" || strings.Contains(id, ":") {
+			return nil, fmt.Errorf("encryption key must be given as id:base64key")
+		}
+		if _, exists := keyring.keys[id]; exists {
+			return nil, fmt.Errorf("encryption key %q is listed twice", id)
+		}
+
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil || len(key) != 32 {
+			return nil, fmt.Errorf("encryption key %q must be 32 bytes encoded as base64", id)
+		}
+
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, err
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, err
+		}
+
+		keyring.keys[id] = aead
+		if keyring.primary == "" {
+			keyring.primary = id
+		}
+	}
+
+	return keyring, nil
+}
+
+// PrimaryKeyID returns the ID of the key new values are encrypted with.
+func (k *Keyring) PrimaryKeyID() string {
+	return k.primary
+}
+
+// Encrypt seals plaintext with the primary key. The key ID is authenticated
+// along with the ciphertext.
+func (k *Keyring) Encrypt(plaintext string) (string, error) {
+	aead := k.keys[k.primary]
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := aead.Seal(nonce, nonce, []byte(plaintext), []byte(k.primary))
+
+	return encryptedPrefix + k.primary + ":" + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt opens a value produced by Encrypt with whichever key sealed it.
+func (k *Keyring) Decrypt(value string) (string, error) {
+	if !IsEncrypted(value) {
+		return "", fmt.Errorf("value is not encrypted")
+	}
+
+	id, encoded, _ := strings.Cut(strings.TrimPrefix(value, encryptedPrefix), ":")
+	aead, ok := k.keys[id]
+	if !ok {
+		return "", fmt.Errorf("encryption key %q is not configured", id)
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return "", fmt.Errorf("malformed encrypted value")
+	}
+
+	plaintext, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], []byte(id))
+	if err != nil {
+		return "", err
+	}
+
+	return string(plaintext), nil
+}
+
+// EncryptValues seals every value of values with the primary key.
+func (k *Keyring) EncryptValues(values map[string]string) (map[string]string, error) {
+	sealed := make(map[string]string, len(values))
+	for name, value := range values {
+		encrypted, err := k.Encrypt(value)
+		if err != nil {
+			return nil, err
+		}
+		sealed[name] = encrypted
+	}
+	return sealed, nil
+}
+
+// DecryptValues opens every encrypted value of values. Values that are not
+// encrypted are returned as they are.
+func (k *Keyring) DecryptValues(values map[string]string) (map[string]string, error) {
+	opened := make(map[string]string, len(values))
+	for name, value := range values {
+		if !IsEncrypted(value) {
+			opened[name] = value
+			continue
+		}
+		plaintext, err := k.Decrypt(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt %s: %w", name, err)
+		}
+		opened[name] = plaintext
+	}
+	return opened, nil
+}
+
+// NeedsReEncryption reports whether value is plaintext or sealed with a key
+// other than the primary one.
+func (k *Keyring) NeedsReEncryption(value string) bool {
+	return KeyID(value) != k.primary
+}
+
+// IsEncrypted reports whether value was produced by Encrypt.
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, encryptedPrefix)
+}
+
+// KeyID returns the ID of the key value was encrypted with, or "" when value
+// is not encrypted.
+func KeyID(value string) string {
+	if !IsEncrypted(value) {
+		return ""
+	}
+	id, _, _ := strings.Cut(strings.TrimPrefix(value, encryptedPrefix), ":")
+	return id
+}
@@ -0,0 +1,114 @@
+package diff
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+const (
+	Added   = "added"
+	Removed = "removed"
+	Changed = "changed"
+)
+
+// Change is one field that differs between two values. Field is a path such
+// as "build.gradle.javaVersion" or "endpoints[0].routes[1]".
+type Change struct {
+	Field string      `json:"field"`
+	Type  string      `json:"type"`
+	From  interface{} `json:"from,omitempty"`
+	To    interface{} `json:"to,omitempty"`
+}
+
+// Compare returns the field-by-field changes from one value to another, both
+// compared in their JSON form. Objects are compared key by key and arrays
+// index by index; anything else is reported as a change of the whole field.
+func Compare(from, to interface{}) ([]Change, error) {
+	fromValue, err := normalize(from)
+	if err != nil {
+		return nil, err
+	}
+	toValue, err := normalize(to)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := []Change{}
+	walk("", fromValue, toValue, &changes)
+	return changes, nil
+}
+
+func normalize(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var normalized interface{}
+	if err := json.Unmarshal(data, &normalized); err != nil {
+		return nil, err
+	}
+	return normalized, nil
+}
+
+func walk(field string, from, to interface{}, changes *[]Change) {
+	switch {
+	case from == nil && to == nil:
+		return
+	case from == nil:
+		*changes = append(*changes, Change{Field: field, Type: Added, To: to})
+		return
+	case to == nil:
+		*changes = append(*changes, Change{Field: field, Type: Removed, From: from})
+		return
+	}
+
+	fromObject, fromIsObject := from.(map[string]interface{})
+	toObject, toIsObject := to.(map[string]interface{})
+	if fromIsObject && toIsObject {
+		keys := make([]string, 0, len(fromObject)+len(toObject))
+		for key := range fromObject {
+			keys = append(keys, key)
+		}
+		for key := range toObject {
+			if _, ok := fromObject[key]; !ok {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			walk(join(field, key), fromObject[key], toObject[key], changes)
+		}
+		return
+	}
+
+	fromArray, fromIsArray := from.([]interface{})
+	toArray, toIsArray := to.([]interface{})
+	if fromIsArray && toIsArray {
+		for i := 0; i < len(fromArray) || i < len(toArray); i++ {
+			var fromItem, toItem interface{}
+			if i < len(fromArray) {
+				fromItem = fromArray[i]
+			}
+			if i < len(toArray) {
+				toItem = toArray[i]
+			}
+			walk(field+"["+strconv.Itoa(i)+"]", fromItem, toItem, changes)
+		}
+		return
+	}
+
+	if !reflect.DeepEqual(from, to) {
+		*changes = append(*changes, Change{Field: field, Type: Changed, From: from, To: to})
+	}
+}
+
+func join(field, key string) string {
+	if field == "" {
+		return key
+	}
+	return field + "." + key
+}
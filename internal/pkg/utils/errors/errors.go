@@ -5,9 +5,16 @@ import (
 )
 
 type AppError struct {
-	StatusCode int    `json:"status_code"`
-	Message    string `json:"message"`
-	Type       string `json:"type"`
+	StatusCode int          `json:"status_code"`
+	Message    string       `json:"message"`
+	Type       string       `json:"type"`
+	Details    []FieldError `json:"details,omitempty"`
+}
+
+// FieldError describes why a single request field was rejected.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
 }
 
 func (e *AppError) Error() string {
@@ -53,3 +60,13 @@ func Internal(message string) *AppError {
 		Type:       "INTERNAL_SERVER_ERROR",
 	}
 }
+
+// Validation reports every rejected field of a request at once.
+func Validation(details []FieldError) *AppError {
+	return &AppError{
+		StatusCode: http.StatusUnprocessableEntity,
+		Message:    "Validation failed",
+		Type:       "VALIDATION_ERROR",
+		Details:    details,
+	}
+}
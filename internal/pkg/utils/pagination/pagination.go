@@ -0,0 +1,134 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/team-xquare/deployment-platform/internal/pkg/utils/errors"
+)
+
+const (
+	DefaultLimit = 50
+	MaxLimit     = 200
+)
+
+// Sort keys shared by list endpoints. Lists sorted by creation are ordered by
+// ID, which follows the creation time.
+const (
+	SortName    = "name"
+	SortCreated = "created_at"
+)
+
+// Params selects one page of a list. Rows are ordered by Sort, then by ID in
+// the same direction, and the page starts after the row Cursor points at.
+type Params struct {
+	Limit  int
+	Sort   string
+	Desc   bool
+	Cursor *Cursor
+}
+
+// Cursor is the position of the last row of a page. It remembers the order it
+// was issued for, so it cannot be reused with another.
+type Cursor struct {
+	Order string `json:"o"`
+	Name  string `json:"n,omitempty"`
+	ID    uint   `json:"id"`
+}
+
+// Page is the metadata returned with every page of a list; NextCursor is
+// empty on the last page and TotalCount ignores paging.
+type Page struct {
+	NextCursor string `json:"next_cursor,omitempty"`
+	TotalCount int    `json:"total_count"`
+}
+
+// Parse reads the limit, cursor and sort query parameters. sorts are the keys
+// the list may be sorted by; a leading "-" sorts in descending order, as in
+// the default order, e.g. "-created_at".
+func Parse(c *gin.Context, defaultOrder string, sorts ...string) (Params, error) {
+	params := Params{Limit: DefaultLimit}
+
+	if limitStr := c.Query("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit < 1 || limit > MaxLimit {
+			return Params{}, errors.BadRequest("Invalid limit; use 1 to " + strconv.Itoa(MaxLimit))
+		}
+		params.Limit = limit
+	}
+
+	order := c.DefaultQuery("sort", defaultOrder)
+	params.Sort = strings.TrimPrefix(order, "-")
+	params.Desc = params.Sort != order
+	if !contains(sorts, params.Sort) {
+		return Params{}, errors.BadRequest("Invalid sort; use one of " + strings.Join(sorts, ", ") + ", optionally prefixed with -")
+	}
+
+	if cursorStr := c.Query("cursor"); cursorStr != "" {
+		cursor, err := decodeCursor(cursorStr)
+		if err != nil || cursor.Order != order {
+			return Params{}, errors.BadRequest("Invalid cursor")
+		}
+		params.Cursor = cursor
+	}
+
+	return params, nil
+}
+
+// Order is the sort parameter the params were parsed from.
+func (p Params) Order() string {
+	if p.Desc {
+		return "-" + p.Sort
+	}
+	return p.Sort
+}
+
+// Paginate trims the extra row a repository fetches to tell whether another
+// page follows, and builds the page metadata. key returns a row's ID and name.
+func Paginate[T any](rows []T, params Params, total int, key func(T) (uint, string)) ([]T, Page) {
+	page := Page{TotalCount: total}
+	if len(rows) <= params.Limit {
+		return rows, page
+	}
+
+	rows = rows[:params.Limit]
+	id, name := key(rows[len(rows)-1])
+	cursor := Cursor{Order: params.Order(), ID: id}
+	if params.Sort == SortName {
+		cursor.Name = name
+	}
+	page.NextCursor = cursor.encode()
+
+	return rows, page
+}
+
+func (c Cursor) encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(s string) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+
+	var cursor Cursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, err
+	}
+
+	return &cursor, nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
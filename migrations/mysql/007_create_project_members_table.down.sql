@@ -0,0 +1 @@
+DROP TABLE IF EXISTS project_members;
\ No newline at end of file
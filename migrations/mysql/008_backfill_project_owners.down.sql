@@ -0,0 +1 @@
+DELETE pm FROM project_members pm JOIN projects p ON p.id = pm.project_id AND p.owner_id = pm.user_id WHERE pm.role = 'owner';
\ No newline at end of file
@@ -0,0 +1,2 @@
+INSERT IGNORE INTO project_members (project_id, user_id, role)
+SELECT id, owner_id, 'owner' FROM projects;
\ No newline at end of file
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS deployments;
\ No newline at end of file
@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS deployments (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    project_id INT NOT NULL,
+    application_id INT NOT NULL, -- kept after the application is deleted so history survives
+    application_name VARCHAR(255) NOT NULL,
+    action VARCHAR(20) NOT NULL, -- apply, remove
+    spec JSON,
+    requested_by INT,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending', -- pending, dispatched, failed, succeeded
+    error_message TEXT,
+    dispatched_at TIMESTAMP NULL,
+    completed_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    FOREIGN KEY (project_id) REFERENCES projects (id) ON DELETE CASCADE,
+    FOREIGN KEY (requested_by) REFERENCES users (id) ON DELETE SET NULL,
+    INDEX idx_application_id (application_id),
+    INDEX idx_status (status)
+);
@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS outbox_messages (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    aggregate_type VARCHAR(50) NOT NULL, -- deployment, addon
+    aggregate_id INT NOT NULL,
+    owner VARCHAR(255) NOT NULL,
+    repo VARCHAR(255) NOT NULL,
+    event_type VARCHAR(100) NOT NULL,
+    payload JSON NOT NULL,
+
+    status VARCHAR(20) NOT NULL DEFAULT 'pending', -- pending, delivered, dead
+    attempts INT NOT NULL DEFAULT 0,
+    last_error TEXT,
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    claim_token VARCHAR(64),
+    claimed_until TIMESTAMP NULL,
+    delivered_at TIMESTAMP NULL,
+
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    INDEX idx_status_next_attempt (status, next_attempt_at),
+    INDEX idx_claim_token (claim_token),
+    INDEX idx_aggregate (aggregate_type, aggregate_id)
+);
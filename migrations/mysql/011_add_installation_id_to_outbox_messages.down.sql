@@ -0,0 +1 @@
+ALTER TABLE outbox_messages DROP COLUMN installation_id;
\ No newline at end of file
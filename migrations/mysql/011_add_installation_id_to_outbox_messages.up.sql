@@ -0,0 +1,2 @@
+ALTER TABLE outbox_messages
+    ADD COLUMN installation_id VARCHAR(50) AFTER aggregate_id; -- empty means resolve from owner/repo
@@ -0,0 +1 @@
+ALTER TABLE github_installations DROP COLUMN suspended_at;
\ No newline at end of file
@@ -0,0 +1,2 @@
+ALTER TABLE github_installations
+    ADD COLUMN suspended_at TIMESTAMP NULL AFTER permissions;
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS github_installation_repositories;
\ No newline at end of file
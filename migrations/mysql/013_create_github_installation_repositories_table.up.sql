@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS github_installation_repositories (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    installation_id VARCHAR(50) NOT NULL,
+    repository_id BIGINT NOT NULL,
+    full_name VARCHAR(255) NOT NULL,
+    private BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY unique_installation_repository (installation_id, repository_id),
+    INDEX idx_repository_id (repository_id)
+);
@@ -0,0 +1 @@
+ALTER TABLE deployments DROP COLUMN commit_sha;
\ No newline at end of file
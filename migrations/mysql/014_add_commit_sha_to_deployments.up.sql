@@ -0,0 +1 @@
+ALTER TABLE deployments ADD COLUMN commit_sha VARCHAR(40) NULL AFTER spec;
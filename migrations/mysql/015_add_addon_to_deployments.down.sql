@@ -0,0 +1,2 @@
+-- application_id and application_name stay nullable: addon deployments have neither
+ALTER TABLE deployments DROP INDEX idx_addon_id, DROP COLUMN addon_name, DROP COLUMN addon_id;
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE deployments MODIFY application_id INT NULL, MODIFY application_name VARCHAR(255) NULL, ADD COLUMN addon_id INT NULL AFTER application_name, ADD COLUMN addon_name VARCHAR(255) NULL AFTER addon_id, ADD INDEX idx_addon_id (addon_id);
@@ -0,0 +1 @@
+ALTER TABLE deployments DROP INDEX unique_correlation_id, DROP COLUMN run_url, DROP COLUMN correlation_id;
\ No newline at end of file
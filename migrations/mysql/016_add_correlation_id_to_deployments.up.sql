@@ -0,0 +1 @@
+ALTER TABLE deployments ADD COLUMN correlation_id VARCHAR(64) NULL AFTER commit_sha, ADD COLUMN run_url VARCHAR(512) NULL AFTER correlation_id, ADD UNIQUE KEY unique_correlation_id (correlation_id);
@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    delivery_id VARCHAR(64) NOT NULL UNIQUE, -- X-GitHub-Delivery
+    event_type VARCHAR(100) NOT NULL,
+    payload LONGTEXT NOT NULL,
+    signature_valid BOOLEAN NOT NULL DEFAULT FALSE,
+    status VARCHAR(20) NOT NULL DEFAULT 'processing', -- processing, processed, ignored, failed, rejected
+    error_message TEXT,
+    duration_ms INT,
+    attempts INT NOT NULL DEFAULT 0,
+    processed_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    INDEX idx_status (status),
+    INDEX idx_created_at (created_at)
+);
@@ -0,0 +1 @@
+ALTER TABLE projects DROP COLUMN slug;
\ No newline at end of file
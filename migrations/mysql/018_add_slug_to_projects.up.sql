@@ -0,0 +1 @@
+ALTER TABLE projects ADD COLUMN slug VARCHAR(63) NULL AFTER name;
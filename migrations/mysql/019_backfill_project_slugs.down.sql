@@ -0,0 +1 @@
+UPDATE projects SET slug = NULL WHERE slug = CONCAT('project-', id);
\ No newline at end of file
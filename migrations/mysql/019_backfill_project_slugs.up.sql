@@ -0,0 +1 @@
+UPDATE projects SET slug = CONCAT('project-', id) WHERE slug IS NULL;
@@ -0,0 +1 @@
+ALTER TABLE projects DROP INDEX unique_slug, MODIFY slug VARCHAR(63) NULL;
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE projects MODIFY slug VARCHAR(63) NOT NULL, ADD UNIQUE KEY unique_slug (slug);
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS platform_gitops_targets;
\ No newline at end of file
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS platform_gitops_targets (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    resource_type VARCHAR(20) NOT NULL UNIQUE, -- application, addon
+    owner VARCHAR(255) NOT NULL,
+    repo VARCHAR(255) NOT NULL,
+    event_type VARCHAR(100) NOT NULL DEFAULT 'config-api',
+    installation_id VARCHAR(50), -- looked up from the repository when empty
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+);
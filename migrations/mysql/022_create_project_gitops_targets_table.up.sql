@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS project_gitops_targets (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    project_id INT NOT NULL,
+    resource_type VARCHAR(20) NOT NULL, -- application, addon
+    owner VARCHAR(255) NOT NULL,
+    repo VARCHAR(255) NOT NULL,
+    event_type VARCHAR(100) NOT NULL DEFAULT 'config-api',
+    installation_id VARCHAR(50), -- looked up from the repository when empty
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    FOREIGN KEY (project_id) REFERENCES projects (id) ON DELETE CASCADE,
+    UNIQUE KEY unique_project_resource_type (project_id, resource_type)
+);
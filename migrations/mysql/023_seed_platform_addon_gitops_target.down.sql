@@ -0,0 +1 @@
+DELETE FROM platform_gitops_targets WHERE resource_type = 'addon' AND owner = 'team-xquare' AND repo = 'infrastructure-configs';
\ No newline at end of file
@@ -0,0 +1 @@
+INSERT IGNORE INTO platform_gitops_targets (resource_type, owner, repo, event_type) VALUES ('addon', 'team-xquare', 'infrastructure-configs', 'config-api');
@@ -0,0 +1 @@
+ALTER TABLE addons DROP COLUMN version;
\ No newline at end of file
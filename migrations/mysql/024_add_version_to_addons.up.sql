@@ -0,0 +1 @@
+ALTER TABLE addons ADD COLUMN version VARCHAR(20) NULL AFTER type;
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS addon_bindings;
\ No newline at end of file
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS addon_bindings (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    addon_id INT NOT NULL,
+    application_id INT NOT NULL,
+    env_prefix VARCHAR(50) NOT NULL DEFAULT '',
+    username VARCHAR(64) NOT NULL,
+    password VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    FOREIGN KEY (addon_id) REFERENCES addons (id) ON DELETE CASCADE,
+    FOREIGN KEY (application_id) REFERENCES applications (id) ON DELETE CASCADE,
+    UNIQUE KEY unique_addon_application (addon_id, application_id),
+    INDEX idx_application_id (application_id)
+);
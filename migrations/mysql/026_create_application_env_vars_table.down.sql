@@ -0,0 +1 @@
+DROP TABLE IF EXISTS application_env_vars;
\ No newline at end of file
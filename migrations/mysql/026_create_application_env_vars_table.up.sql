@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS application_env_vars (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    application_id INT NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    value TEXT NOT NULL, -- encrypted with the secrets keyring when secret is set
+    secret BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    FOREIGN KEY (application_id) REFERENCES applications (id) ON DELETE CASCADE,
+    UNIQUE KEY unique_application_env_var (application_id, name)
+);
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS application_environments;
\ No newline at end of file
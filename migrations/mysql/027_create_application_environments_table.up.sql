@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS application_environments (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    application_id INT NOT NULL,
+    name VARCHAR(63) NOT NULL,
+    branch VARCHAR(100),
+    tier VARCHAR(50) NOT NULL,
+    endpoints JSON,
+    is_default BOOLEAN NOT NULL DEFAULT FALSE, -- mirrors the application's own branch, tier and endpoints
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    FOREIGN KEY (application_id) REFERENCES applications (id) ON DELETE CASCADE,
+    UNIQUE KEY unique_application_environment (application_id, name)
+);
@@ -0,0 +1 @@
+DELETE FROM application_environments WHERE is_default;
\ No newline at end of file
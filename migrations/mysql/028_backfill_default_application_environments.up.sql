@@ -0,0 +1 @@
+INSERT INTO application_environments (application_id, name, branch, tier, endpoints, is_default) SELECT id, 'production', github_branch, tier, endpoints, TRUE FROM applications;
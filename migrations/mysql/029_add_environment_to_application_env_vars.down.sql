@@ -0,0 +1,2 @@
+-- Variables scoped to an environment are deleted by the down migration of 030 first
+ALTER TABLE application_env_vars DROP FOREIGN KEY fk_application_env_vars_environment, DROP INDEX unique_application_environment_env_var, DROP COLUMN environment_id, ADD UNIQUE KEY unique_application_env_var (application_id, name);
\ No newline at end of file
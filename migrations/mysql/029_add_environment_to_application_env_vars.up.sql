@@ -0,0 +1 @@
+ALTER TABLE application_env_vars ADD COLUMN environment_id INT NULL AFTER application_id, ADD CONSTRAINT fk_application_env_vars_environment FOREIGN KEY (environment_id) REFERENCES application_environments (id) ON DELETE CASCADE, DROP INDEX unique_application_env_var, ADD UNIQUE KEY unique_application_environment_env_var (application_id, environment_id, name);
@@ -0,0 +1,2 @@
+-- Environment-scoped variables cannot be kept once env vars are no longer scoped by environment
+DELETE FROM application_env_vars WHERE environment_id IS NOT NULL;
\ No newline at end of file
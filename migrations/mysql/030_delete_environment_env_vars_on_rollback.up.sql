@@ -0,0 +1,2 @@
+-- Nothing to do; the down migration removes variables scoped to an environment so the unique key of 029 can be restored
+DO 0;
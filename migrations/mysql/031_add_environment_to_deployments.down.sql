@@ -0,0 +1 @@
+ALTER TABLE deployments DROP INDEX idx_environment_id, DROP COLUMN environment_name, DROP COLUMN environment_id;
\ No newline at end of file
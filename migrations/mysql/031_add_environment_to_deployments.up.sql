@@ -0,0 +1 @@
+ALTER TABLE deployments ADD COLUMN environment_id INT NULL AFTER application_name, ADD COLUMN environment_name VARCHAR(63) NULL AFTER environment_id, ADD INDEX idx_environment_id (environment_id);
@@ -0,0 +1 @@
+UPDATE deployments d JOIN application_environments e ON e.id = d.environment_id AND e.is_default SET d.environment_id = NULL, d.environment_name = NULL;
\ No newline at end of file
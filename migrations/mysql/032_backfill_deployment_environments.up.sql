@@ -0,0 +1 @@
+UPDATE deployments d JOIN application_environments e ON e.application_id = d.application_id AND e.is_default SET d.environment_id = e.id, d.environment_name = e.name;
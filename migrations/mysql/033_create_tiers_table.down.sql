@@ -0,0 +1 @@
+DROP TABLE IF EXISTS tiers;
\ No newline at end of file
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS tiers (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(50) NOT NULL UNIQUE,
+    cpu_millicores INT NOT NULL, -- per replica
+    memory_mib INT NOT NULL, -- per replica
+    min_replicas INT NOT NULL DEFAULT 1,
+    max_replicas INT NOT NULL DEFAULT 1,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+);
@@ -0,0 +1 @@
+DELETE FROM tiers WHERE name IN ('x3.micro', 'x3.small', 'x3.medium', 'x3.large');
\ No newline at end of file
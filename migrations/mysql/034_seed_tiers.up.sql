@@ -0,0 +1 @@
+INSERT IGNORE INTO tiers (name, cpu_millicores, memory_mib, min_replicas, max_replicas) VALUES ('x3.micro', 250, 256, 1, 1), ('x3.small', 500, 512, 1, 2), ('x3.medium', 1000, 1024, 1, 3), ('x3.large', 2000, 2048, 1, 4);
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS project_quotas;
\ No newline at end of file
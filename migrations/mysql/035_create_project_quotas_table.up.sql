@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS project_quotas (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    project_id INT NOT NULL UNIQUE,
+    cpu_millicores INT NOT NULL,
+    memory_mib INT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    FOREIGN KEY (project_id) REFERENCES projects (id) ON DELETE CASCADE
+);
@@ -0,0 +1 @@
+ALTER TABLE application_environments DROP INDEX unique_hostname, DROP COLUMN hostname;
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE application_environments ADD COLUMN hostname VARCHAR(255) NULL AFTER endpoints, ADD UNIQUE KEY unique_hostname (hostname);
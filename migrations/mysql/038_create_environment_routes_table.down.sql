@@ -0,0 +1 @@
+DROP TABLE IF EXISTS environment_routes;
\ No newline at end of file
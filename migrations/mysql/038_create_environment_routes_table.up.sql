@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS environment_routes (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    environment_id INT NOT NULL,
+    host VARCHAR(255) NOT NULL, -- the environment's platform hostname for path-only routes
+    path VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    FOREIGN KEY (environment_id) REFERENCES application_environments (id) ON DELETE CASCADE,
+    UNIQUE KEY unique_host_path (host, path)
+);
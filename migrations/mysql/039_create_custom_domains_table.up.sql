@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS custom_domains (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    application_id INT NOT NULL,
+    environment_id INT NOT NULL,
+    domain VARCHAR(253) NOT NULL,
+    port INT NOT NULL, -- the endpoint the domain is served by
+    token VARCHAR(64) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending', -- pending, verified, failed
+    last_error TEXT,
+    checked_at TIMESTAMP NULL,
+    verified_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    FOREIGN KEY (application_id) REFERENCES applications (id) ON DELETE CASCADE,
+    FOREIGN KEY (environment_id) REFERENCES application_environments (id) ON DELETE CASCADE,
+    UNIQUE KEY unique_environment_domain (environment_id, domain),
+    INDEX idx_domain (domain)
+);
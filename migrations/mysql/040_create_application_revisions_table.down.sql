@@ -0,0 +1 @@
+DROP TABLE IF EXISTS application_revisions;
\ No newline at end of file
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS application_revisions (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    application_id INT NOT NULL,
+    number INT NOT NULL, -- counts from 1 per application
+    config JSON NOT NULL,
+    rollback_of INT NULL, -- the revision number a rollback restored
+    created_by INT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    FOREIGN KEY (application_id) REFERENCES applications (id) ON DELETE CASCADE,
+    FOREIGN KEY (created_by) REFERENCES users (id) ON DELETE SET NULL,
+    UNIQUE KEY unique_application_number (application_id, number)
+);
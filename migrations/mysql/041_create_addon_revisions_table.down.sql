@@ -0,0 +1 @@
+DROP TABLE IF EXISTS addon_revisions;
\ No newline at end of file
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS addon_revisions (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    addon_id INT NOT NULL,
+    number INT NOT NULL, -- counts from 1 per addon
+    config JSON NOT NULL,
+    created_by INT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    FOREIGN KEY (addon_id) REFERENCES addons (id) ON DELETE CASCADE,
+    FOREIGN KEY (created_by) REFERENCES users (id) ON DELETE SET NULL,
+    UNIQUE KEY unique_addon_number (addon_id, number)
+);
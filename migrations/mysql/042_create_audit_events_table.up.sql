@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS audit_events (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    actor_id INT NULL, -- kept after the user is deleted so history survives
+    actor_email VARCHAR(255) NULL,
+    action VARCHAR(100) NOT NULL, -- e.g. addon.delete, auth.login
+    resource_type VARCHAR(50) NOT NULL,
+    resource_id VARCHAR(255) NULL,
+    project_id INT NULL, -- kept after the project is deleted so history survives
+    request_id VARCHAR(64) NULL,
+    client_ip VARCHAR(45) NULL,
+    before_snapshot JSON NULL,
+    after_snapshot JSON NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    INDEX idx_project_id (project_id, id),
+    INDEX idx_actor_id (actor_id, id),
+    INDEX idx_resource (resource_type, resource_id)
+);
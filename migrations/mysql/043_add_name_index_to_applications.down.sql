@@ -0,0 +1 @@
+ALTER TABLE applications DROP INDEX idx_project_name;
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE applications ADD INDEX idx_project_name (project_id, name);
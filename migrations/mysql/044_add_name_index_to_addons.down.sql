@@ -0,0 +1 @@
+ALTER TABLE addons DROP INDEX idx_project_name;
\ No newline at end of file
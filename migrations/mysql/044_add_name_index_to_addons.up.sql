@@ -0,0 +1 @@
+ALTER TABLE addons ADD INDEX idx_project_name (project_id, name);
@@ -0,0 +1 @@
+ALTER TABLE outbox_messages DROP INDEX idx_path, DROP COLUMN path;
\ No newline at end of file
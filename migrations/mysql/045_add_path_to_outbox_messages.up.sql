@@ -0,0 +1,2 @@
+-- Dispatches for the same path of a repository are delivered in order
+ALTER TABLE outbox_messages ADD COLUMN path VARCHAR(512) NULL AFTER event_type, ADD INDEX idx_path (owner, repo, path(191));
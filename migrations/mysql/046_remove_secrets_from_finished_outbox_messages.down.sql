@@ -0,0 +1,2 @@
+-- Removed secrets cannot be restored
+DO 0;
\ No newline at end of file
@@ -0,0 +1,2 @@
+-- Secrets of messages already delivered or dead-lettered are no longer needed
+UPDATE outbox_messages SET payload = JSON_REMOVE(payload, '$.secrets') WHERE status IN ('delivered', 'dead');
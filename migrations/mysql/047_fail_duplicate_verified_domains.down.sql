@@ -0,0 +1,2 @@
+-- Attachments that lost their verification are verified again by the next check
+DO 0;
\ No newline at end of file
@@ -0,0 +1,2 @@
+-- Only one attachment of a domain may stay verified before verification is enforced as unique
+UPDATE custom_domains d JOIN custom_domains e ON e.domain = d.domain AND e.status = 'verified' AND e.id < d.id SET d.status = 'failed', d.verified_at = NULL, d.last_error = 'Domain is already verified by another application' WHERE d.status = 'verified';
@@ -0,0 +1 @@
+ALTER TABLE custom_domains DROP INDEX unique_verified_domain, DROP COLUMN verified_domain;
\ No newline at end of file
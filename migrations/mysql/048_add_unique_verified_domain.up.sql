@@ -0,0 +1,2 @@
+-- verified_domain is set only while the domain is verified, so a domain can be verified for a single attachment
+ALTER TABLE custom_domains ADD COLUMN verified_domain VARCHAR(253) GENERATED ALWAYS AS (IF(status = 'verified', domain, NULL)) STORED, ADD UNIQUE KEY unique_verified_domain (verified_domain);
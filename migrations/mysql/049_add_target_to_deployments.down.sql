@@ -0,0 +1 @@
+ALTER TABLE deployments DROP COLUMN target_repo, DROP COLUMN target_owner;
\ No newline at end of file
@@ -0,0 +1,2 @@
+-- The repository the deployment is dispatched to; only its workflow and check runs report the result
+ALTER TABLE deployments ADD COLUMN target_owner VARCHAR(255) NULL AFTER correlation_id, ADD COLUMN target_repo VARCHAR(255) NULL AFTER target_owner;
@@ -0,0 +1 @@
+UPDATE deployments SET target_owner = NULL, target_repo = NULL;
\ No newline at end of file
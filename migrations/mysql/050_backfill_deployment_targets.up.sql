@@ -0,0 +1 @@
+UPDATE deployments d JOIN outbox_messages o ON o.aggregate_type = 'deployment' AND o.aggregate_id = d.id SET d.target_owner = o.owner, d.target_repo = o.repo;